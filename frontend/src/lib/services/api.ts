@@ -0,0 +1,40 @@
+import {
+  ObterEstadoAPI as ObterEstadoAPIGo,
+  AtivarAPI as AtivarAPIGo,
+  DesativarAPI as DesativarAPIGo,
+  GerarNovoToken as GerarNovoTokenGo
+} from '../../wailsjs/wailsjs/go/api/Servidor';
+
+// API HTTP local: só escuta em 127.0.0.1 e exige o token
+export interface EstadoAPI {
+  ativa: boolean;
+  porta: number;
+  token: string;
+  endereco?: string; // Preenchido enquanto a API está escutando
+  erro?: string;     // Por que a API ativa não pôde iniciar
+}
+
+function wailsDisponivel(): boolean {
+  // @ts-ignore
+  return typeof window !== 'undefined' && window.go?.api?.Servidor;
+}
+
+export async function obterEstadoAPI(): Promise<EstadoAPI | null> {
+  if (!wailsDisponivel()) return null;
+  return await ObterEstadoAPIGo() as EstadoAPI;
+}
+
+export async function ativarAPI(porta: number): Promise<EstadoAPI> {
+  if (!wailsDisponivel()) throw new Error('A API local requer o app desktop.');
+  return await AtivarAPIGo(porta) as EstadoAPI;
+}
+
+export async function desativarAPI(): Promise<void> {
+  if (!wailsDisponivel()) return;
+  await DesativarAPIGo();
+}
+
+export async function gerarNovoToken(): Promise<EstadoAPI> {
+  if (!wailsDisponivel()) throw new Error('A API local requer o app desktop.');
+  return await GerarNovoTokenGo() as EstadoAPI;
+}
@@ -1,13 +1,90 @@
 import {
   CriarBackup as CriarBackupGo,
   ListarBackups as ListarBackupsGo,
-  RestaurarBackup as RestaurarBackupGo
+  RestaurarBackup as RestaurarBackupGo,
+  ObterRetencao as ObterRetencaoGo,
+  SalvarRetencao as SalvarRetencaoGo,
+  RestaurarArquivos as RestaurarArquivosGo,
+  DesfazerRestauracao as DesfazerRestauracaoGo,
+  CompararBackup as CompararBackupGo,
+  ExportarDados as ExportarDadosGo,
+  ImportarDados as ImportarDadosGo,
+  ObterAgendamento as ObterAgendamentoGo,
+  SalvarAgendamento as SalvarAgendamentoGo,
+  ListarDestinos as ListarDestinosGo,
+  EscolherPastaDestino as EscolherPastaDestinoGo,
+  AdicionarDestino as AdicionarDestinoGo,
+  RemoverDestino as RemoverDestinoGo,
+  CopiarParaDestinos as CopiarParaDestinosGo,
+  VerificarBackup as VerificarBackupGo,
+  VerificarTodos as VerificarTodosGo,
+  FixarBackup as FixarBackupGo,
+  ArquivosCorrompidos as ArquivosCorrompidosGo,
+  RecuperarArquivo as RecuperarArquivoGo,
+  DescartarCorrompido as DescartarCorrompidoGo
 } from '../../wailsjs/wailsjs/go/handlers/BackupHandler';
 
+export interface VerificacaoBackup {
+  backup: string;
+  integro: boolean;
+  problemas: string[];
+  verificadoEm: string;
+}
+
 export interface BackupInfo {
   nome: string;
   data: string;
   label: string;
+  nota?: string;
+  fixado?: boolean;
+  verificacao?: VerificacaoBackup;
+}
+
+export interface ItemDiferente {
+  id: string;
+  titulo: string;
+  tipo: 'adicionado' | 'removido' | 'alterado';
+  atual?: unknown;
+  backup?: unknown;
+}
+
+export interface DiferencaModulo {
+  arquivo: string;
+  modulo: string;
+  adicionados: number;
+  removidos: number;
+  alterados: number;
+  itens: ItemDiferente[];
+}
+
+export interface PoliticaRetencao {
+  ultimos: number;
+  horas: number;
+  dias: number;
+  semanas: number;
+}
+
+export interface Agendamento {
+  minutos: number;
+}
+
+export interface Destino {
+  id: string;
+  nome: string;
+  pasta: string;
+  retencao: PoliticaRetencao;
+  ultimoBackup?: string;
+  ultimaCopia?: string;
+  erro?: string;
+  erroEm?: string;
+}
+
+// Arquivo de dados ilegível, isolado até ser recuperado de um backup ou
+// descartado; enquanto isso o módulo não grava nada
+export interface ArquivoCorrompido {
+  arquivo: string;
+  quarentena: string;
+  backup?: BackupInfo; // Backup válido mais recente, se houver
 }
 
 function wailsDisponivel(): boolean {
@@ -15,9 +92,14 @@ function wailsDisponivel(): boolean {
   return typeof window !== 'undefined' && window.go?.handlers?.BackupHandler;
 }
 
-export async function criarBackup(): Promise<BackupInfo> {
+export async function criarBackup(nota = '', fixar = false): Promise<BackupInfo> {
   if (!wailsDisponivel()) throw new Error('Backup requer o app desktop.');
-  return await CriarBackupGo() as BackupInfo;
+  return await CriarBackupGo(nota, fixar) as BackupInfo;
+}
+
+export async function fixarBackup(nome: string, fixado: boolean): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Backup requer o app desktop.');
+  await FixarBackupGo(nome, fixado);
 }
 
 export async function listarBackups(): Promise<BackupInfo[]> {
@@ -30,3 +112,102 @@ export async function restaurarBackup(nome: string): Promise<void> {
   if (!wailsDisponivel()) throw new Error('Restauração requer o app desktop.');
   await RestaurarBackupGo(nome);
 }
+
+export async function compararBackup(nome: string): Promise<DiferencaModulo[]> {
+  if (!wailsDisponivel()) return [];
+  const lista = await CompararBackupGo(nome);
+  return (lista as DiferencaModulo[]) ?? [];
+}
+
+export async function restaurarArquivos(nome: string, arquivos: string[]): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Restauração requer o app desktop.');
+  await RestaurarArquivosGo(nome, arquivos);
+}
+
+export async function desfazerRestauracao(): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Restauração requer o app desktop.');
+  await DesfazerRestauracaoGo();
+}
+
+export async function obterRetencao(): Promise<PoliticaRetencao | null> {
+  if (!wailsDisponivel()) return null;
+  return await ObterRetencaoGo() as PoliticaRetencao;
+}
+
+export async function salvarRetencao(politica: PoliticaRetencao): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Retenção requer o app desktop.');
+  await SalvarRetencaoGo(politica as any);
+}
+
+// Retorna o caminho do arquivo gerado, ou '' se o usuário cancelar
+export async function exportarDados(): Promise<string> {
+  if (!wailsDisponivel()) throw new Error('Exportação requer o app desktop.');
+  return await ExportarDadosGo();
+}
+
+// modo 'substituir' troca os dados atuais pelos do arquivo (módulos ausentes
+// nele ficam vazios); 'mesclar' só
+// acrescenta os itens que ainda não existem. Retorna '' se o usuário cancelar.
+export async function importarDados(modo: 'substituir' | 'mesclar'): Promise<string> {
+  if (!wailsDisponivel()) throw new Error('Importação requer o app desktop.');
+  return await ImportarDadosGo(modo);
+}
+
+export async function obterAgendamento(): Promise<Agendamento | null> {
+  if (!wailsDisponivel()) return null;
+  return await ObterAgendamentoGo() as Agendamento;
+}
+
+export async function salvarAgendamento(agendamento: Agendamento): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Agendamento requer o app desktop.');
+  await SalvarAgendamentoGo(agendamento as any);
+}
+
+export async function listarDestinos(): Promise<Destino[]> {
+  if (!wailsDisponivel()) return [];
+  return (await ListarDestinosGo() as Destino[]) ?? [];
+}
+
+// Abre o seletor de pastas e adiciona a escolhida; null se o usuário cancelar
+export async function adicionarDestino(): Promise<Destino | null> {
+  if (!wailsDisponivel()) throw new Error('Destinos de backup requerem o app desktop.');
+  const pasta = await EscolherPastaDestinoGo();
+  if (!pasta) return null;
+  return await AdicionarDestinoGo('', pasta) as Destino;
+}
+
+export async function removerDestino(id: string): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Destinos de backup requerem o app desktop.');
+  await RemoverDestinoGo(id);
+}
+
+export async function copiarParaDestinos(): Promise<Destino[]> {
+  if (!wailsDisponivel()) return [];
+  return (await CopiarParaDestinosGo() as Destino[]) ?? [];
+}
+
+export async function verificarBackup(nome: string): Promise<VerificacaoBackup> {
+  if (!wailsDisponivel()) throw new Error('Verificação requer o app desktop.');
+  return await VerificarBackupGo(nome) as VerificacaoBackup;
+}
+
+export async function verificarTodos(): Promise<VerificacaoBackup[]> {
+  if (!wailsDisponivel()) return [];
+  return (await VerificarTodosGo() as VerificacaoBackup[]) ?? [];
+}
+
+export async function arquivosCorrompidos(): Promise<ArquivoCorrompido[]> {
+  if (!wailsDisponivel()) return [];
+  return (await ArquivosCorrompidosGo() as ArquivoCorrompido[]) ?? [];
+}
+
+// nome vazio usa o backup válido mais recente; retorna o backup usado
+export async function recuperarArquivo(arquivo: string, nome = ''): Promise<BackupInfo> {
+  if (!wailsDisponivel()) throw new Error('Recuperação requer o app desktop.');
+  return await RecuperarArquivoGo(arquivo, nome) as BackupInfo;
+}
+
+export async function descartarCorrompido(arquivo: string): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Recuperação requer o app desktop.');
+  await DescartarCorrompidoGo(arquivo);
+}
@@ -4,7 +4,18 @@ import {
   CarregarEventos as CarregarEventosGo,
   AdicionarEvento as AdicionarEventoGo,
   AtualizarEvento as AtualizarEventoGo,
-  DeletarEvento as DeletarEventoGo
+  DeletarEvento as DeletarEventoGo,
+  ListarOcorrencias as ListarOcorrenciasGo,
+  EventosNoIntervalo as EventosNoIntervaloGo,
+  EventosDoDia as EventosDoDiaGo,
+  BuscarEventos as BuscarEventosGo,
+  ExportarICS as ExportarICSGo,
+  ImportarICS as ImportarICSGo,
+  ListarAssinaturas as ListarAssinaturasGo,
+  AdicionarAssinatura as AdicionarAssinaturaGo,
+  RemoverAssinatura as RemoverAssinaturaGo,
+  AtualizarAssinaturas as AtualizarAssinaturasGo,
+  AdiarLembrete as AdiarLembreteGo
 } from '../../wailsjs/wailsjs/go/handlers/CalendarioHandler';
 
 export interface Evento {
@@ -15,6 +26,50 @@ export interface Evento {
   descricao: string;
   cor: string;       // Hex color
   createdAt: string;
+  recorrencia?: Recorrencia;
+  uid?: string;      // UID de origem, quando importado de um .ics
+  lembretes?: number[];  // minutos de antecedência de cada aviso (0 = na hora)
+}
+
+// Subconjunto do RRULE (RFC 5545); a data do evento é a primeira ocorrência
+export interface Recorrencia {
+  frequencia: 'diaria' | 'semanal' | 'mensal' | 'anual';
+  intervalo?: number;     // a cada N dias/semanas/meses/anos
+  diasSemana?: string[];  // MO..SU; no mensal aceita ordinal (2MO, -1FR)
+  ate?: string;           // YYYY-MM-DD, inclusivo
+  vezes?: number;         // número de ocorrências
+  excecoes?: string[];    // datas puladas (YYYY-MM-DD)
+}
+
+// Um evento numa data: data é a da ocorrência, id é o da série
+export interface Ocorrencia extends Evento {
+  recorrente?: boolean;
+  somenteLeitura?: boolean;  // vem de um calendário assinado
+  calendario?: string;       // nome do calendário assinado
+}
+
+// Aviso de uma ocorrência, recebido pelo evento "calendario:lembrete"
+export interface Lembrete {
+  id: string;
+  eventoId: string;
+  titulo: string;
+  data: string;      // data da ocorrência
+  hora: string;
+  cor: string;
+  minutos: number;   // antecedência
+}
+
+// Calendário externo (.ics) mostrado junto com os eventos, só para leitura
+export interface Assinatura {
+  id: string;
+  nome: string;
+  origem: string;     // caminho de um .ics ou URL http(s)/webcal
+  cor: string;
+  minutos?: number;   // intervalo de atualização (0 = a cada hora)
+  createdAt: string;
+  atualizadoEm?: string;
+  eventos: number;
+  erro?: string;
 }
 
 let wailsAvailable = false;
@@ -140,6 +195,174 @@ export async function DeletarEvento(id: string): Promise<void> {
   await SalvarEventos(eventos);
 }
 
+export async function ListarOcorrencias(inicio: string, fim: string): Promise<Ocorrencia[]> {
+  if (wailsAvailable) {
+    try {
+      return await ListarOcorrenciasGo(inicio, fim) as Ocorrencia[];
+    } catch (err) {
+      console.error('Erro ao listar ocorrências no Wails:', err);
+    }
+  }
+
+  // Fallback: sem o backend, só os eventos sem repetição
+  const eventos = await CarregarEventos();
+  return eventos
+    .filter(e => !e.recorrencia && e.data >= inicio && e.data <= fim)
+    .sort((a, b) => (a.data + a.hora).localeCompare(b.data + b.hora));
+}
+
+// Eventos do workspace num período (sem os calendários assinados), em ordem
+// cronológica
+export async function EventosNoIntervalo(inicio: string, fim: string): Promise<Ocorrencia[]> {
+  if (wailsAvailable) {
+    try {
+      return await EventosNoIntervaloGo(inicio, fim) as Ocorrencia[];
+    } catch (err) {
+      console.error('Erro ao consultar eventos no Wails:', err);
+    }
+  }
+  return await ListarOcorrencias(inicio, fim);
+}
+
+export async function EventosDoDia(data: string): Promise<Ocorrencia[]> {
+  if (wailsAvailable) {
+    try {
+      return await EventosDoDiaGo(data) as Ocorrencia[];
+    } catch (err) {
+      console.error('Erro ao consultar eventos no Wails:', err);
+    }
+  }
+  return await ListarOcorrencias(data, data);
+}
+
+// Minúsculas e sem acentos, como na busca do backend
+function normalizarBusca(texto: string): string {
+  return texto.normalize('NFD').replace(/\p{Mn}/gu, '').toLowerCase();
+}
+
+// Eventos com todas as palavras no título ou na descrição, em ordem cronológica
+export async function BuscarEventos(texto: string): Promise<Evento[]> {
+  if (wailsAvailable) {
+    try {
+      return await BuscarEventosGo(texto) as Evento[];
+    } catch (err) {
+      console.error('Erro ao buscar eventos no Wails:', err);
+    }
+  }
+
+  // Fallback
+  const termos = normalizarBusca(texto).split(/\s+/).filter(Boolean);
+  const eventos = await CarregarEventos();
+  return eventos
+    .filter(e => {
+      const alvo = normalizarBusca(`${e.titulo}\n${e.descricao}`);
+      return termos.every(t => alvo.includes(t));
+    })
+    .sort((a, b) => (a.data + a.hora.padStart(5, '0')).localeCompare(b.data + b.hora.padStart(5, '0')));
+}
+
+export interface ResultadoICS {
+  novos: number;
+  atualizados: number;
+  avisos?: string[];
+}
+
+// Exporta os eventos para um .ics; retorna o caminho ('' se cancelado)
+export async function ExportarICS(): Promise<string> {
+  if (!wailsAvailable) throw new Error('Exportar .ics requer o app desktop.');
+  return await ExportarICSGo();
+}
+
+// Importa um .ics escolhido pelo usuário; null se cancelado
+export async function ImportarICS(): Promise<ResultadoICS | null> {
+  if (!wailsAvailable) throw new Error('Importar .ics requer o app desktop.');
+  const resultado = await ImportarICSGo() as ResultadoICS;
+  return resultado.novos || resultado.atualizados || resultado.avisos?.length ? resultado : null;
+}
+
+export async function ListarAssinaturas(): Promise<Assinatura[]> {
+  if (!wailsAvailable) return [];
+  return await ListarAssinaturasGo() as Assinatura[];
+}
+
+export async function AdicionarAssinatura(origem: string, nome: string, cor: string): Promise<Assinatura> {
+  if (!wailsAvailable) throw new Error('Assinar calendários requer o app desktop.');
+  return await AdicionarAssinaturaGo({ id: '', nome, origem, cor, createdAt: '' } as any) as Assinatura;
+}
+
+export async function RemoverAssinatura(id: string): Promise<void> {
+  if (!wailsAvailable) return;
+  await RemoverAssinaturaGo(id);
+}
+
+export async function AtualizarAssinaturas(): Promise<Assinatura[]> {
+  if (!wailsAvailable) return [];
+  return await AtualizarAssinaturasGo() as Assinatura[];
+}
+
+// Mostra o lembrete de novo daqui a alguns minutos
+export async function AdiarLembrete(id: string, minutos: number): Promise<void> {
+  if (!wailsAvailable) return;
+  await AdiarLembreteGo(id, minutos);
+}
+
+// Antecedências oferecidas no formulário do evento, em minutos
+export const ANTECEDENCIAS = [
+  { minutos: 0, nome: 'na hora' },
+  { minutos: 10, nome: '10 min' },
+  { minutos: 30, nome: '30 min' },
+  { minutos: 60, nome: '1 h' },
+  { minutos: 120, nome: '2 h' },
+  { minutos: 1440, nome: '1 dia' },
+  { minutos: 10080, nome: '1 semana' },
+];
+
+// Descrição curta da antecedência, ex: "10 min antes", "1 dia antes"
+export function descreverAntecedencia(minutos: number): string {
+  if (minutos === 0) return 'na hora';
+  const conhecida = ANTECEDENCIAS.find(a => a.minutos === minutos);
+  if (conhecida) return `${conhecida.nome} antes`;
+  if (minutos % 1440 === 0) return `${minutos / 1440} dias antes`;
+  if (minutos % 60 === 0) return `${minutos / 60} h antes`;
+  return `${minutos} min antes`;
+}
+
+// Dias da semana no formato do RRULE, de segunda a domingo
+export const DIAS_SEMANA = [
+  { id: 'MO', nome: 'seg' },
+  { id: 'TU', nome: 'ter' },
+  { id: 'WE', nome: 'qua' },
+  { id: 'TH', nome: 'qui' },
+  { id: 'FR', nome: 'sex' },
+  { id: 'SA', nome: 'sáb' },
+  { id: 'SU', nome: 'dom' },
+];
+
+// Descrição curta da regra, ex: "a cada 2 semanas (seg, qui), 10 vezes"
+export function descreverRecorrencia(r: Recorrencia): string {
+  const n = r.intervalo && r.intervalo > 1 ? r.intervalo : 1;
+  const unidades = {
+    diaria: ['todo dia', 'dias'],
+    semanal: ['toda semana', 'semanas'],
+    mensal: ['todo mês', 'meses'],
+    anual: ['todo ano', 'anos'],
+  }[r.frequencia];
+  let texto = n === 1 ? unidades[0] : `a cada ${n} ${unidades[1]}`;
+  if (r.diasSemana?.length) {
+    const nomes = r.diasSemana.map(d => {
+      const dia = DIAS_SEMANA.find(x => d.endsWith(x.id));
+      const ordinal = d.slice(0, -2);
+      if (!dia) return d;
+      if (ordinal === '-1') return `última ${dia.nome}`;
+      return ordinal ? `${ordinal}ª ${dia.nome}` : dia.nome;
+    });
+    texto += ` (${nomes.join(', ')})`;
+  }
+  if (r.ate) texto += `, até ${r.ate.split('-').reverse().join('/')}`;
+  if (r.vezes) texto += `, ${r.vezes} vezes`;
+  return texto;
+}
+
 // Cores disponíveis para eventos
 export const CORES_EVENTO = [
   { nome: 'Rosa', cor: '#ec4899' },
@@ -0,0 +1,39 @@
+import {
+  EstadoCripto as EstadoCriptoGo,
+  Desbloquear as DesbloquearGo,
+  AtivarCriptografia as AtivarCriptografiaGo,
+  DesativarCriptografia as DesativarCriptografiaGo
+} from '../../wailsjs/wailsjs/go/app/App';
+
+// '' = sem criptografia, 'backups' = só backups e exportações, 'dados' = tudo
+export type ModoCripto = '' | 'backups' | 'dados';
+
+export interface EstadoCripto {
+  modo: ModoCripto;
+  bloqueado: boolean;
+}
+
+function wailsDisponivel(): boolean {
+  // @ts-ignore
+  return typeof window !== 'undefined' && window.go?.app?.App;
+}
+
+export async function estadoCripto(): Promise<EstadoCripto> {
+  if (!wailsDisponivel()) return { modo: '', bloqueado: false };
+  return await EstadoCriptoGo() as EstadoCripto;
+}
+
+export async function desbloquear(senha: string): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Criptografia requer o app desktop.');
+  await DesbloquearGo(senha);
+}
+
+export async function ativarCriptografia(modo: 'backups' | 'dados', senha: string): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Criptografia requer o app desktop.');
+  await AtivarCriptografiaGo(modo, senha);
+}
+
+export async function desativarCriptografia(senha: string): Promise<void> {
+  if (!wailsDisponivel()) throw new Error('Criptografia requer o app desktop.');
+  await DesativarCriptografiaGo(senha);
+}
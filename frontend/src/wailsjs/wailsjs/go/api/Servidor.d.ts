@@ -0,0 +1,16 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {api} from '../models';
+import {context} from '../models';
+
+export function AtivarAPI(arg1:number):Promise<api.EstadoAPI>;
+
+export function DesativarAPI():Promise<void>;
+
+export function GerarNovoToken():Promise<api.EstadoAPI>;
+
+export function ObterEstadoAPI():Promise<api.EstadoAPI>;
+
+export function Shutdown(arg1:context.Context):Promise<void>;
+
+export function Startup(arg1:context.Context):Promise<void>;
@@ -0,0 +1,27 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function AtivarAPI(arg1) {
+  return window['go']['api']['Servidor']['AtivarAPI'](arg1);
+}
+
+export function DesativarAPI() {
+  return window['go']['api']['Servidor']['DesativarAPI']();
+}
+
+export function GerarNovoToken() {
+  return window['go']['api']['Servidor']['GerarNovoToken']();
+}
+
+export function ObterEstadoAPI() {
+  return window['go']['api']['Servidor']['ObterEstadoAPI']();
+}
+
+export function Shutdown(arg1) {
+  return window['go']['api']['Servidor']['Shutdown'](arg1);
+}
+
+export function Startup(arg1) {
+  return window['go']['api']['Servidor']['Startup'](arg1);
+}
@@ -1,5 +1,35 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {storage} from '../models';
+import {app} from '../models';
 import {context} from '../models';
 
+export function AssetsDir():Promise<string>;
+
+export function AtivarCriptografia(arg1:string,arg2:string):Promise<void>;
+
+export function Backend():Promise<storage.Backend>;
+
+export function CriarWorkspace(arg1:string):Promise<app.Workspace>;
+
+export function DeletarWorkspace(arg1:string):Promise<void>;
+
+export function DesativarCriptografia(arg1:string):Promise<void>;
+
+export function Desbloquear(arg1:string):Promise<void>;
+
+export function EstadoCripto():Promise<app.EstadoCripto>;
+
+export function ListarWorkspaces():Promise<Array<app.Workspace>>;
+
+export function Registrar(arg1:Array<app.Modulo>):Promise<void>;
+
+export function RenomearWorkspace(arg1:string,arg2:string):Promise<void>;
+
+export function Shutdown(arg1:context.Context):Promise<void>;
+
 export function Startup(arg1:context.Context):Promise<void>;
+
+export function TrocarWorkspace(arg1:string):Promise<void>;
+
+export function WorkspaceAtivo():Promise<app.Workspace>;
@@ -2,6 +2,62 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AssetsDir() {
+  return window['go']['app']['App']['AssetsDir']();
+}
+
+export function AtivarCriptografia(arg1, arg2) {
+  return window['go']['app']['App']['AtivarCriptografia'](arg1, arg2);
+}
+
+export function Backend() {
+  return window['go']['app']['App']['Backend']();
+}
+
+export function CriarWorkspace(arg1) {
+  return window['go']['app']['App']['CriarWorkspace'](arg1);
+}
+
+export function DeletarWorkspace(arg1) {
+  return window['go']['app']['App']['DeletarWorkspace'](arg1);
+}
+
+export function DesativarCriptografia(arg1) {
+  return window['go']['app']['App']['DesativarCriptografia'](arg1);
+}
+
+export function Desbloquear(arg1) {
+  return window['go']['app']['App']['Desbloquear'](arg1);
+}
+
+export function EstadoCripto() {
+  return window['go']['app']['App']['EstadoCripto']();
+}
+
+export function ListarWorkspaces() {
+  return window['go']['app']['App']['ListarWorkspaces']();
+}
+
+export function Registrar(arg1) {
+  return window['go']['app']['App']['Registrar'](arg1);
+}
+
+export function RenomearWorkspace(arg1, arg2) {
+  return window['go']['app']['App']['RenomearWorkspace'](arg1, arg2);
+}
+
+export function Shutdown(arg1) {
+  return window['go']['app']['App']['Shutdown'](arg1);
+}
+
 export function Startup(arg1) {
   return window['go']['app']['App']['Startup'](arg1);
 }
+
+export function TrocarWorkspace(arg1) {
+  return window['go']['app']['App']['TrocarWorkspace'](arg1);
+}
+
+export function WorkspaceAtivo() {
+  return window['go']['app']['App']['WorkspaceAtivo']();
+}
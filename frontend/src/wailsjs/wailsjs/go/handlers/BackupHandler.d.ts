@@ -1,12 +1,69 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {handlers} from '../models';
+import {storage} from '../models';
 import {context} from '../models';
 
-export function CriarBackup():Promise<handlers.BackupInfo>;
+export function AdicionarDestino(arg1:string,arg2:string):Promise<handlers.Destino>;
+
+export function Apontar(arg1:string,arg2:storage.Backend):Promise<void>;
+
+export function ApontarCifrado(arg1:string,arg2:storage.Backend,arg3:storage.Cifra):Promise<void>;
+
+export function ArquivosCorrompidos():Promise<Array<handlers.ArquivoCorrompido>>;
+
+export function AtualizarDestino(arg1:handlers.Destino):Promise<void>;
+
+export function CompararBackup(arg1:string):Promise<Array<handlers.DiferencaModulo>>;
+
+export function CopiarParaDestinos():Promise<Array<handlers.Destino>>;
+
+export function CriarBackup(arg1:string,arg2:boolean):Promise<handlers.BackupInfo>;
+
+export function DescartarCorrompido(arg1:string):Promise<void>;
+
+export function DesfazerRestauracao():Promise<void>;
+
+export function EscolherPastaDestino():Promise<string>;
+
+export function ExportarArquivo(arg1:string):Promise<void>;
+
+export function ExportarDados():Promise<string>;
+
+export function FixarBackup(arg1:string,arg2:boolean):Promise<void>;
+
+export function ImportarArquivo(arg1:string,arg2:string):Promise<void>;
+
+export function ImportarDados(arg1:string):Promise<string>;
 
 export function ListarBackups():Promise<Array<handlers.BackupInfo>>;
 
+export function ListarDestinos():Promise<Array<handlers.Destino>>;
+
+export function ObterAgendamento():Promise<handlers.Agendamento>;
+
+export function ObterRetencao():Promise<handlers.PoliticaRetencao>;
+
+export function RecuperarArquivo(arg1:string,arg2:string):Promise<handlers.BackupInfo>;
+
+export function RemoverDestino(arg1:string):Promise<void>;
+
+export function RestaurarArquivos(arg1:string,arg2:Array<string>):Promise<void>;
+
 export function RestaurarBackup(arg1:string):Promise<void>;
 
+export function SalvarAgendamento(arg1:handlers.Agendamento):Promise<void>;
+
+export function SalvarRetencao(arg1:handlers.PoliticaRetencao):Promise<void>;
+
+export function Shutdown(arg1:context.Context):Promise<void>;
+
 export function Startup(arg1:context.Context):Promise<void>;
+
+export function UltimaRestauracao():Promise<handlers.InfoRestauracao>;
+
+export function UsarCifra(arg1:storage.Cifra):Promise<void>;
+
+export function VerificarBackup(arg1:string):Promise<handlers.VerificacaoBackup>;
+
+export function VerificarTodos():Promise<Array<handlers.VerificacaoBackup>>;
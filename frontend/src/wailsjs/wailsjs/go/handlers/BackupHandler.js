@@ -2,18 +2,130 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function CriarBackup() {
-  return window['go']['handlers']['BackupHandler']['CriarBackup']();
+export function AdicionarDestino(arg1, arg2) {
+  return window['go']['handlers']['BackupHandler']['AdicionarDestino'](arg1, arg2);
+}
+
+export function Apontar(arg1, arg2) {
+  return window['go']['handlers']['BackupHandler']['Apontar'](arg1, arg2);
+}
+
+export function ApontarCifrado(arg1, arg2, arg3) {
+  return window['go']['handlers']['BackupHandler']['ApontarCifrado'](arg1, arg2, arg3);
+}
+
+export function ArquivosCorrompidos() {
+  return window['go']['handlers']['BackupHandler']['ArquivosCorrompidos']();
+}
+
+export function AtualizarDestino(arg1) {
+  return window['go']['handlers']['BackupHandler']['AtualizarDestino'](arg1);
+}
+
+export function CompararBackup(arg1) {
+  return window['go']['handlers']['BackupHandler']['CompararBackup'](arg1);
+}
+
+export function CopiarParaDestinos() {
+  return window['go']['handlers']['BackupHandler']['CopiarParaDestinos']();
+}
+
+export function CriarBackup(arg1, arg2) {
+  return window['go']['handlers']['BackupHandler']['CriarBackup'](arg1, arg2);
+}
+
+export function DescartarCorrompido(arg1) {
+  return window['go']['handlers']['BackupHandler']['DescartarCorrompido'](arg1);
+}
+
+export function DesfazerRestauracao() {
+  return window['go']['handlers']['BackupHandler']['DesfazerRestauracao']();
+}
+
+export function EscolherPastaDestino() {
+  return window['go']['handlers']['BackupHandler']['EscolherPastaDestino']();
+}
+
+export function ExportarArquivo(arg1) {
+  return window['go']['handlers']['BackupHandler']['ExportarArquivo'](arg1);
+}
+
+export function ExportarDados() {
+  return window['go']['handlers']['BackupHandler']['ExportarDados']();
+}
+
+export function FixarBackup(arg1, arg2) {
+  return window['go']['handlers']['BackupHandler']['FixarBackup'](arg1, arg2);
+}
+
+export function ImportarArquivo(arg1, arg2) {
+  return window['go']['handlers']['BackupHandler']['ImportarArquivo'](arg1, arg2);
+}
+
+export function ImportarDados(arg1) {
+  return window['go']['handlers']['BackupHandler']['ImportarDados'](arg1);
 }
 
 export function ListarBackups() {
   return window['go']['handlers']['BackupHandler']['ListarBackups']();
 }
 
+export function ListarDestinos() {
+  return window['go']['handlers']['BackupHandler']['ListarDestinos']();
+}
+
+export function ObterAgendamento() {
+  return window['go']['handlers']['BackupHandler']['ObterAgendamento']();
+}
+
+export function ObterRetencao() {
+  return window['go']['handlers']['BackupHandler']['ObterRetencao']();
+}
+
+export function RecuperarArquivo(arg1, arg2) {
+  return window['go']['handlers']['BackupHandler']['RecuperarArquivo'](arg1, arg2);
+}
+
+export function RemoverDestino(arg1) {
+  return window['go']['handlers']['BackupHandler']['RemoverDestino'](arg1);
+}
+
+export function RestaurarArquivos(arg1, arg2) {
+  return window['go']['handlers']['BackupHandler']['RestaurarArquivos'](arg1, arg2);
+}
+
 export function RestaurarBackup(arg1) {
   return window['go']['handlers']['BackupHandler']['RestaurarBackup'](arg1);
 }
 
+export function SalvarAgendamento(arg1) {
+  return window['go']['handlers']['BackupHandler']['SalvarAgendamento'](arg1);
+}
+
+export function SalvarRetencao(arg1) {
+  return window['go']['handlers']['BackupHandler']['SalvarRetencao'](arg1);
+}
+
+export function Shutdown(arg1) {
+  return window['go']['handlers']['BackupHandler']['Shutdown'](arg1);
+}
+
 export function Startup(arg1) {
   return window['go']['handlers']['BackupHandler']['Startup'](arg1);
 }
+
+export function UltimaRestauracao() {
+  return window['go']['handlers']['BackupHandler']['UltimaRestauracao']();
+}
+
+export function UsarCifra(arg1) {
+  return window['go']['handlers']['BackupHandler']['UsarCifra'](arg1);
+}
+
+export function VerificarBackup(arg1) {
+  return window['go']['handlers']['BackupHandler']['VerificarBackup'](arg1);
+}
+
+export function VerificarTodos() {
+  return window['go']['handlers']['BackupHandler']['VerificarTodos']();
+}
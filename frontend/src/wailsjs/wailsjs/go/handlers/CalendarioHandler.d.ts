@@ -1,16 +1,51 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {handlers} from '../models';
+import {storage} from '../models';
 import {context} from '../models';
 
+export function AdiarLembrete(arg1:string,arg2:number):Promise<void>;
+
+export function AdicionarAssinatura(arg1:handlers.Assinatura):Promise<handlers.EstadoAssinatura>;
+
 export function AdicionarEvento(arg1:handlers.Evento):Promise<void>;
 
+export function Apontar(arg1:string,arg2:storage.Backend):Promise<void>;
+
+export function AtualizarAssinaturas():Promise<Array<handlers.EstadoAssinatura>>;
+
 export function AtualizarEvento(arg1:handlers.Evento):Promise<void>;
 
+export function BuscarEventos(arg1:string):Promise<Array<handlers.Evento>>;
+
 export function CarregarEventos():Promise<Array<handlers.Evento>>;
 
 export function DeletarEvento(arg1:string):Promise<void>;
 
+export function EventosDoDia(arg1:string):Promise<Array<handlers.Ocorrencia>>;
+
+export function EventosNoIntervalo(arg1:string,arg2:string):Promise<Array<handlers.Ocorrencia>>;
+
+export function ExportarArquivoICS(arg1:string):Promise<void>;
+
+export function ExportarICS():Promise<string>;
+
+export function ImportarArquivoICS(arg1:string):Promise<handlers.ResultadoICS>;
+
+export function ImportarICS():Promise<handlers.ResultadoICS>;
+
+export function ListarAssinaturas():Promise<Array<handlers.EstadoAssinatura>>;
+
+export function ListarOcorrencias(arg1:string,arg2:string):Promise<Array<handlers.Ocorrencia>>;
+
+export function ProximosLembretes(arg1:number):Promise<Array<handlers.Lembrete>>;
+
+export function PularOcorrencia(arg1:string,arg2:string):Promise<void>;
+
+export function RemoverAssinatura(arg1:string):Promise<void>;
+
 export function SalvarEventos(arg1:Array<handlers.Evento>):Promise<void>;
 
+export function Shutdown(arg1:context.Context):Promise<void>;
+
 export function Startup(arg1:context.Context):Promise<void>;
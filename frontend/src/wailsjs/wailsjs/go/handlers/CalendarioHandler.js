@@ -2,14 +2,34 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AdiarLembrete(arg1, arg2) {
+  return window['go']['handlers']['CalendarioHandler']['AdiarLembrete'](arg1, arg2);
+}
+
+export function AdicionarAssinatura(arg1) {
+  return window['go']['handlers']['CalendarioHandler']['AdicionarAssinatura'](arg1);
+}
+
 export function AdicionarEvento(arg1) {
   return window['go']['handlers']['CalendarioHandler']['AdicionarEvento'](arg1);
 }
 
+export function Apontar(arg1, arg2) {
+  return window['go']['handlers']['CalendarioHandler']['Apontar'](arg1, arg2);
+}
+
+export function AtualizarAssinaturas() {
+  return window['go']['handlers']['CalendarioHandler']['AtualizarAssinaturas']();
+}
+
 export function AtualizarEvento(arg1) {
   return window['go']['handlers']['CalendarioHandler']['AtualizarEvento'](arg1);
 }
 
+export function BuscarEventos(arg1) {
+  return window['go']['handlers']['CalendarioHandler']['BuscarEventos'](arg1);
+}
+
 export function CarregarEventos() {
   return window['go']['handlers']['CalendarioHandler']['CarregarEventos']();
 }
@@ -18,10 +38,58 @@ export function DeletarEvento(arg1) {
   return window['go']['handlers']['CalendarioHandler']['DeletarEvento'](arg1);
 }
 
+export function EventosDoDia(arg1) {
+  return window['go']['handlers']['CalendarioHandler']['EventosDoDia'](arg1);
+}
+
+export function EventosNoIntervalo(arg1, arg2) {
+  return window['go']['handlers']['CalendarioHandler']['EventosNoIntervalo'](arg1, arg2);
+}
+
+export function ExportarArquivoICS(arg1) {
+  return window['go']['handlers']['CalendarioHandler']['ExportarArquivoICS'](arg1);
+}
+
+export function ExportarICS() {
+  return window['go']['handlers']['CalendarioHandler']['ExportarICS']();
+}
+
+export function ImportarArquivoICS(arg1) {
+  return window['go']['handlers']['CalendarioHandler']['ImportarArquivoICS'](arg1);
+}
+
+export function ImportarICS() {
+  return window['go']['handlers']['CalendarioHandler']['ImportarICS']();
+}
+
+export function ListarAssinaturas() {
+  return window['go']['handlers']['CalendarioHandler']['ListarAssinaturas']();
+}
+
+export function ListarOcorrencias(arg1, arg2) {
+  return window['go']['handlers']['CalendarioHandler']['ListarOcorrencias'](arg1, arg2);
+}
+
+export function ProximosLembretes(arg1) {
+  return window['go']['handlers']['CalendarioHandler']['ProximosLembretes'](arg1);
+}
+
+export function PularOcorrencia(arg1, arg2) {
+  return window['go']['handlers']['CalendarioHandler']['PularOcorrencia'](arg1, arg2);
+}
+
+export function RemoverAssinatura(arg1) {
+  return window['go']['handlers']['CalendarioHandler']['RemoverAssinatura'](arg1);
+}
+
 export function SalvarEventos(arg1) {
   return window['go']['handlers']['CalendarioHandler']['SalvarEventos'](arg1);
 }
 
+export function Shutdown(arg1) {
+  return window['go']['handlers']['CalendarioHandler']['Shutdown'](arg1);
+}
+
 export function Startup(arg1) {
   return window['go']['handlers']['CalendarioHandler']['Startup'](arg1);
 }
@@ -1,12 +1,21 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {handlers} from '../models';
+import {storage} from '../models';
 import {context} from '../models';
 
+export function AdicionarNo(arg1:handlers.NodeData):Promise<void>;
+
+export function Apontar(arg1:string,arg2:storage.Backend):Promise<void>;
+
+export function AtualizarNo(arg1:handlers.NodeData):Promise<void>;
+
 export function CarregarCanvas():Promise<handlers.CanvasData>;
 
 export function DeletarImagem(arg1:string):Promise<void>;
 
+export function DeletarNo(arg1:string):Promise<void>;
+
 export function LimparImagensOrfas(arg1:Array<string>):Promise<void>;
 
 export function SalvarCanvas(arg1:Array<handlers.NodeData>,arg2:Array<handlers.EdgeData>):Promise<void>;
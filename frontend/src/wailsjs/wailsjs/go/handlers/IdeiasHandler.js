@@ -2,6 +2,18 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AdicionarNo(arg1) {
+  return window['go']['handlers']['IdeiasHandler']['AdicionarNo'](arg1);
+}
+
+export function Apontar(arg1, arg2) {
+  return window['go']['handlers']['IdeiasHandler']['Apontar'](arg1, arg2);
+}
+
+export function AtualizarNo(arg1) {
+  return window['go']['handlers']['IdeiasHandler']['AtualizarNo'](arg1);
+}
+
 export function CarregarCanvas() {
   return window['go']['handlers']['IdeiasHandler']['CarregarCanvas']();
 }
@@ -10,6 +22,10 @@ export function DeletarImagem(arg1) {
   return window['go']['handlers']['IdeiasHandler']['DeletarImagem'](arg1);
 }
 
+export function DeletarNo(arg1) {
+  return window['go']['handlers']['IdeiasHandler']['DeletarNo'](arg1);
+}
+
 export function LimparImagensOrfas(arg1) {
   return window['go']['handlers']['IdeiasHandler']['LimparImagensOrfas'](arg1);
 }
@@ -1,10 +1,13 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {handlers} from '../models';
+import {storage} from '../models';
 import {context} from '../models';
 
 export function AdicionarLink(arg1:handlers.Link):Promise<void>;
 
+export function Apontar(arg1:string,arg2:storage.Backend):Promise<void>;
+
 export function AtualizarLink(arg1:handlers.Link):Promise<void>;
 
 export function CarregarLinks():Promise<Array<handlers.Link>>;
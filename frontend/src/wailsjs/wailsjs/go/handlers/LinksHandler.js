@@ -6,6 +6,10 @@ export function AdicionarLink(arg1) {
   return window['go']['handlers']['LinksHandler']['AdicionarLink'](arg1);
 }
 
+export function Apontar(arg1, arg2) {
+  return window['go']['handlers']['LinksHandler']['Apontar'](arg1, arg2);
+}
+
 export function AtualizarLink(arg1) {
   return window['go']['handlers']['LinksHandler']['AtualizarLink'](arg1);
 }
@@ -1,10 +1,13 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {handlers} from '../models';
+import {storage} from '../models';
 import {context} from '../models';
 
 export function AdicionarObjetivo(arg1:handlers.Objetivo):Promise<void>;
 
+export function Apontar(arg1:string,arg2:storage.Backend):Promise<void>;
+
 export function AtualizarObjetivo(arg1:handlers.Objetivo):Promise<void>;
 
 export function CarregarObjetivos():Promise<Array<handlers.Objetivo>>;
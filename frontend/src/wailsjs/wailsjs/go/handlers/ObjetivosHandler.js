@@ -6,6 +6,10 @@ export function AdicionarObjetivo(arg1) {
   return window['go']['handlers']['ObjetivosHandler']['AdicionarObjetivo'](arg1);
 }
 
+export function Apontar(arg1, arg2) {
+  return window['go']['handlers']['ObjetivosHandler']['Apontar'](arg1, arg2);
+}
+
 export function AtualizarObjetivo(arg1) {
   return window['go']['handlers']['ObjetivosHandler']['AtualizarObjetivo'](arg1);
 }
@@ -1,10 +1,13 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {handlers} from '../models';
+import {storage} from '../models';
 import {context} from '../models';
 
 export function AdicionarPasso(arg1:handlers.Passo):Promise<void>;
 
+export function Apontar(arg1:string,arg2:storage.Backend):Promise<void>;
+
 export function AtualizarPasso(arg1:handlers.Passo):Promise<void>;
 
 export function CarregarPassos():Promise<Array<handlers.Passo>>;
@@ -6,6 +6,10 @@ export function AdicionarPasso(arg1) {
   return window['go']['handlers']['PassosHandler']['AdicionarPasso'](arg1);
 }
 
+export function Apontar(arg1, arg2) {
+  return window['go']['handlers']['PassosHandler']['Apontar'](arg1, arg2);
+}
+
 export function AtualizarPasso(arg1) {
   return window['go']['handlers']['PassosHandler']['AtualizarPasso'](arg1);
 }
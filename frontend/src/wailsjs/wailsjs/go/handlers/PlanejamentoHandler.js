@@ -6,6 +6,10 @@ export function AdicionarTarefa(arg1) {
   return window['go']['handlers']['PlanejamentoHandler']['AdicionarTarefa'](arg1);
 }
 
+export function Apontar(arg1, arg2) {
+  return window['go']['handlers']['PlanejamentoHandler']['Apontar'](arg1, arg2);
+}
+
 export function AtualizarTarefa(arg1, arg2) {
   return window['go']['handlers']['PlanejamentoHandler']['AtualizarTarefa'](arg1, arg2);
 }
@@ -18,6 +22,10 @@ export function DeletarTarefa(arg1, arg2) {
   return window['go']['handlers']['PlanejamentoHandler']['DeletarTarefa'](arg1, arg2);
 }
 
+export function ListarTarefas() {
+  return window['go']['handlers']['PlanejamentoHandler']['ListarTarefas']();
+}
+
 export function MoverTarefa(arg1, arg2, arg3) {
   return window['go']['handlers']['PlanejamentoHandler']['MoverTarefa'](arg1, arg2, arg3);
 }
@@ -1,9 +1,102 @@
+export namespace api {
+	
+	export class EstadoAPI {
+	    ativa: boolean;
+	    porta: number;
+	    token: string;
+	    endereco?: string;
+	    erro?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EstadoAPI(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ativa = source["ativa"];
+	        this.porta = source["porta"];
+	        this.token = source["token"];
+	        this.endereco = source["endereco"];
+	        this.erro = source["erro"];
+	    }
+	}
+
+}
+
+export namespace app {
+	
+	export class EstadoCripto {
+	    modo: string;
+	    bloqueado: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EstadoCripto(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.modo = source["modo"];
+	        this.bloqueado = source["bloqueado"];
+	    }
+	}
+	export class Workspace {
+	    id: string;
+	    nome: string;
+	    ativo: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Workspace(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.nome = source["nome"];
+	        this.ativo = source["ativo"];
+	    }
+	}
+
+}
+
 export namespace handlers {
 	
+	export class Agendamento {
+	    minutos: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Agendamento(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.minutos = source["minutos"];
+	    }
+	}
+	export class VerificacaoBackup {
+	    backup: string;
+	    integro: boolean;
+	    problemas: string[];
+	    verificadoEm: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new VerificacaoBackup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.backup = source["backup"];
+	        this.integro = source["integro"];
+	        this.problemas = source["problemas"];
+	        this.verificadoEm = source["verificadoEm"];
+	    }
+	}
 	export class BackupInfo {
 	    nome: string;
 	    data: string;
 	    label: string;
+	    nota?: string;
+	    fixado?: boolean;
+	    verificacao?: VerificacaoBackup;
 	
 	    static createFrom(source: any = {}) {
 	        return new BackupInfo(source);
@@ -14,8 +107,86 @@ export namespace handlers {
 	        this.nome = source["nome"];
 	        this.data = source["data"];
 	        this.label = source["label"];
+	        this.nota = source["nota"];
+	        this.fixado = source["fixado"];
+	        this.verificacao = this.convertValues(source["verificacao"], VerificacaoBackup);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	export class ArquivoCorrompido {
+	    arquivo: string;
+	    quarentena: string;
+	    backup?: BackupInfo;
+	
+	    static createFrom(source: any = {}) {
+	        return new ArquivoCorrompido(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.arquivo = source["arquivo"];
+	        this.quarentena = source["quarentena"];
+	        this.backup = this.convertValues(source["backup"], BackupInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Assinatura {
+	    id: string;
+	    nome: string;
+	    origem: string;
+	    cor: string;
+	    minutos?: number;
+	    createdAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Assinatura(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.nome = source["nome"];
+	        this.origem = source["origem"];
+	        this.cor = source["cor"];
+	        this.minutos = source["minutos"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	
 	export class EdgeData {
 	    id: string;
 	    source: string;
@@ -100,7 +271,179 @@ export namespace handlers {
 		    return a;
 		}
 	}
+	export class PoliticaRetencao {
+	    ultimos: number;
+	    horas: number;
+	    dias: number;
+	    semanas: number;
 	
+	    static createFrom(source: any = {}) {
+	        return new PoliticaRetencao(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ultimos = source["ultimos"];
+	        this.horas = source["horas"];
+	        this.dias = source["dias"];
+	        this.semanas = source["semanas"];
+	    }
+	}
+	export class Destino {
+	    id: string;
+	    nome: string;
+	    pasta: string;
+	    retencao: PoliticaRetencao;
+	    ultimoBackup?: string;
+	    ultimaCopia?: string;
+	    erro?: string;
+	    erroEm?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Destino(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.nome = source["nome"];
+	        this.pasta = source["pasta"];
+	        this.retencao = this.convertValues(source["retencao"], PoliticaRetencao);
+	        this.ultimoBackup = source["ultimoBackup"];
+	        this.ultimaCopia = source["ultimaCopia"];
+	        this.erro = source["erro"];
+	        this.erroEm = source["erroEm"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ItemDiferente {
+	    id: string;
+	    titulo: string;
+	    tipo: string;
+	    atual?: number[];
+	    backup?: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemDiferente(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.titulo = source["titulo"];
+	        this.tipo = source["tipo"];
+	        this.atual = source["atual"];
+	        this.backup = source["backup"];
+	    }
+	}
+	export class DiferencaModulo {
+	    arquivo: string;
+	    modulo: string;
+	    adicionados: number;
+	    removidos: number;
+	    alterados: number;
+	    itens: ItemDiferente[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DiferencaModulo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.arquivo = source["arquivo"];
+	        this.modulo = source["modulo"];
+	        this.adicionados = source["adicionados"];
+	        this.removidos = source["removidos"];
+	        this.alterados = source["alterados"];
+	        this.itens = this.convertValues(source["itens"], ItemDiferente);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class EstadoAssinatura {
+	    id: string;
+	    nome: string;
+	    origem: string;
+	    cor: string;
+	    minutos?: number;
+	    createdAt: string;
+	    atualizadoEm?: string;
+	    eventos: number;
+	    erro?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EstadoAssinatura(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.nome = source["nome"];
+	        this.origem = source["origem"];
+	        this.cor = source["cor"];
+	        this.minutos = source["minutos"];
+	        this.createdAt = source["createdAt"];
+	        this.atualizadoEm = source["atualizadoEm"];
+	        this.eventos = source["eventos"];
+	        this.erro = source["erro"];
+	    }
+	}
+	export class Recorrencia {
+	    frequencia: string;
+	    intervalo?: number;
+	    diasSemana?: string[];
+	    ate?: string;
+	    vezes?: number;
+	    excecoes?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Recorrencia(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.frequencia = source["frequencia"];
+	        this.intervalo = source["intervalo"];
+	        this.diasSemana = source["diasSemana"];
+	        this.ate = source["ate"];
+	        this.vezes = source["vezes"];
+	        this.excecoes = source["excecoes"];
+	    }
+	}
 	export class Evento {
 	    id: string;
 	    titulo: string;
@@ -109,6 +452,9 @@ export namespace handlers {
 	    descricao: string;
 	    cor: string;
 	    createdAt: string;
+	    recorrencia?: Recorrencia;
+	    uid?: string;
+	    lembretes?: number[];
 	
 	    static createFrom(source: any = {}) {
 	        return new Evento(source);
@@ -123,6 +469,86 @@ export namespace handlers {
 	        this.descricao = source["descricao"];
 	        this.cor = source["cor"];
 	        this.createdAt = source["createdAt"];
+	        this.recorrencia = this.convertValues(source["recorrencia"], Recorrencia);
+	        this.uid = source["uid"];
+	        this.lembretes = source["lembretes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class InfoRestauracao {
+	    backup: BackupInfo;
+	    anterior: BackupInfo;
+	    arquivos: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new InfoRestauracao(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.backup = this.convertValues(source["backup"], BackupInfo);
+	        this.anterior = this.convertValues(source["anterior"], BackupInfo);
+	        this.arquivos = source["arquivos"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Lembrete {
+	    id: string;
+	    eventoId: string;
+	    titulo: string;
+	    data: string;
+	    hora: string;
+	    cor: string;
+	    minutos: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Lembrete(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.eventoId = source["eventoId"];
+	        this.titulo = source["titulo"];
+	        this.data = source["data"];
+	        this.hora = source["hora"];
+	        this.cor = source["cor"];
+	        this.minutos = source["minutos"];
 	    }
 	}
 	export class Link {
@@ -168,6 +594,60 @@ export namespace handlers {
 	        this.createdAt = source["createdAt"];
 	    }
 	}
+	export class Ocorrencia {
+	    id: string;
+	    titulo: string;
+	    data: string;
+	    hora: string;
+	    descricao: string;
+	    cor: string;
+	    createdAt: string;
+	    recorrencia?: Recorrencia;
+	    uid?: string;
+	    lembretes?: number[];
+	    recorrente?: boolean;
+	    somenteLeitura?: boolean;
+	    calendario?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Ocorrencia(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.titulo = source["titulo"];
+	        this.data = source["data"];
+	        this.hora = source["hora"];
+	        this.descricao = source["descricao"];
+	        this.cor = source["cor"];
+	        this.createdAt = source["createdAt"];
+	        this.recorrencia = this.convertValues(source["recorrencia"], Recorrencia);
+	        this.uid = source["uid"];
+	        this.lembretes = source["lembretes"];
+	        this.recorrente = source["recorrente"];
+	        this.somenteLeitura = source["somenteLeitura"];
+	        this.calendario = source["calendario"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Passo {
 	    id: string;
 	    descricao: string;
@@ -188,6 +668,7 @@ export namespace handlers {
 	        this.createdAt = source["createdAt"];
 	    }
 	}
+	
 	export class Tarefa {
 	    id: string;
 	    titulo: string;
@@ -242,6 +723,41 @@ export namespace handlers {
 		    return a;
 		}
 	}
+	
+	export class ResultadoICS {
+	    novos: number;
+	    atualizados: number;
+	    avisos?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ResultadoICS(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.novos = source["novos"];
+	        this.atualizados = source["atualizados"];
+	        this.avisos = source["avisos"];
+	    }
+	}
+	
+
+}
+
+export namespace storage {
+	
+	export class Cifra {
+	
+	
+	    static createFrom(source: any = {}) {
+	        return new Cifra(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	
+	    }
+	}
 
 }
 
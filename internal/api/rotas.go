@@ -0,0 +1,566 @@
+package api
+
+import (
+	"bytes"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/handlers"
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// openapi descreve a API no formato OpenAPI 3, servido em /api/openapi.json
+//
+//go:embed openapi.json
+var openapi []byte
+
+// tamanhoMaximoCorpo limita o corpo das requisições
+const tamanhoMaximoCorpo = 1 << 20
+
+// Erros que viram códigos HTTP (ver escreverErro)
+var (
+	errNaoEncontrado = errors.New("não encontrado")
+	errJaExiste      = errors.New("já existe um item com este ID")
+)
+
+// errRequisicao é um corpo ou parâmetro inválido
+type errRequisicao struct{ msg string }
+
+func (e errRequisicao) Error() string { return e.msg }
+
+// rota atende uma requisição e retorna o código e o corpo da resposta
+type rota func(r *http.Request) (int, any, error)
+
+// rotas monta o roteador da API, protegido por protegido
+func (s *Servidor) rotas() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openapi)
+	})
+
+	m := s.modulos
+	registrarLista(s, mux, "/api/links", recursoLista[handlers.Link]{
+		modulo: "links", prefixo: "link",
+		carregar: m.Links.CarregarLinks, adicionar: m.Links.AdicionarLink,
+		atualizar: m.Links.AtualizarLink, deletar: m.Links.DeletarLink,
+		campos: func(l *handlers.Link) (*string, *string) { return &l.ID, &l.CreatedAt },
+	})
+	registrarLista(s, mux, "/api/eventos", recursoLista[handlers.Evento]{
+		modulo: "calendario", prefixo: "evento",
+		carregar: m.Calendario.CarregarEventos, adicionar: m.Calendario.AdicionarEvento,
+		atualizar: m.Calendario.AtualizarEvento, deletar: m.Calendario.DeletarEvento,
+		campos: func(e *handlers.Evento) (*string, *string) { return &e.ID, &e.CreatedAt },
+	})
+	registrarLista(s, mux, "/api/objetivos", recursoLista[handlers.Objetivo]{
+		modulo: "objetivos", prefixo: "obj",
+		carregar: m.Objetivos.CarregarObjetivos, adicionar: m.Objetivos.AdicionarObjetivo,
+		atualizar: m.Objetivos.AtualizarObjetivo, deletar: m.Objetivos.DeletarObjetivo,
+		campos: func(o *handlers.Objetivo) (*string, *string) { return &o.ID, &o.CreatedAt },
+	})
+	registrarLista(s, mux, "/api/passos", recursoLista[handlers.Passo]{
+		modulo: "objetivo-passos", prefixo: "passo",
+		carregar: m.Passos.CarregarPassos, adicionar: m.Passos.AdicionarPasso,
+		atualizar: m.Passos.AtualizarPasso, deletar: m.Passos.DeletarPasso,
+		campos: func(p *handlers.Passo) (*string, *string) { return &p.ID, &p.CreatedAt },
+	})
+	s.registrarOcorrencias(mux)
+	s.registrarTarefas(mux)
+	s.registrarCanvas(mux)
+
+	return s.protegido(mux)
+}
+
+// protegido só deixa passar requisições feitas a 127.0.0.1 pelo nome
+// esperado (contra DNS rebinding) e com o token. CORS é liberado: o
+// navegador não envia o token sozinho, então outra página não consegue usar
+// a API em nome do usuário.
+func (s *Servidor) protegido(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hostLocal(r.Host) {
+			escreverErro(w, http.StatusForbidden, fmt.Errorf("host não permitido: %s", r.Host))
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if r.URL.Path != "/api/openapi.json" && !s.tokenValido(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			escreverErro(w, http.StatusUnauthorized, fmt.Errorf("token ausente ou inválido"))
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, tamanhoMaximoCorpo)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tokenValido confere o cabeçalho "Authorization: Bearer <token>"
+func (s *Servidor) tokenValido(r *http.Request) bool {
+	token := s.tokenAtual()
+	enviado, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(enviado), []byte(token)) == 1
+}
+
+func hostLocal(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return host == "127.0.0.1" || host == "localhost" || host == "::1"
+}
+
+// responder executa a rota e escreve a resposta. Alterações bem-sucedidas
+// avisam o frontend, que recarrega o módulo se ele estiver aberto.
+func (s *Servidor) responder(modulo string, f rota) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, corpo, err := f(r)
+		if err != nil {
+			escreverErro(w, codigoErro(err), err)
+			return
+		}
+		if r.Method != http.MethodGet && s.ctx != nil {
+			runtime.EventsEmit(s.ctx, "dados:alterados", modulo)
+		}
+		if corpo == nil {
+			w.WriteHeader(status)
+			return
+		}
+		escreverJSON(w, status, corpo)
+	}
+}
+
+func codigoErro(err error) int {
+	var requisicao errRequisicao
+	var tamanho *http.MaxBytesError
+	switch {
+	case errors.Is(err, errNaoEncontrado):
+		return http.StatusNotFound
+	case errors.Is(err, errJaExiste):
+		return http.StatusConflict
+	case errors.Is(err, storage.ErrBloqueado):
+		return http.StatusLocked
+	case errors.As(err, &requisicao), errors.Is(err, handlers.ErrRecorrenciaInvalida),
+		errors.Is(err, handlers.ErrLembreteInvalido):
+		return http.StatusBadRequest
+	case errors.As(err, &tamanho):
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
+
+func escreverJSON(w http.ResponseWriter, status int, corpo any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(corpo)
+}
+
+func escreverErro(w http.ResponseWriter, status int, err error) {
+	escreverJSON(w, status, map[string]string{"erro": err.Error()})
+}
+
+// lerCorpo decodifica o corpo JSON em v. Campos desconhecidos são recusados,
+// para que um nome errado não seja ignorado em silêncio.
+func lerCorpo(r *http.Request, v any) error {
+	dados, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(dados))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errRequisicao{fmt.Sprintf("JSON inválido: %v", err)}
+	}
+	return nil
+}
+
+// recursoLista descreve um módulo guardado como lista de itens com ID
+type recursoLista[E any] struct {
+	modulo    string // módulo avisado ao frontend
+	prefixo   string // prefixo dos IDs gerados
+	carregar  func() ([]E, error)
+	adicionar func(E) error
+	atualizar func(E) error
+	deletar   func(id string) error
+	campos    func(*E) (id *string, criadoEm *string)
+}
+
+// registrarLista cria as rotas de listar, criar, ler, substituir (PUT),
+// alterar só os campos enviados (PATCH) e remover itens do recurso
+func registrarLista[E any](s *Servidor, mux *http.ServeMux, caminho string, rl recursoLista[E]) {
+	buscar := func(id string) (E, error) {
+		var vazio E
+		itens, err := rl.carregar()
+		if err != nil {
+			return vazio, err
+		}
+		for _, item := range itens {
+			if atual, _ := rl.campos(&item); *atual == id {
+				return item, nil
+			}
+		}
+		return vazio, fmt.Errorf("%w: %s", errNaoEncontrado, id)
+	}
+
+	mux.HandleFunc("GET "+caminho, s.responder(rl.modulo, func(r *http.Request) (int, any, error) {
+		itens, err := rl.carregar()
+		return http.StatusOK, itens, err
+	}))
+
+	mux.HandleFunc("POST "+caminho, s.responder(rl.modulo, func(r *http.Request) (int, any, error) {
+		var item E
+		if err := lerCorpo(r, &item); err != nil {
+			return 0, nil, err
+		}
+		id, criadoEm := rl.campos(&item)
+		if *id == "" {
+			*id = handlers.NovoID(rl.prefixo)
+		} else if _, err := buscar(*id); err == nil {
+			return 0, nil, errJaExiste
+		}
+		if *criadoEm == "" {
+			*criadoEm = handlers.CriadoAgora()
+		}
+		if err := rl.adicionar(item); err != nil {
+			return 0, nil, err
+		}
+		// O handler pode completar o item (ex: a ordem de um passo)
+		item, err := buscar(*id)
+		return http.StatusCreated, item, err
+	}))
+
+	mux.HandleFunc("GET "+caminho+"/{id}", s.responder(rl.modulo, func(r *http.Request) (int, any, error) {
+		item, err := buscar(r.PathValue("id"))
+		return http.StatusOK, item, err
+	}))
+
+	alterar := func(parcial bool) rota {
+		return func(r *http.Request) (int, any, error) {
+			atual, err := buscar(r.PathValue("id"))
+			if err != nil {
+				return 0, nil, err
+			}
+			var item E
+			if parcial {
+				item = atual
+			}
+			if err := lerCorpo(r, &item); err != nil {
+				return 0, nil, err
+			}
+			id, criadoEm := rl.campos(&item)
+			_, criadoAntes := rl.campos(&atual)
+			*id = r.PathValue("id")
+			if *criadoEm == "" {
+				*criadoEm = *criadoAntes
+			}
+			if err := rl.atualizar(item); err != nil {
+				return 0, nil, err
+			}
+			return http.StatusOK, item, nil
+		}
+	}
+	mux.HandleFunc("PUT "+caminho+"/{id}", s.responder(rl.modulo, alterar(false)))
+	mux.HandleFunc("PATCH "+caminho+"/{id}", s.responder(rl.modulo, alterar(true)))
+
+	mux.HandleFunc("DELETE "+caminho+"/{id}", s.responder(rl.modulo, func(r *http.Request) (int, any, error) {
+		if _, err := buscar(r.PathValue("id")); err != nil {
+			return 0, nil, err
+		}
+		return http.StatusNoContent, nil, rl.deletar(r.PathValue("id"))
+	}))
+}
+
+// registrarOcorrencias cria as rotas da agenda: os eventos de um período com
+// as repetições expandidas, a busca por texto e pular uma data de um evento
+// que se repete
+func (s *Servidor) registrarOcorrencias(mux *http.ServeMux) {
+	h := s.modulos.Calendario
+	const modulo = "calendario"
+
+	mux.HandleFunc("GET /api/ocorrencias", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		inicio, fim := r.URL.Query().Get("inicio"), r.URL.Query().Get("fim")
+		for _, d := range []string{inicio, fim} {
+			if _, err := time.Parse(time.DateOnly, d); err != nil {
+				return 0, nil, errRequisicao{"informe inicio e fim no formato AAAA-MM-DD"}
+			}
+		}
+		if fim < inicio {
+			return 0, nil, errRequisicao{"fim é anterior a inicio"}
+		}
+		ocorrencias, err := h.ListarOcorrencias(inicio, fim)
+		return http.StatusOK, ocorrencias, err
+	}))
+
+	mux.HandleFunc("GET /api/eventos/busca", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		texto := strings.TrimSpace(r.URL.Query().Get("q"))
+		if texto == "" {
+			return 0, nil, errRequisicao{"informe o texto da busca em q"}
+		}
+		eventos, err := h.BuscarEventos(texto)
+		return http.StatusOK, eventos, err
+	}))
+
+	mux.HandleFunc("POST /api/eventos/{id}/pular", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		var corpo struct {
+			Data string `json:"data"`
+		}
+		if err := lerCorpo(r, &corpo); err != nil {
+			return 0, nil, err
+		}
+		if _, err := time.Parse(time.DateOnly, corpo.Data); err != nil {
+			return 0, nil, errRequisicao{"informe a data no formato AAAA-MM-DD"}
+		}
+		eventos, err := h.CarregarEventos()
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, e := range eventos {
+			if e.ID != r.PathValue("id") {
+				continue
+			}
+			if e.Recorrencia == nil {
+				return 0, nil, errRequisicao{"o evento não se repete"}
+			}
+			return http.StatusNoContent, nil, h.PularOcorrencia(e.ID, corpo.Data)
+		}
+		return 0, nil, fmt.Errorf("%w: %s", errNaoEncontrado, r.PathValue("id"))
+	}))
+}
+
+// registrarTarefas cria as rotas do quadro. O status de uma tarefa é a
+// coluna em que ela está; mudar o status move a tarefa de coluna.
+func (s *Servidor) registrarTarefas(mux *http.ServeMux) {
+	h := s.modulos.Planejamento
+	const modulo = "planejamento"
+
+	buscar := func(id string) (handlers.Tarefa, error) {
+		tarefas, err := h.ListarTarefas()
+		if err != nil {
+			return handlers.Tarefa{}, err
+		}
+		for _, t := range tarefas {
+			if t.ID == id {
+				return t, nil
+			}
+		}
+		return handlers.Tarefa{}, fmt.Errorf("%w: %s", errNaoEncontrado, id)
+	}
+	validar := func(t handlers.Tarefa) error {
+		if !slices.Contains(handlers.StatusTarefas, t.Status) {
+			return errRequisicao{fmt.Sprintf("status inválido: %q (use %s)", t.Status, strings.Join(handlers.StatusTarefas, ", "))}
+		}
+		return nil
+	}
+
+	mux.HandleFunc("GET /api/tarefas", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		tarefas, err := h.ListarTarefas()
+		if err != nil {
+			return 0, nil, err
+		}
+		status := r.URL.Query().Get("status")
+		if status == "" {
+			return http.StatusOK, tarefas, nil
+		}
+		filtradas := []handlers.Tarefa{}
+		for _, t := range tarefas {
+			if t.Status == status {
+				filtradas = append(filtradas, t)
+			}
+		}
+		return http.StatusOK, filtradas, nil
+	}))
+
+	mux.HandleFunc("POST /api/tarefas", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		var t handlers.Tarefa
+		if err := lerCorpo(r, &t); err != nil {
+			return 0, nil, err
+		}
+		if t.Status == "" {
+			t.Status = handlers.StatusTarefas[0]
+		}
+		if err := validar(t); err != nil {
+			return 0, nil, err
+		}
+		if t.ID == "" {
+			t.ID = handlers.NovoID("tarefa")
+		} else if _, err := buscar(t.ID); err == nil {
+			return 0, nil, errJaExiste
+		}
+		if t.CreatedAt == "" {
+			t.CreatedAt = handlers.CriadoAgora()
+		}
+		if err := h.AdicionarTarefa(t); err != nil {
+			return 0, nil, err
+		}
+		t, err := buscar(t.ID)
+		return http.StatusCreated, t, err
+	}))
+
+	mux.HandleFunc("GET /api/tarefas/{id}", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		t, err := buscar(r.PathValue("id"))
+		return http.StatusOK, t, err
+	}))
+
+	alterar := func(parcial bool) rota {
+		return func(r *http.Request) (int, any, error) {
+			atual, err := buscar(r.PathValue("id"))
+			if err != nil {
+				return 0, nil, err
+			}
+			var t handlers.Tarefa
+			if parcial {
+				t = atual
+			}
+			if err := lerCorpo(r, &t); err != nil {
+				return 0, nil, err
+			}
+			t.ID = atual.ID
+			if t.Status == "" {
+				t.Status = atual.Status
+			}
+			if t.CreatedAt == "" {
+				t.CreatedAt = atual.CreatedAt
+			}
+			if err := validar(t); err != nil {
+				return 0, nil, err
+			}
+			if t.Status != atual.Status {
+				if err := h.MoverTarefa(t.ID, atual.Status, t.Status); err != nil {
+					return 0, nil, err
+				}
+			}
+			if err := h.AtualizarTarefa(t, t.Status); err != nil {
+				return 0, nil, err
+			}
+			t, err = buscar(t.ID)
+			return http.StatusOK, t, err
+		}
+	}
+	mux.HandleFunc("PUT /api/tarefas/{id}", s.responder(modulo, alterar(false)))
+	mux.HandleFunc("PATCH /api/tarefas/{id}", s.responder(modulo, alterar(true)))
+
+	mux.HandleFunc("DELETE /api/tarefas/{id}", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		t, err := buscar(r.PathValue("id"))
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusNoContent, nil, h.DeletarTarefa(t.ID, t.Status)
+	}))
+}
+
+// registrarCanvas cria as rotas do canvas de ideias: o canvas inteiro e
+// cada nó. As conexões são alteradas junto com o canvas inteiro.
+func (s *Servidor) registrarCanvas(mux *http.ServeMux) {
+	h := s.modulos.Ideias
+	const modulo = "ideias"
+
+	buscar := func(id string) (handlers.NodeData, error) {
+		canvas, err := h.CarregarCanvas()
+		if err != nil {
+			return handlers.NodeData{}, err
+		}
+		for _, n := range canvas.Nodes {
+			if n.ID == id {
+				return n, nil
+			}
+		}
+		return handlers.NodeData{}, fmt.Errorf("%w: %s", errNaoEncontrado, id)
+	}
+
+	mux.HandleFunc("GET /api/canvas", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		canvas, err := h.CarregarCanvas()
+		return http.StatusOK, canvas, err
+	}))
+
+	mux.HandleFunc("PUT /api/canvas", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		var canvas handlers.CanvasData
+		if err := lerCorpo(r, &canvas); err != nil {
+			return 0, nil, err
+		}
+		if err := h.SalvarCanvas(canvas.Nodes, canvas.Edges); err != nil {
+			return 0, nil, err
+		}
+		canvas, err := h.CarregarCanvas()
+		return http.StatusOK, canvas, err
+	}))
+
+	mux.HandleFunc("GET /api/canvas/nos", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		canvas, err := h.CarregarCanvas()
+		return http.StatusOK, canvas.Nodes, err
+	}))
+
+	mux.HandleFunc("POST /api/canvas/nos", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		var n handlers.NodeData
+		if err := lerCorpo(r, &n); err != nil {
+			return 0, nil, err
+		}
+		if n.Type == "" {
+			n.Type = "text"
+		}
+		if n.ID == "" {
+			n.ID = handlers.NovoID(n.Type)
+		} else if _, err := buscar(n.ID); err == nil {
+			return 0, nil, errJaExiste
+		}
+		if n.Position == nil {
+			n.Position = map[string]float64{"x": 0, "y": 0}
+		}
+		if n.Data == nil {
+			n.Data = map[string]interface{}{}
+		}
+		if err := h.AdicionarNo(n); err != nil {
+			return 0, nil, err
+		}
+		n, err := buscar(n.ID)
+		return http.StatusCreated, n, err
+	}))
+
+	mux.HandleFunc("GET /api/canvas/nos/{id}", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		n, err := buscar(r.PathValue("id"))
+		return http.StatusOK, n, err
+	}))
+
+	alterar := func(parcial bool) rota {
+		return func(r *http.Request) (int, any, error) {
+			atual, err := buscar(r.PathValue("id"))
+			if err != nil {
+				return 0, nil, err
+			}
+			var n handlers.NodeData
+			if parcial {
+				n = atual
+			}
+			if err := lerCorpo(r, &n); err != nil {
+				return 0, nil, err
+			}
+			n.ID = atual.ID
+			if err := h.AtualizarNo(n); err != nil {
+				return 0, nil, err
+			}
+			n, err = buscar(n.ID)
+			return http.StatusOK, n, err
+		}
+	}
+	mux.HandleFunc("PUT /api/canvas/nos/{id}", s.responder(modulo, alterar(false)))
+	mux.HandleFunc("PATCH /api/canvas/nos/{id}", s.responder(modulo, alterar(true)))
+
+	mux.HandleFunc("DELETE /api/canvas/nos/{id}", s.responder(modulo, func(r *http.Request) (int, any, error) {
+		if _, err := buscar(r.PathValue("id")); err != nil {
+			return 0, nil, err
+		}
+		return http.StatusNoContent, nil, h.DeletarNo(r.PathValue("id"))
+	}))
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/handlers"
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+const tokenTeste = "token-de-teste"
+
+// novaAPITeste monta as rotas da API sobre um workspace vazio
+func novaAPITeste(t *testing.T) http.Handler {
+	t.Helper()
+	assets := t.TempDir()
+	backend := storage.NewBackendArquivos(filepath.Join(assets, "init"))
+	s := NovoServidor(t.TempDir(), Modulos{
+		Ideias:       handlers.NewIdeiasHandler(assets, backend),
+		Links:        handlers.NewLinksHandler(assets, backend),
+		Planejamento: handlers.NewPlanejamentoHandler(assets, backend),
+		Passos:       handlers.NewPassosHandler(assets, backend),
+		Calendario:   handlers.NewCalendarioHandler(assets, backend),
+		Objetivos:    handlers.NewObjetivosHandler(assets, backend),
+	})
+	token := tokenTeste
+	s.token.Store(&token)
+	return s.rotas()
+}
+
+// requisitar faz uma requisição autenticada, com o corpo em JSON (uma
+// string vai como está)
+func requisitar(t *testing.T, h http.Handler, metodo, caminho string, corpo any) *httptest.ResponseRecorder {
+	t.Helper()
+	var texto string
+	switch c := corpo.(type) {
+	case nil:
+	case string:
+		texto = c
+	default:
+		dados, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		texto = string(dados)
+	}
+	req := httptest.NewRequest(metodo, caminho, strings.NewReader(texto))
+	req.Host = "127.0.0.1:8737"
+	req.Header.Set("Authorization", "Bearer "+tokenTeste)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// esperar confere o código da resposta e decodifica o corpo em v (se não for nil)
+func esperar(t *testing.T, rec *httptest.ResponseRecorder, status int, v any) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status %d, esperado %d: %s", rec.Code, status, rec.Body.String())
+	}
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("resposta ilegível: %v: %s", err, rec.Body.String())
+		}
+	}
+}
+
+func TestProtecao(t *testing.T) {
+	h := novaAPITeste(t)
+	casos := []struct {
+		nome          string
+		caminho       string
+		host          string
+		authorization string
+		status        int
+	}{
+		{"sem token", "/api/links", "127.0.0.1:8737", "", http.StatusUnauthorized},
+		{"token errado", "/api/links", "127.0.0.1:8737", "Bearer outro", http.StatusUnauthorized},
+		{"sem Bearer", "/api/links", "127.0.0.1:8737", tokenTeste, http.StatusUnauthorized},
+		{"host de fora", "/api/links", "exemplo.com:8737", "Bearer " + tokenTeste, http.StatusForbidden},
+		{"rebinding sem token", "/api/openapi.json", "exemplo.com", "", http.StatusForbidden},
+		{"localhost", "/api/links", "localhost:8737", "Bearer " + tokenTeste, http.StatusOK},
+		{"openapi sem token", "/api/openapi.json", "127.0.0.1:8737", "", http.StatusOK},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, c.caminho, nil)
+			req.Host = c.host
+			if c.authorization != "" {
+				req.Header.Set("Authorization", c.authorization)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != c.status {
+				t.Errorf("status %d, esperado %d: %s", rec.Code, c.status, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCorpoGrandeDemais(t *testing.T) {
+	h := novaAPITeste(t)
+	corpo := `{"title":"` + strings.Repeat("a", tamanhoMaximoCorpo) + `"}`
+	esperar(t, requisitar(t, h, http.MethodPost, "/api/links", corpo), http.StatusRequestEntityTooLarge, nil)
+
+	var links []handlers.Link
+	esperar(t, requisitar(t, h, http.MethodGet, "/api/links", nil), http.StatusOK, &links)
+	if len(links) != 0 {
+		t.Errorf("%d links gravados", len(links))
+	}
+}
+
+func TestListasCRUD(t *testing.T) {
+	casos := []struct {
+		caminho string
+		novo    map[string]any
+		campo   string // campo alterado pelo PATCH
+	}{
+		{"/api/links", map[string]any{"title": "Exemplo", "url": "https://exemplo.com"}, "title"},
+		{"/api/eventos", map[string]any{"titulo": "Dentista", "data": "2026-03-10", "hora": "14:30"}, "titulo"},
+		{"/api/objetivos", map[string]any{"titulo": "Correr 5 km"}, "titulo"},
+		{"/api/passos", map[string]any{"descricao": "Comprar tênis"}, "descricao"},
+	}
+	for _, c := range casos {
+		t.Run(c.caminho, func(t *testing.T) {
+			h := novaAPITeste(t)
+
+			var criado map[string]any
+			esperar(t, requisitar(t, h, http.MethodPost, c.caminho, c.novo), http.StatusCreated, &criado)
+			id, _ := criado["id"].(string)
+			if id == "" || criado["createdAt"] == "" {
+				t.Fatalf("item criado sem id ou createdAt: %v", criado)
+			}
+			item := c.caminho + "/" + id
+
+			esperar(t, requisitar(t, h, http.MethodPost, c.caminho, map[string]any{"id": id}), http.StatusConflict, nil)
+
+			var lista []map[string]any
+			esperar(t, requisitar(t, h, http.MethodGet, c.caminho, nil), http.StatusOK, &lista)
+			if len(lista) != 1 || lista[0]["id"] != id {
+				t.Fatalf("lista = %v", lista)
+			}
+
+			var alterado map[string]any
+			esperar(t, requisitar(t, h, http.MethodPatch, item, map[string]any{c.campo: "alterado"}), http.StatusOK, &alterado)
+			if alterado[c.campo] != "alterado" || alterado["createdAt"] != criado["createdAt"] {
+				t.Errorf("PATCH = %v", alterado)
+			}
+			var lido map[string]any
+			esperar(t, requisitar(t, h, http.MethodGet, item, nil), http.StatusOK, &lido)
+			if lido[c.campo] != "alterado" {
+				t.Errorf("GET depois do PATCH = %v", lido)
+			}
+			for campo, valor := range c.novo {
+				if campo != c.campo && lido[campo] != valor {
+					t.Errorf("PATCH perdeu %s: %v", campo, lido[campo])
+				}
+			}
+
+			substituto := map[string]any{c.campo: "substituído"}
+			esperar(t, requisitar(t, h, http.MethodPut, item, substituto), http.StatusOK, nil)
+			esperar(t, requisitar(t, h, http.MethodGet, item, nil), http.StatusOK, &lido)
+			if lido[c.campo] != "substituído" || lido["id"] != id {
+				t.Errorf("GET depois do PUT = %v", lido)
+			}
+
+			esperar(t, requisitar(t, h, http.MethodPatch, item, `{"naoExiste":1}`), http.StatusBadRequest, nil)
+
+			esperar(t, requisitar(t, h, http.MethodDelete, item, nil), http.StatusNoContent, nil)
+			esperar(t, requisitar(t, h, http.MethodGet, item, nil), http.StatusNotFound, nil)
+			esperar(t, requisitar(t, h, http.MethodDelete, item, nil), http.StatusNotFound, nil)
+		})
+	}
+}
+
+func TestTarefasMudamDeColuna(t *testing.T) {
+	h := novaAPITeste(t)
+
+	var criada handlers.Tarefa
+	esperar(t, requisitar(t, h, http.MethodPost, "/api/tarefas", map[string]any{"titulo": "Lavar a louça"}), http.StatusCreated, &criada)
+	if criada.ID == "" || criada.Status != "objetivo" {
+		t.Fatalf("tarefa criada = %+v", criada)
+	}
+	esperar(t, requisitar(t, h, http.MethodPost, "/api/tarefas", map[string]any{"titulo": "x", "status": "depois"}), http.StatusBadRequest, nil)
+
+	item := "/api/tarefas/" + criada.ID
+	var movida handlers.Tarefa
+	esperar(t, requisitar(t, h, http.MethodPatch, item, map[string]any{"status": "fazendo"}), http.StatusOK, &movida)
+	if movida.Status != "fazendo" || movida.Titulo != "Lavar a louça" {
+		t.Errorf("PATCH = %+v", movida)
+	}
+
+	var fazendo, objetivo []handlers.Tarefa
+	esperar(t, requisitar(t, h, http.MethodGet, "/api/tarefas?status=fazendo", nil), http.StatusOK, &fazendo)
+	esperar(t, requisitar(t, h, http.MethodGet, "/api/tarefas?status=objetivo", nil), http.StatusOK, &objetivo)
+	if len(fazendo) != 1 || fazendo[0].ID != criada.ID || len(objetivo) != 0 {
+		t.Errorf("fazendo = %+v, objetivo = %+v", fazendo, objetivo)
+	}
+
+	var substituida handlers.Tarefa
+	esperar(t, requisitar(t, h, http.MethodPut, item, map[string]any{"titulo": "Louça lavada", "status": "feito"}), http.StatusOK, &substituida)
+	if substituida.Status != "feito" || substituida.Titulo != "Louça lavada" || substituida.CreatedAt != criada.CreatedAt {
+		t.Errorf("PUT = %+v", substituida)
+	}
+
+	esperar(t, requisitar(t, h, http.MethodDelete, item, nil), http.StatusNoContent, nil)
+	esperar(t, requisitar(t, h, http.MethodGet, item, nil), http.StatusNotFound, nil)
+}
+
+func TestNosDoCanvas(t *testing.T) {
+	h := novaAPITeste(t)
+
+	var no handlers.NodeData
+	esperar(t, requisitar(t, h, http.MethodPost, "/api/canvas/nos", map[string]any{"data": map[string]any{"label": "Ideia"}}), http.StatusCreated, &no)
+	if no.ID == "" || no.Type != "text" || no.Position == nil {
+		t.Fatalf("nó criado = %+v", no)
+	}
+	item := "/api/canvas/nos/" + no.ID
+
+	var movido handlers.NodeData
+	esperar(t, requisitar(t, h, http.MethodPatch, item, map[string]any{"position": map[string]float64{"x": 10, "y": 20}}), http.StatusOK, &movido)
+	if movido.Position["x"] != 10 || movido.Data["label"] != "Ideia" {
+		t.Errorf("PATCH = %+v", movido)
+	}
+
+	var canvas handlers.CanvasData
+	esperar(t, requisitar(t, h, http.MethodGet, "/api/canvas", nil), http.StatusOK, &canvas)
+	if len(canvas.Nodes) != 1 || canvas.Nodes[0].ID != no.ID {
+		t.Errorf("canvas = %+v", canvas)
+	}
+
+	esperar(t, requisitar(t, h, http.MethodDelete, item, nil), http.StatusNoContent, nil)
+	esperar(t, requisitar(t, h, http.MethodGet, item, nil), http.StatusNotFound, nil)
+}
@@ -0,0 +1,274 @@
+// Package api expõe os módulos do organizador por uma API HTTP/JSON local,
+// para integrar scripts, lançadores e extensões de navegador. A API só
+// escuta em 127.0.0.1, exige um token e usa os mesmos handlers do app.
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/handlers"
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// portaPadrao é a porta usada quando nenhuma é informada
+const portaPadrao = 8737
+
+// Config é o conteúdo de api.json, na pasta de dados. A API atende sempre o
+// workspace ativo, então a configuração vale para todos.
+type Config struct {
+	Ativa bool   `json:"ativa"`
+	Porta int    `json:"porta"`
+	Token string `json:"token"`
+}
+
+// EstadoAPI é o que o frontend mostra sobre a API
+type EstadoAPI struct {
+	Config
+	Endereco string `json:"endereco,omitempty"` // Endereço em que a API está escutando
+	Erro     string `json:"erro,omitempty"`     // Por que a API ativa não pôde iniciar
+}
+
+// esquemaConfig registra as migrações de api.json
+var esquemaConfig = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+// Modulos são os handlers expostos pela API
+type Modulos struct {
+	Ideias       *handlers.IdeiasHandler
+	Links        *handlers.LinksHandler
+	Planejamento *handlers.PlanejamentoHandler
+	Passos       *handlers.PassosHandler
+	Calendario   *handlers.CalendarioHandler
+	Objetivos    *handlers.ObjetivosHandler
+}
+
+// Servidor liga e desliga a API conforme a configuração
+type Servidor struct {
+	ctx     context.Context
+	mu      sync.Mutex // protege http, endereco e erro
+	modulos Modulos
+	config  *storage.Store[Config]
+
+	// Lido a cada requisição, sem s.mu: parar segura s.mu enquanto espera
+	// as requisições em andamento
+	token atomic.Pointer[string]
+
+	http     *http.Server
+	endereco string
+	erro     string
+}
+
+// NovoServidor cria o servidor da API, ainda parado
+func NovoServidor(dataDir string, modulos Modulos) *Servidor {
+	return &Servidor{
+		modulos: modulos,
+		config: storage.NewStore(storage.NewBackendArquivos(dataDir), "api.json", esquemaConfig,
+			func() Config { return Config{Porta: portaPadrao} },
+			normalizarConfig,
+		),
+	}
+}
+
+// Startup é chamado quando o app inicia: liga a API se ela estiver ativa
+func (s *Servidor) Startup(ctx context.Context) {
+	s.ctx = ctx
+	cfg, err := s.config.Carregar()
+	if err != nil || !cfg.Ativa {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.iniciar(cfg); err != nil {
+		s.erro = err.Error()
+	}
+}
+
+// Shutdown desliga a API, esperando as requisições em andamento
+func (s *Servidor) Shutdown(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parar(ctx)
+}
+
+// ObterEstadoAPI retorna a configuração da API e se ela está escutando
+func (s *Servidor) ObterEstadoAPI() (EstadoAPI, error) {
+	cfg, err := s.config.Carregar()
+	if err != nil {
+		return EstadoAPI{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return EstadoAPI{Config: cfg, Endereco: s.endereco, Erro: s.erro}, nil
+}
+
+// AtivarAPI liga a API na porta informada (0 usa a porta padrão), gerando
+// um token na primeira vez
+func (s *Servidor) AtivarAPI(porta int) (EstadoAPI, error) {
+	if porta == 0 {
+		porta = portaPadrao
+	}
+	if porta < 1024 || porta > 65535 {
+		return EstadoAPI{}, fmt.Errorf("porta inválida: %d (use de 1024 a 65535)", porta)
+	}
+
+	var cfg Config
+	err := s.config.Atualizar(func(c *Config) error {
+		if c.Token == "" {
+			token, err := novoToken()
+			if err != nil {
+				return err
+			}
+			c.Token = token
+		}
+		c.Ativa = true
+		c.Porta = porta
+		cfg = *c
+		return nil
+	})
+	if err != nil {
+		return EstadoAPI{}, err
+	}
+
+	s.mu.Lock()
+	s.parar(context.Background())
+	err = s.iniciar(cfg)
+	s.erro = ""
+	if err != nil {
+		s.erro = err.Error()
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return EstadoAPI{}, err
+	}
+	return s.ObterEstadoAPI()
+}
+
+// DesativarAPI desliga a API. O token é mantido para quando ela voltar.
+func (s *Servidor) DesativarAPI() error {
+	err := s.config.Atualizar(func(c *Config) error {
+		c.Ativa = false
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parar(context.Background())
+	s.erro = ""
+	return nil
+}
+
+// GerarNovoToken troca o token da API. O anterior deixa de valer na hora.
+func (s *Servidor) GerarNovoToken() (EstadoAPI, error) {
+	token, err := novoToken()
+	if err != nil {
+		return EstadoAPI{}, err
+	}
+	err = s.config.Atualizar(func(c *Config) error {
+		c.Token = token
+		return nil
+	})
+	if err != nil {
+		return EstadoAPI{}, err
+	}
+	s.token.Store(&token)
+	return s.ObterEstadoAPI()
+}
+
+// Escutar liga a API com a configuração gravada, mesmo desativada, para a
+// linha de comando atender sem a janela do app. A porta informada (se não
+// for 0) vale só nesta execução. É uma função, e não um método, para que o
+// Wails não a ofereça ao frontend.
+func Escutar(s *Servidor, porta int) (EstadoAPI, error) {
+	cfg, err := s.config.Carregar()
+	if err != nil {
+		return EstadoAPI{}, err
+	}
+	if cfg.Token == "" {
+		estado, err := s.GerarNovoToken()
+		if err != nil {
+			return EstadoAPI{}, err
+		}
+		cfg = estado.Config
+	}
+	if porta != 0 {
+		cfg.Porta = porta
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.iniciar(cfg); err != nil {
+		return EstadoAPI{}, err
+	}
+	return EstadoAPI{Config: cfg, Endereco: s.endereco}, nil
+}
+
+// --- Funções internas ---
+
+// iniciar abre a porta e começa a atender. Quem chama deve segurar s.mu.
+func (s *Servidor) iniciar(cfg Config) error {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", cfg.Porta))
+	if err != nil {
+		return fmt.Errorf("não foi possível abrir a porta %d: %w", cfg.Porta, err)
+	}
+	s.token.Store(&cfg.Token)
+	s.endereco = "http://" + ln.Addr().String()
+	s.http = &http.Server{
+		Handler:           s.rotas(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func(srv *http.Server) {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.mu.Lock()
+			s.erro = err.Error()
+			s.mu.Unlock()
+		}
+	}(s.http)
+	return nil
+}
+
+// parar desliga a API, se estiver ligada. Quem chama deve segurar s.mu.
+func (s *Servidor) parar(ctx context.Context) {
+	if s.http == nil {
+		return
+	}
+	s.http.Shutdown(ctx)
+	s.http = nil
+	s.endereco = ""
+}
+
+// tokenAtual retorna o token que as requisições devem apresentar
+func (s *Servidor) tokenAtual() string {
+	if t := s.token.Load(); t != nil {
+		return *t
+	}
+	return ""
+}
+
+func normalizarConfig(c *Config) {
+	if c.Porta == 0 {
+		c.Porta = portaPadrao
+	}
+}
+
+// novoToken gera um token aleatório de 256 bits
+func novoToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -2,22 +2,116 @@ package app
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/user/tdah-organizer/internal/storage"
 )
 
 // App struct
 type App struct {
 	ctx       context.Context
-	AssetsDir string
+	dataDir   string
+	assetsDir atomic.Pointer[string] // pasta do workspace ativo
+
+	mu       sync.Mutex // serializa trocas de workspace
+	registro *storage.Store[registroWorkspaces]
+	backend  storage.Backend
+	cifra    *storage.Cifra // nil se o workspace ativo não usa criptografia
+	modulos  []Modulo
 }
 
-// NewApp cria uma nova instância da aplicação
-func NewApp(assetsDir string) *App {
-	return &App{
-		AssetsDir: assetsDir,
+// NewApp cria uma nova instância da aplicação, abrindo o workspace ativo
+// dentro da pasta de dados
+func NewApp(dataDir string) (*App, error) {
+	a := &App{
+		dataDir:  dataDir,
+		registro: novoRegistro(dataDir),
+	}
+
+	r, err := a.registro.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	dir := a.pastaWorkspace(r.Ativo)
+	if err := prepararPasta(dir); err != nil {
+		return nil, err
+	}
+	a.backend, a.cifra, err = abrirDados(dir)
+	if err != nil {
+		return nil, err
 	}
+	a.assetsDir.Store(&dir)
+	return a, nil
 }
 
 // Startup é chamado quando o app inicia
 func (a *App) Startup(ctx context.Context) {
 	a.ctx = ctx
 }
+
+// Shutdown é chamado quando o app fecha
+func (a *App) Shutdown(ctx context.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	fecharBackend(a.backend)
+}
+
+// AssetsDir retorna a pasta do workspace ativo (init, img, backups)
+func (a *App) AssetsDir() string {
+	return *a.assetsDir.Load()
+}
+
+// Backend retorna o armazenamento do workspace ativo
+func (a *App) Backend() storage.Backend {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.backend
+}
+
+// Registrar inclui handlers que devem acompanhar as trocas de workspace.
+// Os que gravam fora do backend recebem a cifra do workspace ativo.
+func (a *App) Registrar(modulos ...Modulo) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, m := range modulos {
+		if mc, ok := m.(ModuloCifrado); ok {
+			if err := mc.UsarCifra(a.cifra); err != nil {
+				return err
+			}
+		}
+	}
+	a.modulos = append(a.modulos, modulos...)
+	return nil
+}
+
+// AbrirBackend abre o armazenamento escolhido pela variável TDAH_STORAGE.
+// "sqlite" usa dados.db na pasta informada (importando os JSON de init na
+// primeira vez); qualquer outro valor mantém os arquivos JSON.
+func AbrirBackend(dir string) (storage.Backend, error) {
+	arquivos := storage.NewBackendArquivos(filepath.Join(dir, "init"))
+	if os.Getenv("TDAH_STORAGE") != "sqlite" {
+		return arquivos, nil
+	}
+
+	db, err := storage.NewBackendSQLite(filepath.Join(dir, "dados.db"))
+	if err != nil {
+		return nil, err
+	}
+	if nomes, err := db.Listar(); err == nil && len(nomes) == 0 {
+		if _, err := storage.Importar(db, arquivos); err != nil {
+			db.Fechar()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// fecharBackend fecha o backend se ele mantiver recursos abertos (ex: SQLite)
+func fecharBackend(backend storage.Backend) {
+	if b, ok := backend.(interface{ Fechar() error }); ok {
+		b.Fechar()
+	}
+}
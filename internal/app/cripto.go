@@ -0,0 +1,268 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Modos de criptografia de um workspace
+const (
+	CriptoDesligada = ""        // Nada é cifrado
+	CriptoBackups   = "backups" // Backups e exportações são cifrados
+	CriptoDados     = "dados"   // Também os arquivos de dados em uso
+)
+
+// configCripto é o conteúdo de cripto.json, na pasta do workspace. Guarda
+// só o sal e um texto cifrado para conferir a senha, nunca a senha.
+type configCripto struct {
+	Modo        string `json:"modo"`
+	Sal         []byte `json:"sal,omitempty"`
+	Verificador []byte `json:"verificador,omitempty"`
+}
+
+// EstadoCripto é o que o frontend precisa saber para pedir a senha
+type EstadoCripto struct {
+	Modo      string `json:"modo"`
+	Bloqueado bool   `json:"bloqueado"`
+}
+
+// esquemaCripto registra as migrações de cripto.json
+var esquemaCripto = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+// ModuloCifrado é um módulo que grava dados fora do backend (ex: backups) e
+// precisa da cifra do workspace para protegê-los
+type ModuloCifrado interface {
+	Modulo
+	// ApontarCifrado substitui Apontar, informando também a cifra do workspace
+	ApontarCifrado(assetsDir string, backend storage.Backend, cifra *storage.Cifra)
+	// UsarCifra troca a cifra do workspace atual
+	UsarCifra(cifra *storage.Cifra) error
+}
+
+func storeCripto(dir string) *storage.Store[configCripto] {
+	return storage.NewStore(storage.NewBackendArquivos(dir), "cripto.json", esquemaCripto,
+		func() configCripto { return configCripto{} },
+		nil,
+	)
+}
+
+// abrirDados abre o backend do workspace em dir e, se ele usa criptografia,
+// a sua cifra (ainda bloqueada). No modo CriptoDados o backend devolvido
+// decifra os documentos.
+func abrirDados(dir string) (storage.Backend, *storage.Cifra, error) {
+	cfg, err := storeCripto(dir).Carregar()
+	if err != nil {
+		return nil, nil, err
+	}
+	backend, err := AbrirBackend(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.Modo == CriptoDesligada {
+		return backend, nil, nil
+	}
+
+	cifra := storage.NovaCifra(cfg.Sal)
+	if cfg.Modo == CriptoDados {
+		return storage.NewBackendCifrado(backend, cifra), cifra, nil
+	}
+	return backend, cifra, nil
+}
+
+// EstadoCripto informa o modo de criptografia do workspace ativo e se a
+// senha ainda precisa ser informada
+func (a *App) EstadoCripto() EstadoCripto {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	cfg, err := storeCripto(a.AssetsDir()).Carregar()
+	if err != nil || a.cifra == nil {
+		return EstadoCripto{Modo: CriptoDesligada}
+	}
+	return EstadoCripto{Modo: cfg.Modo, Bloqueado: a.cifra.Bloqueada()}
+}
+
+// Desbloquear informa a senha do workspace ativo. O frontend recebe o evento
+// "cripto:desbloqueado" para recarregar os módulos.
+func (a *App) Desbloquear(senha string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cifra == nil {
+		return fmt.Errorf("este workspace não usa criptografia")
+	}
+	cfg, err := storeCripto(a.AssetsDir()).Carregar()
+	if err != nil {
+		return err
+	}
+	if err := a.cifra.Desbloquear(senha, cfg.Verificador); err != nil {
+		return err
+	}
+
+	err = a.usarCifra(a.cifra)
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "cripto:desbloqueado", cfg.Modo)
+	}
+	return err
+}
+
+// AtivarCriptografia protege o workspace ativo com uma senha, no modo
+// CriptoBackups ou CriptoDados. Com a criptografia já ativa, troca o modo
+// (a senha deve ser a atual). Os dados e backups existentes são convertidos.
+func (a *App) AtivarCriptografia(modo string, senha string) error {
+	if modo != CriptoBackups && modo != CriptoDados {
+		return fmt.Errorf("modo de criptografia inválido: %s", modo)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	dir := a.AssetsDir()
+	store := storeCripto(dir)
+	cfg, err := store.Carregar()
+	if err != nil {
+		return err
+	}
+
+	cifra := a.cifra
+	if cifra == nil {
+		sal, err := storage.NovoSal()
+		if err != nil {
+			return err
+		}
+		cifra = storage.NovaCifra(sal)
+		if err := cifra.Desbloquear(senha, nil); err != nil {
+			return err
+		}
+		verificador, err := cifra.Verificador()
+		if err != nil {
+			return err
+		}
+		cfg = configCripto{Sal: sal, Verificador: verificador}
+	} else if err := cifra.Desbloquear(senha, cfg.Verificador); err != nil {
+		return err
+	}
+
+	cfg.Modo = modo
+	return a.converter(dir, store, cfg, cifra)
+}
+
+// DesativarCriptografia volta a gravar dados e backups do workspace ativo em
+// claro. Exige a senha atual.
+func (a *App) DesativarCriptografia(senha string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cifra == nil {
+		return nil
+	}
+	dir := a.AssetsDir()
+	store := storeCripto(dir)
+	cfg, err := store.Carregar()
+	if err != nil {
+		return err
+	}
+	if err := a.cifra.Desbloquear(senha, cfg.Verificador); err != nil {
+		return err
+	}
+	return a.converter(dir, store, configCripto{}, nil)
+}
+
+// converter passa o workspace ativo para a configuração cfg: converte os
+// objetos dos backups, aponta os módulos para o novo backend e regrava cada
+// documento por ele. O novo backend lê os documentos nos dois formatos, então
+// os handlers não param durante a conversão. A configuração é gravada antes
+// quando a proteção aumenta e depois quando diminui, para que uma conversão
+// interrompida sempre reabra com um backend que entende os dois formatos.
+func (a *App) converter(dir string, store *storage.Store[configCripto], cfg configCripto, cifra *storage.Cifra) error {
+	atual, err := store.Carregar()
+	if err != nil {
+		return err
+	}
+	salvarAntes := nivelCripto(cfg.Modo) >= nivelCripto(atual.Modo)
+	if salvarAntes {
+		if err := store.Salvar(cfg); err != nil {
+			return err
+		}
+	}
+
+	interno := a.backend
+	if b, ok := interno.(*storage.BackendCifrado); ok {
+		interno = b.Interno()
+	}
+	novo := interno
+	switch {
+	case cfg.Modo == CriptoDados:
+		novo = storage.NewBackendCifrado(interno, cifra)
+	case atual.Modo == CriptoDados:
+		novo = storage.NewBackendDecifrando(interno, a.cifra)
+	}
+
+	// Objetos primeiro, enquanto o módulo de backup ainda conhece a cifra anterior
+	if err := a.usarCifra(cifra); err != nil {
+		return err
+	}
+	a.backend = novo
+	a.cifra = cifra
+	a.apontarModulos(dir, novo, cifra)
+
+	nomes, err := novo.Listar()
+	if err != nil {
+		return err
+	}
+	for _, nome := range nomes {
+		unlock := storage.Travar(novo.Chave(nome))
+		conteudo, err := novo.Ler(nome)
+		if err == nil {
+			err = novo.Gravar(nome, conteudo)
+		}
+		unlock()
+		if err != nil {
+			return fmt.Errorf("erro ao converter %s: %w", nome, err)
+		}
+	}
+
+	if !salvarAntes {
+		return store.Salvar(cfg)
+	}
+	return nil
+}
+
+// nivelCripto ordena os modos do menos para o mais protegido
+func nivelCripto(modo string) int {
+	switch modo {
+	case CriptoBackups:
+		return 1
+	case CriptoDados:
+		return 2
+	}
+	return 0
+}
+
+// apontarModulos passa todos os módulos para o workspace em dir
+func (a *App) apontarModulos(dir string, backend storage.Backend, cifra *storage.Cifra) {
+	for _, m := range a.modulos {
+		if mc, ok := m.(ModuloCifrado); ok {
+			mc.ApontarCifrado(dir, backend, cifra)
+		} else {
+			m.Apontar(dir, backend)
+		}
+	}
+}
+
+// usarCifra entrega a cifra do workspace ativo aos módulos que a usam
+func (a *App) usarCifra(cifra *storage.Cifra) error {
+	for _, m := range a.modulos {
+		if mc, ok := m.(ModuloCifrado); ok {
+			if err := mc.UsarCifra(cifra); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/handlers"
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// objetosCifrados conta os objetos de backup cifrados e em claro do workspace
+func objetosCifrados(t *testing.T, a *App) (cifrados, emClaro int) {
+	t.Helper()
+	dir := filepath.Join(a.AssetsDir(), "backups", "objetos")
+	entradas, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entradas {
+		dados, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if storage.Cifrado(dados) {
+			cifrados++
+		} else {
+			emClaro++
+		}
+	}
+	return cifrados, emClaro
+}
+
+// linksEmDisco retorna links_data.json como está gravado em init
+func linksEmDisco(t *testing.T, a *App) string {
+	t.Helper()
+	dados, err := os.ReadFile(filepath.Join(a.AssetsDir(), "init", "links_data.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(dados)
+}
+
+func TestConverterModosCripto(t *testing.T) {
+	t.Setenv("TDAH_STORAGE", "")
+	dataDir := t.TempDir()
+
+	abrir := func() (*App, *handlers.LinksHandler, *handlers.BackupHandler) {
+		t.Helper()
+		a, err := NewApp(dataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		links := handlers.NewLinksHandler(a.AssetsDir(), a.Backend())
+		backup := handlers.NewBackupHandler(a.AssetsDir(), a.Backend())
+		if err := a.Registrar(links, backup); err != nil {
+			t.Fatal(err)
+		}
+		return a, links, backup
+	}
+	conferirLinks := func(links *handlers.LinksHandler) {
+		t.Helper()
+		lista, err := links.CarregarLinks()
+		if err != nil || len(lista) != 1 || lista[0].URL != "https://exemplo.com" {
+			t.Errorf("links = %+v, %v", lista, err)
+		}
+	}
+
+	a, links, backup := abrir()
+	if err := links.AdicionarLink(handlers.Link{ID: "1", URL: "https://exemplo.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := backup.CriarBackup("", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Só backups: os dados continuam em claro e os objetos são cifrados
+	if err := a.AtivarCriptografia(CriptoBackups, "senha"); err != nil {
+		t.Fatal(err)
+	}
+	if cifrados, emClaro := objetosCifrados(t, a); cifrados == 0 || emClaro != 0 {
+		t.Errorf("objetos no modo backups: %d cifrados, %d em claro", cifrados, emClaro)
+	}
+	if bruto := linksEmDisco(t, a); !strings.Contains(bruto, "exemplo.com") {
+		t.Errorf("dados cifrados no modo backups: %s", bruto)
+	}
+
+	// Dados também: a senha é a atual, os documentos passam a ser cifrados
+	if err := a.AtivarCriptografia(CriptoDados, "outra"); !errors.Is(err, storage.ErrSenhaIncorreta) {
+		t.Errorf("trocar de modo com a senha errada = %v", err)
+	}
+	if err := a.AtivarCriptografia(CriptoDados, "senha"); err != nil {
+		t.Fatal(err)
+	}
+	if bruto := linksEmDisco(t, a); !strings.Contains(bruto, `"cifrado"`) || strings.Contains(bruto, "exemplo.com") {
+		t.Errorf("dados em claro no modo dados: %s", bruto)
+	}
+	conferirLinks(links)
+	a.Shutdown(context.Background())
+
+	// Reaberto, o workspace espera a senha
+	a, links, backup = abrir()
+	if estado := a.EstadoCripto(); estado.Modo != CriptoDados || !estado.Bloqueado {
+		t.Errorf("estado ao reabrir = %+v", estado)
+	}
+	if err := a.Desbloquear("outra"); !errors.Is(err, storage.ErrSenhaIncorreta) {
+		t.Errorf("desbloquear com a senha errada = %v", err)
+	}
+	if err := a.Desbloquear("senha"); err != nil {
+		t.Fatal(err)
+	}
+	conferirLinks(links)
+
+	// De volta a só backups e depois tudo em claro
+	if err := a.AtivarCriptografia(CriptoBackups, "senha"); err != nil {
+		t.Fatal(err)
+	}
+	if bruto := linksEmDisco(t, a); !strings.Contains(bruto, "exemplo.com") {
+		t.Errorf("dados ainda cifrados ao voltar para o modo backups: %s", bruto)
+	}
+	if err := a.DesativarCriptografia("outra"); !errors.Is(err, storage.ErrSenhaIncorreta) {
+		t.Errorf("desativar com a senha errada = %v", err)
+	}
+	if err := a.DesativarCriptografia("senha"); err != nil {
+		t.Fatal(err)
+	}
+	if estado := a.EstadoCripto(); estado.Modo != CriptoDesligada || estado.Bloqueado {
+		t.Errorf("estado depois de desativar = %+v", estado)
+	}
+	if cifrados, _ := objetosCifrados(t, a); cifrados != 0 {
+		t.Errorf("%d objetos continuam cifrados", cifrados)
+	}
+	conferirLinks(links)
+
+	// Os backups feitos antes de tudo continuam restauráveis
+	backups, err := backup.ListarBackups()
+	if err != nil || len(backups) == 0 {
+		t.Fatalf("backups = %v, %v", backups, err)
+	}
+	if err := links.DeletarLink("1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := backup.RestaurarBackup(backups[len(backups)-1].Nome); err != nil {
+		t.Fatal(err)
+	}
+	conferirLinks(links)
+	a.Shutdown(context.Background())
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// nomePastaDados é o nome da pasta do app dentro da pasta de dados do usuário
+const nomePastaDados = "tdah-organizer"
+
+// arquivoPortatil, ao lado do executável, força o modo portátil
+const arquivoPortatil = "portable"
+
+// arquivoTrava, na pasta de dados, é travado enquanto o app está aberto
+// (ver TravarDados)
+const arquivoTrava = ".trava"
+
+// esperaTrava é quanto esperar por quem segura a trava: um comando da linha
+// de comando costuma terminar antes disso
+const esperaTrava = 3 * time.Second
+
+// ErrDadosEmUso indica que outro processo (a janela do app ou um comando
+// como "tdah-organizer api") está usando a pasta de dados
+var ErrDadosEmUso = errors.New("os dados estão em uso por outro processo do organizador")
+
+// ResolverDataDir decide onde ficam os dados do app, nesta ordem:
+//  1. --data-dir <pasta> (ou --data-dir=<pasta>) nos argumentos
+//  2. variável de ambiente TDAH_DATA_DIR
+//  3. modo portátil: arquivo "portable" ao lado do executável
+//  4. $XDG_DATA_HOME/tdah-organizer (ou o equivalente do sistema)
+//  5. pasta assets ao lado do executável, se a anterior não puder ser criada
+//
+// No caso 4, uma pasta assets antiga ao lado do executável é copiada para o
+// novo local na primeira execução.
+func ResolverDataDir(args []string) (string, error) {
+	if dir := argumentoDataDir(args); dir != "" {
+		return filepath.Abs(dir)
+	}
+	if dir := os.Getenv("TDAH_DATA_DIR"); dir != "" {
+		return filepath.Abs(dir)
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	exeDir := filepath.Dir(exePath)
+	portatil := filepath.Join(exeDir, "assets")
+
+	if _, err := os.Stat(filepath.Join(exeDir, arquivoPortatil)); err == nil {
+		return portatil, nil
+	}
+
+	dir, err := pastaDadosUsuario()
+	if err != nil {
+		return portatil, nil
+	}
+	if err := migrarPortatil(portatil, dir); err != nil {
+		return "", fmt.Errorf("erro ao migrar %s para %s: %w", portatil, dir, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return portatil, nil
+	}
+	return dir, nil
+}
+
+// TravarDados reserva a pasta de dados para este processo até Soltar. O app
+// a segura enquanto está aberto, e a linha de comando antes de alterar
+// dados, para que um não grave por cima do outro. Retorna ErrDadosEmUso se
+// outro processo continuar com ela depois de alguns segundos.
+func TravarDados(dataDir string) (*storage.TravaProcesso, error) {
+	return travarDados(dataDir, esperaTrava)
+}
+
+// TentarTravarDados é como TravarDados, mas retorna ErrDadosEmUso na hora,
+// sem esperar o outro processo
+func TentarTravarDados(dataDir string) (*storage.TravaProcesso, error) {
+	return travarDados(dataDir, 0)
+}
+
+func travarDados(dataDir string, espera time.Duration) (*storage.TravaProcesso, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, err
+	}
+	trava, err := storage.TravarProcesso(filepath.Join(dataDir, arquivoTrava), espera)
+	if errors.Is(err, storage.ErrTravaOcupada) {
+		return nil, fmt.Errorf("%w (%s)", ErrDadosEmUso, dataDir)
+	}
+	return trava, err
+}
+
+// argumentoDataDir procura --data-dir nos argumentos, ignorando os demais
+// (o Wails e alguns sistemas passam argumentos próprios)
+func argumentoDataDir(args []string) string {
+	for i, arg := range args {
+		for _, prefixo := range []string{"--data-dir", "-data-dir"} {
+			if arg == prefixo && i+1 < len(args) {
+				return args[i+1]
+			}
+			if valor, ok := strings.CutPrefix(arg, prefixo+"="); ok {
+				return valor
+			}
+		}
+	}
+	return ""
+}
+
+// pastaDadosUsuario retorna a pasta de dados do app para o usuário atual
+func pastaDadosUsuario() (string, error) {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, nomePastaDados), nil
+	}
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(base, nomePastaDados), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", nomePastaDados), nil
+}
+
+// migrarPortatil copia a pasta portátil para destino, se destino ainda não
+// existir. A cópia é feita em uma pasta temporária e renomeada no final, para
+// que uma migração interrompida seja refeita na próxima execução. A pasta
+// antiga é mantida (o local de instalação pode ser somente leitura).
+func migrarPortatil(origem, destino string) error {
+	if _, err := os.Stat(destino); err == nil {
+		return nil
+	}
+	if info, err := os.Stat(origem); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destino), 0755); err != nil {
+		return err
+	}
+	tmp := destino + ".migrando"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+
+	err := filepath.WalkDir(origem, func(caminho string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(origem, caminho)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(tmp, rel)
+		if d.IsDir() {
+			return os.MkdirAll(dst, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return storage.CopiarAtomico(caminho, dst)
+	})
+	if err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	return os.Rename(tmp, destino)
+}
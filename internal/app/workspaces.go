@@ -0,0 +1,248 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// workspacePadrao é o workspace que usa a própria pasta de dados, onde os
+// dados ficavam antes de existirem workspaces
+const workspacePadrao = "padrao"
+
+// Workspace é um conjunto separado de dados (ex: "Trabalho" e "Pessoal")
+type Workspace struct {
+	ID    string `json:"id"`
+	Nome  string `json:"nome"`
+	Ativo bool   `json:"ativo"`
+}
+
+// registroWorkspaces é o conteúdo de workspaces.json
+type registroWorkspaces struct {
+	Ativo      string      `json:"ativo"`
+	Workspaces []Workspace `json:"workspaces"`
+}
+
+// esquemaWorkspaces registra as migrações de workspaces.json
+var esquemaWorkspaces = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+// Modulo é um handler cujos dados pertencem ao workspace ativo
+type Modulo interface {
+	Apontar(assetsDir string, backend storage.Backend)
+}
+
+// novoRegistro cria o store de workspaces.json na pasta de dados
+func novoRegistro(dataDir string) *storage.Store[registroWorkspaces] {
+	return storage.NewStore(storage.NewBackendArquivos(dataDir), "workspaces.json", esquemaWorkspaces,
+		func() registroWorkspaces {
+			// Primeira execução: só o workspace padrão, ativo
+			r := registroWorkspaces{}
+			normalizarRegistro(&r)
+			return r
+		},
+		normalizarRegistro,
+	)
+}
+
+// normalizarRegistro garante que o workspace padrão exista e que o ativo
+// aponte para um workspace conhecido
+func normalizarRegistro(r *registroWorkspaces) {
+	temPadrao := false
+	for _, w := range r.Workspaces {
+		if w.ID == workspacePadrao {
+			temPadrao = true
+		}
+	}
+	if !temPadrao {
+		r.Workspaces = append([]Workspace{{ID: workspacePadrao, Nome: "Padrão"}}, r.Workspaces...)
+	}
+
+	if _, err := buscarWorkspace(*r, r.Ativo); err != nil {
+		r.Ativo = workspacePadrao
+	}
+	for i := range r.Workspaces {
+		// Ativo é derivado de r.Ativo, que é quem vale
+		r.Workspaces[i].Ativo = r.Workspaces[i].ID == r.Ativo
+	}
+}
+
+// pastaWorkspace retorna a pasta de dados de um workspace
+func (a *App) pastaWorkspace(id string) string {
+	if id == workspacePadrao {
+		return a.dataDir
+	}
+	return filepath.Join(a.dataDir, "workspaces", id)
+}
+
+// ListarWorkspaces retorna todos os workspaces
+func (a *App) ListarWorkspaces() ([]Workspace, error) {
+	r, err := a.registro.Carregar()
+	if err != nil {
+		return []Workspace{}, err
+	}
+	return r.Workspaces, nil
+}
+
+// WorkspaceAtivo retorna o workspace em uso
+func (a *App) WorkspaceAtivo() (Workspace, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r, err := a.registro.Carregar()
+	if err != nil {
+		return Workspace{}, err
+	}
+	return buscarWorkspace(r, r.Ativo)
+}
+
+// CriarWorkspace cria um workspace vazio (sem trocar para ele)
+func (a *App) CriarWorkspace(nome string) (Workspace, error) {
+	nome = strings.TrimSpace(nome)
+	if nome == "" {
+		return Workspace{}, fmt.Errorf("o nome do workspace não pode ser vazio")
+	}
+
+	w := Workspace{ID: uuid.New().String()[:8], Nome: nome}
+	if err := prepararPasta(a.pastaWorkspace(w.ID)); err != nil {
+		return Workspace{}, err
+	}
+	err := a.registro.Atualizar(func(r *registroWorkspaces) error {
+		r.Workspaces = append(r.Workspaces, w)
+		return nil
+	})
+	if err != nil {
+		os.RemoveAll(a.pastaWorkspace(w.ID))
+		return Workspace{}, err
+	}
+	return w, nil
+}
+
+// RenomearWorkspace troca o nome exibido de um workspace. A pasta não muda,
+// então renomear o workspace ativo não interrompe nada.
+func (a *App) RenomearWorkspace(id string, nome string) error {
+	nome = strings.TrimSpace(nome)
+	if nome == "" {
+		return fmt.Errorf("o nome do workspace não pode ser vazio")
+	}
+	return a.registro.Atualizar(func(r *registroWorkspaces) error {
+		for i := range r.Workspaces {
+			if r.Workspaces[i].ID == id {
+				r.Workspaces[i].Nome = nome
+				return nil
+			}
+		}
+		return fmt.Errorf("workspace não encontrado: %s", id)
+	})
+}
+
+// DeletarWorkspace apaga um workspace e todos os seus dados.
+// O workspace padrão e o workspace ativo não podem ser apagados.
+func (a *App) DeletarWorkspace(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if id == workspacePadrao {
+		return fmt.Errorf("o workspace padrão não pode ser apagado")
+	}
+	err := a.registro.Atualizar(func(r *registroWorkspaces) error {
+		if r.Ativo == id {
+			return fmt.Errorf("troque de workspace antes de apagar o atual")
+		}
+		filtrados := []Workspace{}
+		for _, w := range r.Workspaces {
+			if w.ID != id {
+				filtrados = append(filtrados, w)
+			}
+		}
+		if len(filtrados) == len(r.Workspaces) {
+			return fmt.Errorf("workspace não encontrado: %s", id)
+		}
+		r.Workspaces = filtrados
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(a.pastaWorkspace(id))
+}
+
+// TrocarWorkspace passa todos os módulos para outro workspace sem reiniciar
+// o app. O frontend recebe o evento "workspace:trocado" para recarregar.
+func (a *App) TrocarWorkspace(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r, err := a.registro.Carregar()
+	if err != nil {
+		return err
+	}
+	w, err := buscarWorkspace(r, id)
+	if err != nil {
+		return err
+	}
+	if r.Ativo == id {
+		return nil
+	}
+
+	dir := a.pastaWorkspace(id)
+	if err := prepararPasta(dir); err != nil {
+		return err
+	}
+	backend, cifra, err := abrirDados(dir)
+	if err != nil {
+		return err
+	}
+
+	err = a.registro.Atualizar(func(r *registroWorkspaces) error {
+		r.Ativo = id
+		return nil
+	})
+	if err != nil {
+		fecharBackend(backend)
+		return err
+	}
+
+	// Cada Apontar espera as operações em andamento do módulo terminarem,
+	// então o backend antigo pode ser fechado em seguida. Um workspace com
+	// criptografia abre bloqueado: o frontend pede a senha (EstadoCripto).
+	anterior := a.backend
+	a.assetsDir.Store(&dir)
+	a.backend = backend
+	a.cifra = cifra
+	a.apontarModulos(dir, backend, cifra)
+	fecharBackend(anterior)
+
+	w.Ativo = true
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "workspace:trocado", w)
+	}
+	return nil
+}
+
+func buscarWorkspace(r registroWorkspaces, id string) (Workspace, error) {
+	for _, w := range r.Workspaces {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return Workspace{}, fmt.Errorf("workspace não encontrado: %s", id)
+}
+
+// prepararPasta cria a pasta de um workspace com as subpastas init e img
+func prepararPasta(dir string) error {
+	for _, sub := range []string{"init", "img"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
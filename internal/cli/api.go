@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/user/tdah-organizer/internal/api"
+)
+
+const usoAPI = `Uso: tdah-organizer api [--porta <porta>]
+
+Atende a API HTTP local (em 127.0.0.1) até receber Ctrl+C, sem abrir a
+janela. Usa a porta e o token configurados no app; o token é gerado na
+primeira vez. A descrição OpenAPI fica em /api/openapi.json.
+`
+
+func executarAPI(o *organizador, args []string) error {
+	fs := novasOpcoes("api")
+	porta := fs.Int("porta", 0, "porta")
+	fs.IntVar(porta, "port", 0, "porta")
+	args, err := analisar(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := argumentos(args); err != nil {
+		return err
+	}
+	if *porta != 0 && (*porta < 1024 || *porta > 65535) {
+		return erroUso("porta inválida: %d (use de 1024 a 65535)", *porta)
+	}
+
+	servidor := api.NovoServidor(o.dataDir, api.Modulos{
+		Ideias:       o.ideias,
+		Links:        o.links,
+		Planejamento: o.planejamento,
+		Passos:       o.passos,
+		Calendario:   o.calendario,
+		Objetivos:    o.objetivos,
+	})
+	estado, err := api.Escutar(servidor, *porta)
+	if err != nil {
+		return err
+	}
+	defer servidor.Shutdown(context.Background())
+
+	if err := o.mostrar(estado, "API em %s\ntoken: %s", estado.Endereco, estado.Token); err != nil {
+		return err
+	}
+	ctx, parar := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer parar()
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/tdah-organizer/internal/handlers"
+)
+
+const usoBackup = `Uso: tdah-organizer backup <ação>
+
+  criar [--nota <texto>] [--fixar]   cria um backup (se algo mudou desde o último)
+  listar
+  verificar [<nome>]                 confere um backup, ou todos
+  fixar <nome>                       a retenção não apaga backups fixados
+  soltar <nome>
+  restaurar <nome> [<arquivo>...]    restaura todos os dados, ou só os arquivos informados
+  desfazer                           desfaz a última restauração ou importação
+`
+
+func executarBackup(o *organizador, args []string) error {
+	criar := func(args []string) error {
+		fs := novasOpcoes("criar")
+		nota := fs.String("nota", "", "nota")
+		fixar := fs.Bool("fixar", false, "fixar")
+		args, err := analisar(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		info, err := o.backup.CriarBackup(*nota, *fixar)
+		if err != nil {
+			return err
+		}
+		if info.Nome == "" {
+			return o.mostrar(info, "nenhum dado para salvar ainda")
+		}
+		return o.mostrar(info, "%s", info.Nome)
+	}
+
+	listar := func(args []string) error {
+		args, err := analisar(novasOpcoes("listar"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		backups, err := o.backup.ListarBackups()
+		if err != nil {
+			return err
+		}
+		linhas := [][]string{}
+		for _, b := range backups {
+			situacao := []string{}
+			if b.Fixado {
+				situacao = append(situacao, "fixado")
+			}
+			if b.Verificacao != nil && !b.Verificacao.Integro {
+				situacao = append(situacao, "com problemas")
+			}
+			linhas = append(linhas, []string{b.Nome, b.Label, strings.Join(situacao, ", "), b.Nota})
+		}
+		return o.listar(backups, []string{"NOME", "DATA", "SITUAÇÃO", "NOTA"}, linhas)
+	}
+
+	verificar := func(args []string) error {
+		args, err := analisar(novasOpcoes("verificar"), args)
+		if err != nil {
+			return err
+		}
+		if len(args) > 1 {
+			return erroUso("argumento a mais: %s", args[1])
+		}
+		var resultados []handlers.VerificacaoBackup
+		if len(args) == 1 {
+			v, err := o.backup.VerificarBackup(args[0])
+			if err != nil {
+				return err
+			}
+			resultados = append(resultados, v)
+		} else if resultados, err = o.backup.VerificarTodos(); err != nil {
+			return err
+		}
+
+		if o.json {
+			err = o.escreverJSON(resultados)
+		} else {
+			for _, v := range resultados {
+				if v.Integro {
+					fmt.Fprintf(o.saida, "%s: ok\n", v.Backup)
+					continue
+				}
+				fmt.Fprintf(o.saida, "%s: com problemas\n", v.Backup)
+				for _, p := range v.Problemas {
+					fmt.Fprintf(o.saida, "  %s\n", p)
+				}
+			}
+		}
+		// Um backup com problemas é uma falha, para scripts e cron
+		for _, v := range resultados {
+			if !v.Integro && err == nil {
+				err = fmt.Errorf("há backups com problemas")
+			}
+		}
+		return err
+	}
+
+	fixar := func(fixado bool) func(args []string) error {
+		return func(args []string) error {
+			args, err := analisar(novasOpcoes("fixar"), args)
+			if err != nil {
+				return err
+			}
+			if err := argumentos(args, "o nome do backup"); err != nil {
+				return err
+			}
+			return o.backup.FixarBackup(args[0], fixado)
+		}
+	}
+
+	restaurar := func(args []string) error {
+		args, err := analisar(novasOpcoes("restaurar"), args)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return erroUso("faltou o nome do backup")
+		}
+		return o.backup.RestaurarArquivos(args[0], args[1:])
+	}
+
+	desfazer := func(args []string) error {
+		args, err := analisar(novasOpcoes("desfazer"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		return o.backup.DesfazerRestauracao()
+	}
+
+	return acao(args, map[string]func([]string) error{
+		"criar": criar, "create": criar,
+		"listar": listar, "list": listar, "ls": listar,
+		"verificar": verificar, "verify": verificar,
+		"fixar": fixar(true), "pin": fixar(true),
+		"soltar": fixar(false), "unpin": fixar(false),
+		"restaurar": restaurar, "restore": restaurar,
+		"desfazer": desfazer, "undo": desfazer,
+	})
+}
+
+const usoExportar = `Uso: tdah-organizer exportar <arquivo.zip>
+
+Exporta todos os dados e imagens do workspace para um único .zip.
+`
+
+func executarExportar(o *organizador, args []string) error {
+	args, err := analisar(novasOpcoes("exportar"), args)
+	if err != nil {
+		return err
+	}
+	if err := argumentos(args, "o arquivo .zip"); err != nil {
+		return err
+	}
+	caminho, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+	if err := o.backup.ExportarArquivo(caminho); err != nil {
+		return err
+	}
+	return o.mostrar(map[string]string{"arquivo": caminho}, "%s", caminho)
+}
+
+const usoImportar = `Uso: tdah-organizer importar <arquivo.zip> [--mesclar]
+
+Importa um .zip exportado, substituindo os dados atuais: os módulos que não
+estão no arquivo ficam vazios. Com --mesclar, só os itens cujo ID ainda não
+existe são acrescentados. Os dados atuais são
+salvos antes, e "tdah-organizer backup desfazer" volta a eles.
+`
+
+func executarImportar(o *organizador, args []string) error {
+	fs := novasOpcoes("importar")
+	mesclar := fs.Bool("mesclar", false, "mesclar")
+	fs.BoolVar(mesclar, "merge", false, "mesclar")
+	args, err := analisar(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := argumentos(args, "o arquivo .zip"); err != nil {
+		return err
+	}
+	modo := handlers.ImportarSubstituindo
+	if *mesclar {
+		modo = handlers.ImportarMesclando
+	}
+	return o.backup.ImportarArquivo(args[0], modo)
+}
@@ -0,0 +1,370 @@
+// Package cli implementa o modo de linha de comando do organizador: os
+// mesmos handlers do app, sobre a mesma pasta de dados, sem abrir a janela.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/user/tdah-organizer/internal/app"
+	"github.com/user/tdah-organizer/internal/handlers"
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// comando é um subcomando de primeiro nível (ex: "tarefa")
+type comando struct {
+	nomes    []string // nome em português primeiro, depois os apelidos
+	uso      string
+	executar func(o *organizador, args []string) error
+	// leitura informa se os argumentos só leem os dados, o que dispensa a
+	// trava da pasta de dados (nil: o comando sempre pode alterá-los)
+	leitura func(args []string) bool
+}
+
+// comandos lista os subcomandos na ordem em que aparecem na ajuda
+var comandos = []comando{
+	{[]string{"tarefa", "task"}, usoTarefa, executarTarefa, acoesDeLeitura("listar", "list", "ls")},
+	{[]string{"evento", "event"}, usoEvento, executarEvento,
+		acoesDeLeitura("listar", "list", "ls", "buscar", "search", "lembretes", "reminders", "exportar", "export")},
+	{[]string{"assinatura", "subscription"}, usoAssinatura, executarAssinatura, acoesDeLeitura("listar", "list", "ls")},
+	{[]string{"link"}, usoLink, executarLink, acoesDeLeitura("listar", "list", "ls")},
+	{[]string{"objetivo", "goal"}, usoObjetivo, executarObjetivo, acoesDeLeitura("listar", "list", "ls")},
+	{[]string{"passo", "step"}, usoPasso, executarPasso, acoesDeLeitura("listar", "list", "ls")},
+	{[]string{"backup"}, usoBackup, executarBackup, acoesDeLeitura("listar", "list", "ls")},
+	{[]string{"exportar", "export"}, usoExportar, executarExportar, func([]string) bool { return true }},
+	{[]string{"importar", "import"}, usoImportar, executarImportar, nil},
+	{[]string{"api"}, usoAPI, executarAPI, nil},
+}
+
+// acoesDeLeitura marca como só leitura as ações informadas e os pedidos de
+// ajuda
+func acoesDeLeitura(acoes ...string) func(args []string) bool {
+	return func(args []string) bool {
+		if len(args) == 0 {
+			return true
+		}
+		switch args[0] {
+		case "-h", "--help", "-help", "ajuda", "help":
+			return true
+		}
+		return slices.Contains(acoes, args[0])
+	}
+}
+
+// errUso indica argumentos inválidos: a ajuda do comando é mostrada
+type errUso struct{ msg string }
+
+func (e errUso) Error() string { return e.msg }
+
+func erroUso(formato string, args ...any) error {
+	return errUso{fmt.Sprintf(formato, args...)}
+}
+
+// globais são as opções aceitas em qualquer posição da linha de comando
+type globais struct {
+	dataDir string
+	json    bool
+}
+
+// organizador reúne o app e os handlers abertos para um comando
+type organizador struct {
+	app          *app.App
+	dataDir      string
+	ideias       *handlers.IdeiasHandler
+	planejamento *handlers.PlanejamentoHandler
+	calendario   *handlers.CalendarioHandler
+	links        *handlers.LinksHandler
+	objetivos    *handlers.ObjetivosHandler
+	passos       *handlers.PassosHandler
+	backup       *handlers.BackupHandler
+
+	trava *storage.TravaProcesso // nil ao só ler com o app aberto
+	saida io.Writer
+	json  bool
+}
+
+// Executar roda o subcomando em args (os argumentos sem o nome do
+// programa). Retorna false se args não tiver um subcomando, e o app deve
+// abrir a janela normalmente; senão retorna o código de saída.
+func Executar(args []string, saida, erros io.Writer) (bool, int) {
+	g, resto := separarGlobais(args)
+	if len(resto) == 0 {
+		return false, 0
+	}
+
+	switch resto[0] {
+	case "ajuda", "help", "-h", "--help", "-help":
+		ajuda(saida, resto[1:])
+		return true, 0
+	}
+	c, ok := buscarComando(resto[0])
+	if !ok {
+		// Argumentos que não são nossos (o Wails e alguns sistemas passam os seus)
+		return false, 0
+	}
+
+	escrever := c.leitura == nil || !c.leitura(resto[1:])
+	o, err := abrir(g, saida, escrever)
+	if err != nil {
+		fmt.Fprintln(erros, "erro:", err)
+		return true, 1
+	}
+	defer o.fechar()
+
+	err = c.executar(o, resto[1:])
+	var uso errUso
+	switch {
+	case err == nil:
+		return true, 0
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Fprint(saida, c.uso)
+		return true, 0
+	case errors.As(err, &uso):
+		fmt.Fprintln(erros, "erro:", err)
+		fmt.Fprint(erros, c.uso)
+		return true, 2
+	}
+	fmt.Fprintln(erros, "erro:", err)
+	return true, 1
+}
+
+// separarGlobais tira de args as opções --data-dir e --json
+func separarGlobais(args []string) (globais, []string) {
+	var g globais
+	resto := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--json" || arg == "-json":
+			g.json = true
+		case (arg == "--data-dir" || arg == "-data-dir") && i+1 < len(args):
+			g.dataDir = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--data-dir=") || strings.HasPrefix(arg, "-data-dir="):
+			_, g.dataDir, _ = strings.Cut(arg, "=")
+		default:
+			resto = append(resto, arg)
+		}
+	}
+	return g, resto
+}
+
+func buscarComando(nome string) (comando, bool) {
+	for _, c := range comandos {
+		for _, n := range c.nomes {
+			if n == nome {
+				return c, true
+			}
+		}
+	}
+	return comando{}, false
+}
+
+// abrir abre o workspace ativo da pasta de dados, como o app faz ao iniciar.
+// Num workspace protegido por senha, ela vem da variável TDAH_SENHA. Para
+// escrever, a pasta de dados é travada: com o app aberto, o comando é
+// recusado em vez de gravar por cima do que está na janela. Os comandos que
+// só leem também a travam se ela estiver livre; com o app aberto, leem por
+// um backend que recusa qualquer gravação.
+func abrir(g globais, saida io.Writer, escrever bool) (*organizador, error) {
+	var args []string
+	if g.dataDir != "" {
+		args = []string{"--data-dir", g.dataDir}
+	}
+	dataDir, err := app.ResolverDataDir(args)
+	if err != nil {
+		return nil, err
+	}
+	var trava *storage.TravaProcesso
+	if escrever {
+		trava, err = app.TravarDados(dataDir)
+	} else {
+		trava, err = app.TentarTravarDados(dataDir)
+	}
+	somenteLeitura := false
+	switch {
+	case errors.Is(err, app.ErrDadosEmUso) && !escrever:
+		// O outro processo já preparou a pasta ao abrir: só ler, sem mexer
+		// em nada (nem renomear ou pôr em quarentena documentos)
+		somenteLeitura = true
+	case errors.Is(err, app.ErrDadosEmUso):
+		return nil, fmt.Errorf("%w: feche a janela do app (ou o \"tdah-organizer api\") para alterar os dados pela linha de comando; com o app aberto, use a API local", err)
+	case err != nil:
+		return nil, err
+	}
+	a, err := app.NewApp(dataDir)
+	if err != nil {
+		trava.Soltar()
+		return nil, err
+	}
+	assetsDir := a.AssetsDir()
+	backend := a.Backend()
+	if somenteLeitura {
+		backend = storage.NewBackendSomenteLeitura(backend)
+	}
+
+	o := &organizador{
+		app:          a,
+		dataDir:      dataDir,
+		ideias:       handlers.NewIdeiasHandler(assetsDir, backend),
+		planejamento: handlers.NewPlanejamentoHandler(assetsDir, backend),
+		calendario:   handlers.NewCalendarioHandler(assetsDir, backend),
+		links:        handlers.NewLinksHandler(assetsDir, backend),
+		objetivos:    handlers.NewObjetivosHandler(assetsDir, backend),
+		passos:       handlers.NewPassosHandler(assetsDir, backend),
+		backup:       handlers.NewBackupHandler(assetsDir, backend),
+		trava:        trava,
+		saida:        saida,
+		json:         g.json,
+	}
+	err = a.Registrar(o.ideias, o.planejamento, o.calendario, o.links, o.objetivos, o.passos, o.backup)
+	if err == nil && a.EstadoCripto().Bloqueado {
+		senha := os.Getenv("TDAH_SENHA")
+		if senha == "" {
+			err = fmt.Errorf("este workspace é protegido por senha: informe-a na variável TDAH_SENHA")
+		} else {
+			err = a.Desbloquear(senha)
+		}
+	}
+	if err != nil {
+		o.fechar()
+		return nil, err
+	}
+	return o, nil
+}
+
+// fechar fecha o backend (ex: o banco SQLite) do workspace e solta a trava
+// da pasta de dados
+func (o *organizador) fechar() {
+	o.app.Shutdown(context.Background())
+	o.trava.Soltar()
+}
+
+// listar escreve v em JSON (com --json) ou como tabela, uma linha por item
+func (o *organizador) listar(v any, cabecalho []string, linhas [][]string) error {
+	if o.json {
+		return o.escreverJSON(v)
+	}
+	if len(linhas) == 0 {
+		fmt.Fprintln(o.saida, "(nenhum)")
+		return nil
+	}
+	w := tabwriter.NewWriter(o.saida, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(cabecalho, "\t"))
+	for _, l := range linhas {
+		fmt.Fprintln(w, strings.Join(l, "\t"))
+	}
+	return w.Flush()
+}
+
+// mostrar escreve v em JSON (com --json) ou a mensagem informada
+func (o *organizador) mostrar(v any, formato string, args ...any) error {
+	if o.json {
+		return o.escreverJSON(v)
+	}
+	_, err := fmt.Fprintf(o.saida, formato+"\n", args...)
+	return err
+}
+
+func (o *organizador) escreverJSON(v any) error {
+	e := json.NewEncoder(o.saida)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
+
+// analisar lê as opções de fs em qualquer posição (o pacote flag para no
+// primeiro argumento que não é opção) e retorna os demais argumentos
+func analisar(fs *flag.FlagSet, args []string) ([]string, error) {
+	posicionais := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUso{err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return posicionais, nil
+		}
+		posicionais = append(posicionais, args[0])
+		args = args[1:]
+	}
+}
+
+// novasOpcoes cria o conjunto de opções de uma ação. Os erros de análise
+// são mostrados por Executar, junto com a ajuda do comando.
+func novasOpcoes(nome string) *flag.FlagSet {
+	fs := flag.NewFlagSet(nome, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// acao escolhe a ação pelo primeiro argumento (ex: "listar" em "tarefa listar")
+func acao(args []string, acoes map[string]func(args []string) error) error {
+	if len(args) == 0 {
+		return erroUso("informe a ação")
+	}
+	switch args[0] {
+	case "-h", "--help", "-help", "ajuda", "help":
+		return flag.ErrHelp
+	}
+	f, ok := acoes[args[0]]
+	if !ok {
+		return erroUso("ação desconhecida: %s", args[0])
+	}
+	return f(args[1:])
+}
+
+// argumentos exige exatamente n argumentos, nomeados na mensagem de erro
+func argumentos(args []string, nomes ...string) error {
+	if len(args) < len(nomes) {
+		return erroUso("faltou %s", nomes[len(args)])
+	}
+	if len(args) > len(nomes) {
+		return erroUso("argumento a mais: %s", args[len(nomes)])
+	}
+	return nil
+}
+
+func ajuda(saida io.Writer, args []string) {
+	if len(args) > 0 {
+		if c, ok := buscarComando(args[0]); ok {
+			fmt.Fprint(saida, c.uso)
+			return
+		}
+	}
+	fmt.Fprint(saida, `Uso: tdah-organizer [--data-dir <pasta>] [--json] <comando> <ação> [opções]
+
+Sem comando, abre a janela do app. Os comandos usam o workspace ativo da
+pasta de dados; num workspace protegido por senha, informe-a em TDAH_SENHA.
+Com a janela aberta, só os comandos que leem (ex: "tarefa listar")
+funcionam; os que alteram dados são recusados.
+
+Comandos:
+  tarefa    (task)    tarefas do quadro de planejamento
+  evento    (event)   eventos do calendário
+  assinatura          calendários .ics assinados (só leitura)
+  link                links salvos
+  objetivo  (goal)    objetivos e seu progresso
+  passo     (step)    passos do objetivo
+  backup              criar, listar, verificar e restaurar backups
+  exportar  (export)  exportar os dados para um .zip
+  importar  (import)  importar um .zip exportado
+  api                 atender a API HTTP local sem abrir a janela
+
+Opções globais:
+  --data-dir <pasta>  pasta de dados (como no app)
+  --json              saída em JSON
+
+Use "tdah-organizer ajuda <comando>" para ver as ações de cada comando.
+`)
+}
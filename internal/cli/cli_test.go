@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/app"
+)
+
+// estadoPasta retorna o conteúdo de cada arquivo em dir
+func estadoPasta(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	estado := map[string]string{}
+	err := filepath.WalkDir(dir, func(caminho string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		conteudo, err := os.ReadFile(caminho)
+		estado[caminho] = string(conteudo)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return estado
+}
+
+func TestExecutarTravaDosDados(t *testing.T) {
+	t.Setenv("TDAH_STORAGE", "")
+	t.Setenv("TDAH_SENHA", "")
+
+	casos := []struct {
+		nome      string
+		args      []string
+		appAberto bool
+		codigo    int
+		altera    bool
+	}{
+		{"leitura com o app aberto", []string{"tarefa", "listar"}, true, 0, false},
+		{"leitura de dados corrompidos com o app aberto", []string{"link", "listar"}, true, 1, false},
+		{"leitura com a pasta livre", []string{"tarefa", "listar"}, false, 0, true},
+		{"escrita com o app aberto", []string{"tarefa", "adicionar", "Nova"}, true, 1, false},
+		{"escrita com a pasta livre", []string{"tarefa", "adicionar", "Nova"}, false, 0, true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			dataDir := t.TempDir()
+			a, err := app.NewApp(dataDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			init := filepath.Join(a.AssetsDir(), "init")
+			a.Shutdown(context.Background())
+			// Um documento com o nome antigo (renomeado ao abrir o calendário)
+			// e um ilegível (posto em quarentena ao ser lido)
+			os.WriteFile(filepath.Join(init, "calendario_assinaturas.json"), []byte(`[]`), 0644)
+			os.WriteFile(filepath.Join(init, "links_data.json"), []byte(`{"dados":[`), 0644)
+
+			if c.appAberto {
+				trava, err := app.TravarDados(dataDir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer trava.Soltar()
+			}
+			antes := estadoPasta(t, dataDir)
+
+			var saida, erros bytes.Buffer
+			args := append([]string{"--data-dir", dataDir}, c.args...)
+			tratado, codigo := Executar(args, &saida, &erros)
+			if !tratado || codigo != c.codigo {
+				t.Fatalf("Executar = %v, %d; esperado true, %d\nsaída: %s\nerros: %s",
+					tratado, codigo, c.codigo, saida.String(), erros.String())
+			}
+			if c.appAberto && c.codigo != 0 && c.args[1] == "adicionar" &&
+				!strings.Contains(erros.String(), "em uso") {
+				t.Errorf("erro = %q, esperado o aviso de dados em uso", erros.String())
+			}
+
+			depois := estadoPasta(t, dataDir)
+			if alterou := !maps.Equal(antes, depois); alterou != c.altera {
+				t.Errorf("pasta de dados alterada = %v, esperado %v\nantes: %v\ndepois: %v", alterou, c.altera, antes, depois)
+			}
+		})
+	}
+}
@@ -0,0 +1,857 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/handlers"
+)
+
+const usoTarefa = `Uso: tdah-organizer tarefa <ação>
+
+  listar [--status objetivo|fazendo|feito]
+  adicionar <título> [--descricao <texto>] [--status objetivo|fazendo|feito]
+  mover <id> <objetivo|fazendo|feito>
+  remover <id>
+`
+
+func executarTarefa(o *organizador, args []string) error {
+	listar := func(args []string) error {
+		fs := novasOpcoes("listar")
+		status := fs.String("status", "", "coluna")
+		args, err := analisar(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		if *status != "" && !statusValido(*status) {
+			return erroUso("status inválido: %s", *status)
+		}
+
+		todas, err := o.planejamento.ListarTarefas()
+		if err != nil {
+			return err
+		}
+		tarefas := []handlers.Tarefa{}
+		linhas := [][]string{}
+		for _, t := range todas {
+			if *status == "" || t.Status == *status {
+				tarefas = append(tarefas, t)
+				linhas = append(linhas, []string{t.ID, t.Status, t.Titulo})
+			}
+		}
+		return o.listar(tarefas, []string{"ID", "STATUS", "TÍTULO"}, linhas)
+	}
+
+	adicionar := func(args []string) error {
+		fs := novasOpcoes("adicionar")
+		descricao := fs.String("descricao", "", "descrição")
+		status := fs.String("status", "objetivo", "coluna")
+		args, err := analisar(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o título"); err != nil {
+			return err
+		}
+		if !statusValido(*status) {
+			return erroUso("status inválido: %s", *status)
+		}
+
+		t := handlers.Tarefa{
+			ID:        handlers.NovoID("tarefa"),
+			Titulo:    strings.TrimSpace(args[0]),
+			Descricao: *descricao,
+			Status:    *status,
+			CreatedAt: handlers.CriadoAgora(),
+		}
+		if err := o.planejamento.AdicionarTarefa(t); err != nil {
+			return err
+		}
+		return o.mostrar(t, "%s", t.ID)
+	}
+
+	mover := func(args []string) error {
+		args, err := analisar(novasOpcoes("mover"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID", "o status de destino"); err != nil {
+			return err
+		}
+		if !statusValido(args[1]) {
+			return erroUso("status inválido: %s", args[1])
+		}
+		t, err := o.buscarTarefa(args[0])
+		if err != nil {
+			return err
+		}
+		return o.planejamento.MoverTarefa(t.ID, t.Status, args[1])
+	}
+
+	remover := func(args []string) error {
+		args, err := analisar(novasOpcoes("remover"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID"); err != nil {
+			return err
+		}
+		t, err := o.buscarTarefa(args[0])
+		if err != nil {
+			return err
+		}
+		return o.planejamento.DeletarTarefa(t.ID, t.Status)
+	}
+
+	return acao(args, map[string]func([]string) error{
+		"listar": listar, "list": listar, "ls": listar,
+		"adicionar": adicionar, "add": adicionar,
+		"mover": mover, "move": mover,
+		"remover": remover, "remove": remover, "rm": remover,
+	})
+}
+
+func (o *organizador) buscarTarefa(id string) (handlers.Tarefa, error) {
+	tarefas, err := o.planejamento.ListarTarefas()
+	if err != nil {
+		return handlers.Tarefa{}, err
+	}
+	for _, t := range tarefas {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return handlers.Tarefa{}, fmt.Errorf("tarefa não encontrada: %s", id)
+}
+
+func statusValido(status string) bool {
+	for _, s := range handlers.StatusTarefas {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+const usoEvento = `Uso: tdah-organizer evento <ação>
+
+  listar [--hoje | --semana | --data AAAA-MM-DD]
+  adicionar <título> --data AAAA-MM-DD [--hora HH:MM] [--descricao <texto>] [--cor #rrggbb]
+            [--repetir <RRULE>] [--lembrar 10m,1h,1d]
+  pular <id> <AAAA-MM-DD>            tira uma data de um evento que se repete
+  buscar <texto>                     eventos com todas as palavras no título ou na descrição
+  lembretes [--dias N]               próximos lembretes (padrão: 7 dias)
+  remover <id>
+  exportar <arquivo.ics>             exporta os eventos no formato iCalendar
+  importar <arquivo.ics>             importa eventos; os já importados são atualizados
+
+--repetir aceita regras da RFC 5545 com FREQ (DAILY, WEEKLY, MONTHLY ou
+YEARLY), INTERVAL, BYDAY, UNTIL e COUNT, por exemplo
+"FREQ=WEEKLY;BYDAY=MO,TH" ou "FREQ=MONTHLY;BYDAY=-1FR;COUNT=6". Com
+--hoje, --semana ou --data, listar mostra cada repetição no seu dia.
+
+--lembrar recebe antecedências separadas por vírgula, em minutos (m),
+horas (h) ou dias (d); 0 avisa na hora do evento. Os lembretes aparecem
+como notificação enquanto o app está aberto.
+`
+
+func executarEvento(o *organizador, args []string) error {
+	listar := func(args []string) error {
+		fs := novasOpcoes("listar")
+		hoje := fs.Bool("hoje", false, "só hoje")
+		semana := fs.Bool("semana", false, "esta semana")
+		fs.BoolVar(semana, "week", false, "esta semana")
+		data := fs.String("data", "", "dia")
+		args, err := analisar(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+
+		de, ate := "", ""
+		dia := time.Now()
+		switch {
+		case *data != "":
+			if _, err := time.Parse(time.DateOnly, *data); err != nil {
+				return erroUso("data inválida: %s (use AAAA-MM-DD)", *data)
+			}
+			de, ate = *data, *data
+		case *hoje:
+			de, ate = dia.Format(time.DateOnly), dia.Format(time.DateOnly)
+		case *semana:
+			// Semana de segunda a domingo
+			inicio := dia.AddDate(0, 0, -((int(dia.Weekday()) + 6) % 7))
+			de, ate = inicio.Format(time.DateOnly), inicio.AddDate(0, 0, 6).Format(time.DateOnly)
+		}
+
+		if de != "" {
+			ocorrencias, err := o.calendario.ListarOcorrencias(de, ate)
+			if err != nil {
+				return err
+			}
+			linhas := [][]string{}
+			for _, e := range ocorrencias {
+				linhas = append(linhas, []string{e.ID, e.Data, e.Hora, e.Titulo})
+			}
+			return o.listar(ocorrencias, []string{"ID", "DATA", "HORA", "TÍTULO"}, linhas)
+		}
+
+		eventos, err := o.calendario.CarregarEventos()
+		if err != nil {
+			return err
+		}
+		handlers.OrdenarEventos(eventos)
+		linhas := [][]string{}
+		for _, e := range eventos {
+			repete := ""
+			if e.Recorrencia != nil {
+				repete = e.Recorrencia.RRule()
+			}
+			linhas = append(linhas, []string{e.ID, e.Data, e.Hora, e.Titulo, repete})
+		}
+		return o.listar(eventos, []string{"ID", "DATA", "HORA", "TÍTULO", "REPETE"}, linhas)
+	}
+
+	adicionar := func(args []string) error {
+		fs := novasOpcoes("adicionar")
+		data := fs.String("data", "", "dia")
+		hora := fs.String("hora", "", "horário")
+		descricao := fs.String("descricao", "", "descrição")
+		cor := fs.String("cor", handlers.CorEventoPadrao, "cor")
+		repetir := fs.String("repetir", "", "regra de recorrência")
+		fs.StringVar(repetir, "repeat", "", "regra de recorrência")
+		lembrar := fs.String("lembrar", "", "antecedências dos lembretes")
+		fs.StringVar(lembrar, "remind", "", "antecedências dos lembretes")
+		args, err := analisar(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o título"); err != nil {
+			return err
+		}
+		if _, err := time.Parse(time.DateOnly, *data); err != nil {
+			return erroUso("informe --data no formato AAAA-MM-DD")
+		}
+		if *hora != "" {
+			if _, err := time.Parse("15:04", *hora); err != nil {
+				return erroUso("hora inválida: %s (use HH:MM)", *hora)
+			}
+		}
+
+		e := handlers.Evento{
+			ID:        handlers.NovoID("evento"),
+			Titulo:    strings.TrimSpace(args[0]),
+			Data:      *data,
+			Hora:      *hora,
+			Descricao: *descricao,
+			Cor:       *cor,
+			CreatedAt: handlers.CriadoAgora(),
+		}
+		if *repetir != "" {
+			if e.Recorrencia, err = handlers.LerRRule(*repetir); err != nil {
+				return errUso{err.Error()}
+			}
+		}
+		if e.Lembretes, err = lerAntecedencias(*lembrar); err != nil {
+			return err
+		}
+		if err := o.calendario.AdicionarEvento(e); err != nil {
+			return err
+		}
+		return o.mostrar(e, "%s", e.ID)
+	}
+
+	pular := func(args []string) error {
+		args, err := analisar(novasOpcoes("pular"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID", "a data"); err != nil {
+			return err
+		}
+		return o.calendario.PularOcorrencia(args[0], args[1])
+	}
+
+	buscar := func(args []string) error {
+		args, err := analisar(novasOpcoes("buscar"), args)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return erroUso("informe o texto da busca")
+		}
+		eventos, err := o.calendario.BuscarEventos(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		linhas := [][]string{}
+		for _, e := range eventos {
+			linhas = append(linhas, []string{e.ID, e.Data, e.Hora, e.Titulo})
+		}
+		return o.listar(eventos, []string{"ID", "DATA", "HORA", "TÍTULO"}, linhas)
+	}
+
+	lembretes := func(args []string) error {
+		fs := novasOpcoes("lembretes")
+		dias := fs.Int("dias", 7, "dias à frente")
+		args, err := analisar(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		proximos, err := o.calendario.ProximosLembretes(*dias)
+		if err != nil {
+			return err
+		}
+		linhas := [][]string{}
+		for _, l := range proximos {
+			linhas = append(linhas, []string{l.EventoID, l.Data, l.Hora, descreverAntecedencia(l.Minutos), l.Titulo})
+		}
+		return o.listar(proximos, []string{"EVENTO", "DATA", "HORA", "ANTES", "TÍTULO"}, linhas)
+	}
+
+	remover := func(args []string) error {
+		args, err := analisar(novasOpcoes("remover"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID"); err != nil {
+			return err
+		}
+		eventos, err := o.calendario.CarregarEventos()
+		if err != nil {
+			return err
+		}
+		if !contem(eventos, args[0], func(e handlers.Evento) string { return e.ID }) {
+			return fmt.Errorf("evento não encontrado: %s", args[0])
+		}
+		return o.calendario.DeletarEvento(args[0])
+	}
+
+	exportar := func(args []string) error {
+		args, err := analisar(novasOpcoes("exportar"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o arquivo .ics"); err != nil {
+			return err
+		}
+		caminho, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+		if err := o.calendario.ExportarArquivoICS(caminho); err != nil {
+			return err
+		}
+		return o.mostrar(map[string]string{"arquivo": caminho}, "%s", caminho)
+	}
+
+	importar := func(args []string) error {
+		args, err := analisar(novasOpcoes("importar"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o arquivo .ics"); err != nil {
+			return err
+		}
+		r, err := o.calendario.ImportarArquivoICS(args[0])
+		if err != nil {
+			return err
+		}
+		if o.json {
+			return o.escreverJSON(r)
+		}
+		fmt.Fprintf(o.saida, "%d novo(s), %d atualizado(s)\n", r.Novos, r.Atualizados)
+		for _, aviso := range r.Avisos {
+			fmt.Fprintf(o.saida, "  aviso: %s\n", aviso)
+		}
+		return nil
+	}
+
+	return acao(args, map[string]func([]string) error{
+		"listar": listar, "list": listar, "ls": listar,
+		"adicionar": adicionar, "add": adicionar,
+		"pular": pular, "skip": pular,
+		"buscar": buscar, "search": buscar,
+		"lembretes": lembretes, "reminders": lembretes,
+		"exportar": exportar, "export": exportar,
+		"importar": importar, "import": importar,
+		"remover": remover, "remove": remover, "rm": remover,
+	})
+}
+
+// lerAntecedencias converte "10m,1h,1d" em minutos de antecedência
+func lerAntecedencias(texto string) ([]int, error) {
+	minutos := []int{}
+	for _, parte := range strings.Split(texto, ",") {
+		parte = strings.TrimSpace(parte)
+		if parte == "" {
+			continue
+		}
+		numero, unidade := parte, 1
+		for sufixo, m := range map[string]int{"m": 1, "h": 60, "d": 24 * 60} {
+			if strings.HasSuffix(parte, sufixo) {
+				numero, unidade = strings.TrimSuffix(parte, sufixo), m
+			}
+		}
+		n, err := strconv.Atoi(numero)
+		if err != nil || n < 0 {
+			return nil, erroUso("antecedência inválida: %s (use por exemplo 10m, 2h ou 1d)", parte)
+		}
+		minutos = append(minutos, n*unidade)
+	}
+	if len(minutos) == 0 {
+		return nil, nil
+	}
+	return minutos, nil
+}
+
+// descreverAntecedencia mostra os minutos na maior unidade exata
+func descreverAntecedencia(minutos int) string {
+	switch {
+	case minutos == 0:
+		return "na hora"
+	case minutos%(24*60) == 0:
+		return fmt.Sprintf("%dd", minutos/(24*60))
+	case minutos%60 == 0:
+		return fmt.Sprintf("%dh", minutos/60)
+	}
+	return fmt.Sprintf("%dm", minutos)
+}
+
+const usoAssinatura = `Uso: tdah-organizer assinatura <ação>
+
+  listar
+  adicionar <arquivo.ics | URL> [--nome <nome>] [--cor #rrggbb] [--minutos <n>]
+  remover <id>
+  atualizar                          baixa agora todos os calendários assinados
+
+Os eventos dos calendários assinados aparecem em "evento listar" com --hoje,
+--semana ou --data, mas não podem ser alterados por aqui. Com o app aberto,
+eles são baixados de novo a cada hora (ou a cada --minutos).
+`
+
+func executarAssinatura(o *organizador, args []string) error {
+	listarEstados := func(estados []handlers.EstadoAssinatura) error {
+		linhas := [][]string{}
+		for _, a := range estados {
+			situacao := fmt.Sprintf("%d eventos", a.Eventos)
+			if a.Erro != "" {
+				situacao = "erro: " + a.Erro
+			}
+			linhas = append(linhas, []string{a.ID, a.Nome, a.Origem, situacao})
+		}
+		return o.listar(estados, []string{"ID", "NOME", "ORIGEM", "SITUAÇÃO"}, linhas)
+	}
+
+	listar := func(args []string) error {
+		args, err := analisar(novasOpcoes("listar"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		estados, err := o.calendario.ListarAssinaturas()
+		if err != nil {
+			return err
+		}
+		return listarEstados(estados)
+	}
+
+	adicionar := func(args []string) error {
+		fs := novasOpcoes("adicionar")
+		nome := fs.String("nome", "", "nome")
+		cor := fs.String("cor", "", "cor")
+		minutos := fs.Int("minutos", 0, "intervalo")
+		args, err := analisar(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o arquivo ou a URL"); err != nil {
+			return err
+		}
+		origem := args[0]
+		if !strings.Contains(origem, "://") {
+			// Caminho relativo ao diretório atual, não ao do app
+			if origem, err = filepath.Abs(origem); err != nil {
+				return err
+			}
+		}
+		estado, err := o.calendario.AdicionarAssinatura(handlers.Assinatura{
+			Nome: *nome, Origem: origem, Cor: *cor, Minutos: *minutos,
+		})
+		if err != nil {
+			return err
+		}
+		return o.mostrar(estado, "%s (%d eventos)", estado.ID, estado.Eventos)
+	}
+
+	remover := func(args []string) error {
+		args, err := analisar(novasOpcoes("remover"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID"); err != nil {
+			return err
+		}
+		return o.calendario.RemoverAssinatura(args[0])
+	}
+
+	atualizar := func(args []string) error {
+		args, err := analisar(novasOpcoes("atualizar"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		estados, err := o.calendario.AtualizarAssinaturas()
+		if err != nil {
+			return err
+		}
+		return listarEstados(estados)
+	}
+
+	return acao(args, map[string]func([]string) error{
+		"listar": listar, "list": listar, "ls": listar,
+		"adicionar": adicionar, "add": adicionar,
+		"remover": remover, "remove": remover, "rm": remover,
+		"atualizar": atualizar, "refresh": atualizar,
+	})
+}
+
+const usoLink = `Uso: tdah-organizer link <ação>
+
+  listar
+  adicionar <url> [--titulo <título>] [--descricao <texto>]
+  remover <id>
+`
+
+func executarLink(o *organizador, args []string) error {
+	listar := func(args []string) error {
+		args, err := analisar(novasOpcoes("listar"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		links, err := o.links.CarregarLinks()
+		if err != nil {
+			return err
+		}
+		linhas := [][]string{}
+		for _, l := range links {
+			linhas = append(linhas, []string{l.ID, l.Title, l.URL})
+		}
+		return o.listar(links, []string{"ID", "TÍTULO", "URL"}, linhas)
+	}
+
+	adicionar := func(args []string) error {
+		fs := novasOpcoes("adicionar")
+		titulo := fs.String("titulo", "", "título")
+		descricao := fs.String("descricao", "", "descrição")
+		args, err := analisar(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "a URL"); err != nil {
+			return err
+		}
+
+		l := handlers.Link{
+			ID:          handlers.NovoID("link"),
+			Title:       strings.TrimSpace(*titulo),
+			URL:         strings.TrimSpace(args[0]),
+			Description: *descricao,
+			CreatedAt:   handlers.CriadoAgora(),
+		}
+		if l.Title == "" {
+			l.Title = l.URL
+		}
+		if err := o.links.AdicionarLink(l); err != nil {
+			return err
+		}
+		return o.mostrar(l, "%s", l.ID)
+	}
+
+	remover := func(args []string) error {
+		args, err := analisar(novasOpcoes("remover"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID"); err != nil {
+			return err
+		}
+		links, err := o.links.CarregarLinks()
+		if err != nil {
+			return err
+		}
+		if !contem(links, args[0], func(l handlers.Link) string { return l.ID }) {
+			return fmt.Errorf("link não encontrado: %s", args[0])
+		}
+		return o.links.DeletarLink(args[0])
+	}
+
+	return acao(args, map[string]func([]string) error{
+		"listar": listar, "list": listar, "ls": listar,
+		"adicionar": adicionar, "add": adicionar,
+		"remover": remover, "remove": remover, "rm": remover,
+	})
+}
+
+const usoObjetivo = `Uso: tdah-organizer objetivo <ação>
+
+  listar
+  adicionar <título> [--prazo AAAA-MM-DD]
+  progresso <id> <0-100>
+  concluir <id>
+  remover <id>
+`
+
+func executarObjetivo(o *organizador, args []string) error {
+	listar := func(args []string) error {
+		args, err := analisar(novasOpcoes("listar"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		objetivos, err := o.objetivos.CarregarObjetivos()
+		if err != nil {
+			return err
+		}
+		linhas := [][]string{}
+		for _, obj := range objetivos {
+			progresso := fmt.Sprintf("%.0f%%", obj.Progresso)
+			if obj.Concluido {
+				progresso = "concluído"
+			}
+			linhas = append(linhas, []string{obj.ID, progresso, obj.Prazo, obj.Titulo})
+		}
+		return o.listar(objetivos, []string{"ID", "PROGRESSO", "PRAZO", "TÍTULO"}, linhas)
+	}
+
+	adicionar := func(args []string) error {
+		fs := novasOpcoes("adicionar")
+		prazo := fs.String("prazo", "", "prazo")
+		args, err := analisar(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o título"); err != nil {
+			return err
+		}
+
+		obj := handlers.Objetivo{
+			ID:        handlers.NovoID("obj"),
+			Titulo:    strings.TrimSpace(args[0]),
+			CreatedAt: handlers.CriadoAgora(),
+		}
+		if *prazo != "" {
+			// O app mostra e grava o prazo como DD/MM/AAAA
+			d, err := time.Parse(time.DateOnly, *prazo)
+			if err != nil {
+				return erroUso("prazo inválido: %s (use AAAA-MM-DD)", *prazo)
+			}
+			obj.Prazo = d.Format("02/01/2006")
+		}
+		if err := o.objetivos.AdicionarObjetivo(obj); err != nil {
+			return err
+		}
+		return o.mostrar(obj, "%s", obj.ID)
+	}
+
+	progresso := func(args []string) error {
+		args, err := analisar(novasOpcoes("progresso"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID", "o progresso"); err != nil {
+			return err
+		}
+		p, err := strconv.ParseFloat(strings.TrimSuffix(args[1], "%"), 64)
+		if err != nil || p < 0 || p > 100 {
+			return erroUso("progresso inválido: %s (use de 0 a 100)", args[1])
+		}
+		obj, err := o.buscarObjetivo(args[0])
+		if err != nil {
+			return err
+		}
+		obj.Progresso = p
+		obj.Concluido = p == 100
+		return o.objetivos.AtualizarObjetivo(obj)
+	}
+
+	concluir := func(args []string) error {
+		args, err := analisar(novasOpcoes("concluir"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID"); err != nil {
+			return err
+		}
+		obj, err := o.buscarObjetivo(args[0])
+		if err != nil {
+			return err
+		}
+		obj.Progresso = 100
+		obj.Concluido = true
+		return o.objetivos.AtualizarObjetivo(obj)
+	}
+
+	remover := func(args []string) error {
+		args, err := analisar(novasOpcoes("remover"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID"); err != nil {
+			return err
+		}
+		if _, err := o.buscarObjetivo(args[0]); err != nil {
+			return err
+		}
+		return o.objetivos.DeletarObjetivo(args[0])
+	}
+
+	return acao(args, map[string]func([]string) error{
+		"listar": listar, "list": listar, "ls": listar,
+		"adicionar": adicionar, "add": adicionar,
+		"progresso": progresso, "progress": progresso,
+		"concluir": concluir, "done": concluir,
+		"remover": remover, "remove": remover, "rm": remover,
+	})
+}
+
+func (o *organizador) buscarObjetivo(id string) (handlers.Objetivo, error) {
+	objetivos, err := o.objetivos.CarregarObjetivos()
+	if err != nil {
+		return handlers.Objetivo{}, err
+	}
+	for _, obj := range objetivos {
+		if obj.ID == id {
+			return obj, nil
+		}
+	}
+	return handlers.Objetivo{}, fmt.Errorf("objetivo não encontrado: %s", id)
+}
+
+const usoPasso = `Uso: tdah-organizer passo <ação>
+
+  listar
+  adicionar <descrição>
+  concluir <id>        marca ou desmarca o passo como concluído
+  remover <id>
+`
+
+func executarPasso(o *organizador, args []string) error {
+	listar := func(args []string) error {
+		args, err := analisar(novasOpcoes("listar"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args); err != nil {
+			return err
+		}
+		passos, err := o.passos.CarregarPassos()
+		if err != nil {
+			return err
+		}
+		linhas := [][]string{}
+		for _, p := range passos {
+			feito := "[ ]"
+			if p.Concluido {
+				feito = "[x]"
+			}
+			linhas = append(linhas, []string{p.ID, strconv.Itoa(p.Ordem), feito, p.Descricao})
+		}
+		return o.listar(passos, []string{"ID", "ORDEM", "", "DESCRIÇÃO"}, linhas)
+	}
+
+	adicionar := func(args []string) error {
+		args, err := analisar(novasOpcoes("adicionar"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "a descrição"); err != nil {
+			return err
+		}
+		p := handlers.Passo{
+			ID:        handlers.NovoID("passo"),
+			Descricao: strings.TrimSpace(args[0]),
+			CreatedAt: handlers.CriadoAgora(),
+		}
+		if err := o.passos.AdicionarPasso(p); err != nil {
+			return err
+		}
+		return o.mostrar(p, "%s", p.ID)
+	}
+
+	concluir := func(args []string) error {
+		args, err := analisar(novasOpcoes("concluir"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID"); err != nil {
+			return err
+		}
+		if err := o.exigirPasso(args[0]); err != nil {
+			return err
+		}
+		return o.passos.ToggleConcluido(args[0])
+	}
+
+	remover := func(args []string) error {
+		args, err := analisar(novasOpcoes("remover"), args)
+		if err != nil {
+			return err
+		}
+		if err := argumentos(args, "o ID"); err != nil {
+			return err
+		}
+		if err := o.exigirPasso(args[0]); err != nil {
+			return err
+		}
+		return o.passos.DeletarPasso(args[0])
+	}
+
+	return acao(args, map[string]func([]string) error{
+		"listar": listar, "list": listar, "ls": listar,
+		"adicionar": adicionar, "add": adicionar,
+		"concluir": concluir, "done": concluir,
+		"remover": remover, "remove": remover, "rm": remover,
+	})
+}
+
+func (o *organizador) exigirPasso(id string) error {
+	passos, err := o.passos.CarregarPassos()
+	if err != nil {
+		return err
+	}
+	if !contem(passos, id, func(p handlers.Passo) string { return p.ID }) {
+		return fmt.Errorf("passo não encontrado: %s", id)
+	}
+	return nil
+}
+
+// contem informa se algum item da lista tem o ID informado
+func contem[E any](lista []E, id string, idDe func(E) string) bool {
+	for _, e := range lista {
+		if idDe(e) == id {
+			return true
+		}
+	}
+	return false
+}
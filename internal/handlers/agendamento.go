@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Agendamento define de quanto em quanto tempo um backup é feito enquanto o
+// app está aberto. Só é criado um backup novo se algo mudou desde o último.
+type Agendamento struct {
+	Minutos int `json:"minutos"` // Zero desliga os backups periódicos
+}
+
+// agendamentoPadrao faz um backup por hora
+var agendamentoPadrao = Agendamento{Minutos: 60}
+
+// esquemaAgendamento registra as migrações de agendamento.json
+var esquemaAgendamento = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+// storeAgendamento abre o agendamento gravado na pasta de backups do workspace
+func storeAgendamento(backupDir string) *storage.Store[Agendamento] {
+	return storage.NewStore(storage.NewBackendArquivos(backupDir), "agendamento.json", esquemaAgendamento,
+		func() Agendamento { return agendamentoPadrao },
+		nil,
+	)
+}
+
+func (a Agendamento) validar() error {
+	if a.Minutos < 0 {
+		return fmt.Errorf("o intervalo de backup não pode ser negativo")
+	}
+	return nil
+}
+
+// ObterAgendamento retorna o intervalo dos backups periódicos do workspace
+func (h *BackupHandler) ObterAgendamento() (Agendamento, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return storeAgendamento(h.backupDir).Carregar()
+}
+
+// SalvarAgendamento grava o intervalo dos backups periódicos. A contagem
+// recomeça a partir de agora.
+func (h *BackupHandler) SalvarAgendamento(agendamento Agendamento) error {
+	if err := agendamento.validar(); err != nil {
+		return err
+	}
+	h.mu.RLock()
+	err := storeAgendamento(h.backupDir).Salvar(agendamento)
+	h.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	h.reagendar()
+	return nil
+}
+
+// Shutdown para os backups periódicos, esperando um que esteja em andamento
+// terminar. Deve ser chamado antes de o backend ser fechado.
+func (h *BackupHandler) Shutdown(ctx context.Context) {
+	h.pararUma.Do(func() { close(h.parar) })
+	h.agendador.Wait()
+}
+
+// iniciarAgendador começa os backups periódicos, que param com Shutdown ou
+// quando o contexto do Wails termina
+func (h *BackupHandler) iniciarAgendador(ctx context.Context) {
+	h.agendador.Add(1)
+	go func() {
+		defer h.agendador.Done()
+		for {
+			var disparo <-chan time.Time
+			var timer *time.Timer
+			if intervalo := h.intervaloAgendado(); intervalo > 0 {
+				timer = time.NewTimer(intervalo)
+				disparo = timer.C
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.parar:
+				return
+			case <-h.reagendado:
+				// Intervalo ou workspace mudou: recomeçar a contagem
+			case <-disparo:
+				h.backupAgendado()
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+	}()
+}
+
+// reagendar avisa o agendador que o intervalo (ou o workspace) mudou
+func (h *BackupHandler) reagendar() {
+	select {
+	case h.reagendado <- struct{}{}:
+	default:
+		// Já há um aviso pendente
+	}
+}
+
+// intervaloAgendado lê o intervalo do workspace ativo (zero: desligado)
+func (h *BackupHandler) intervaloAgendado() time.Duration {
+	agendamento, err := h.ObterAgendamento()
+	if err != nil {
+		agendamento = agendamentoPadrao
+	}
+	return time.Duration(agendamento.Minutos) * time.Minute
+}
+
+// backupAgendado faz o backup periódico, se algo mudou desde o último, e
+// avisa o frontend com o evento "backup:criado" (ou "backup:erro", com a
+// mensagem, se falhar)
+func (h *BackupHandler) backupAgendado() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cifra != nil && h.cifra.Bloqueada() {
+		return
+	}
+	defer storage.Travar(h.backupDir)()
+
+	var ultimo string
+	if backups, err := h.listarBackups(); err == nil && len(backups) > 0 {
+		ultimo = backups[0].Nome
+	}
+	info, err := h.fazerBackupInterno()
+	if err != nil {
+		if h.ctx != nil {
+			runtime.LogError(h.ctx, "Erro no backup periódico: "+err.Error())
+			runtime.EventsEmit(h.ctx, "backup:erro", err.Error())
+		}
+		return
+	}
+	h.limparAntigos()
+	// Mesmo sem backup novo: um destino que estava desconectado é atualizado
+	h.copiarParaDestinos()
+	if info.Nome != "" && info.Nome != ultimo && h.ctx != nil {
+		runtime.EventsEmit(h.ctx, "backup:criado", info)
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// tamanhoMaximoNota limita a nota de um backup, que aparece na lista
+const tamanhoMaximoNota = 200
+
+// AnotacaoBackup é o que o usuário registrou sobre um backup: uma nota (ex:
+// "antes de reorganizar o quadro") e se ele está fixado. Backups fixados
+// nunca são apagados pela retenção, nem na pasta local nem nos destinos.
+type AnotacaoBackup struct {
+	Nota   string `json:"nota,omitempty"`
+	Fixado bool   `json:"fixado,omitempty"`
+}
+
+// esquemaAnotacoes registra as migrações de anotacoes.json
+var esquemaAnotacoes = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+// storeAnotacoes abre as anotações dos backups, gravadas na pasta de backups
+// do workspace
+func storeAnotacoes(backupDir string) *storage.Store[map[string]AnotacaoBackup] {
+	return storage.NewStore(storage.NewBackendArquivos(backupDir), "anotacoes.json", esquemaAnotacoes,
+		func() map[string]AnotacaoBackup { return map[string]AnotacaoBackup{} },
+		nil,
+	)
+}
+
+// FixarBackup fixa ou solta um backup. Um backup solto volta a seguir a
+// política de retenção.
+func (h *BackupHandler) FixarBackup(nome string, fixado bool) error {
+	if strings.ContainsAny(nome, "/\\") {
+		return fmt.Errorf("nome de backup inválido")
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backupDir)()
+
+	if _, err := os.Stat(filepath.Join(h.backupDir, nome)); err != nil {
+		return fmt.Errorf("backup não encontrado: %s", nome)
+	}
+	err := storeAnotacoes(h.backupDir).Atualizar(func(anotacoes *map[string]AnotacaoBackup) error {
+		a := (*anotacoes)[nome]
+		a.Fixado = fixado
+		guardarAnotacao(*anotacoes, nome, a)
+		return nil
+	})
+	if err != nil || fixado {
+		return err
+	}
+	h.limparAntigos()
+	return nil
+}
+
+// --- Funções internas ---
+
+// anotarBackup registra a nota e a fixação pedidas ao criar um backup (que é
+// sempre novo quando há nota ou fixação, ver CriarBackup)
+func (h *BackupHandler) anotarBackup(nome string, nota string, fixar bool) error {
+	nota = strings.TrimSpace(nota)
+	if nota == "" && !fixar {
+		return nil
+	}
+	return storeAnotacoes(h.backupDir).Atualizar(func(anotacoes *map[string]AnotacaoBackup) error {
+		a := (*anotacoes)[nome]
+		if nota != "" {
+			a.Nota = nota
+		}
+		a.Fixado = a.Fixado || fixar
+		guardarAnotacao(*anotacoes, nome, a)
+		return nil
+	})
+}
+
+// fixados retorna os backups fixados
+func (h *BackupHandler) fixados() map[string]bool {
+	anotacoes, _ := storeAnotacoes(h.backupDir).Carregar()
+	fixados := make(map[string]bool)
+	for nome, a := range anotacoes {
+		if a.Fixado {
+			fixados[nome] = true
+		}
+	}
+	return fixados
+}
+
+// validarNota confere a nota antes de qualquer backup ser feito
+func validarNota(nota string) error {
+	if len([]rune(strings.TrimSpace(nota))) > tamanhoMaximoNota {
+		return fmt.Errorf("a nota do backup pode ter no máximo %d caracteres", tamanhoMaximoNota)
+	}
+	return nil
+}
+
+// guardarAnotacao grava a anotação, ou a remove se ficou vazia
+func guardarAnotacao(anotacoes map[string]AnotacaoBackup, nome string, a AnotacaoBackup) {
+	if a == (AnotacaoBackup{}) {
+		delete(anotacoes, nome)
+		return
+	}
+	anotacoes[nome] = a
+}
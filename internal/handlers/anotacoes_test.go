@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+func TestCriarBackupComNotaSemMudancas(t *testing.T) {
+	assets := t.TempDir()
+	h := NewBackupHandler(assets, storage.NewBackendArquivos(filepath.Join(assets, "init")))
+	links := NewLinksHandler(assets, h.backend)
+	if err := links.AdicionarLink(Link{ID: "1", URL: "https://exemplo.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	primeiro, err := h.CriarBackup("antes de reorganizar", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nada mudou, mas a nota nova vai para um backup novo
+	segundo, err := h.CriarBackup("depois de reorganizar", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if segundo.Nome == primeiro.Nome {
+		t.Fatalf("a nota nova foi para o backup anterior (%s)", primeiro.Nome)
+	}
+	if segundo.Nota != "depois de reorganizar" || !segundo.Fixado {
+		t.Errorf("backup novo = %+v", segundo)
+	}
+	// Sem nota nem fixação, um backup igual não é repetido
+	terceiro, err := h.CriarBackup("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if terceiro.Nome != segundo.Nome {
+		t.Errorf("backup sem mudanças repetido: %s", terceiro.Nome)
+	}
+
+	backups, err := h.ListarBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notas := map[string]string{}
+	for _, b := range backups {
+		notas[b.Nome] = b.Nota
+	}
+	if len(backups) != 2 || notas[primeiro.Nome] != "antes de reorganizar" || notas[segundo.Nome] != "depois de reorganizar" {
+		t.Errorf("backups = %+v", backups)
+	}
+}
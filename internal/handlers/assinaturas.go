@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Assinatura é um calendário externo (.ics) mostrado junto com os eventos,
+// só para leitura. A configuração fica no workspace; o conteúdo baixado fica
+// em cache na pasta calendarios, fora de calendario_data.json.
+type Assinatura struct {
+	ID        string `json:"id"`
+	Nome      string `json:"nome"`
+	Origem    string `json:"origem"`            // Caminho de um .ics ou URL http(s)/webcal
+	Cor       string `json:"cor"`               // Cor dos eventos do calendário (hex)
+	Minutos   int    `json:"minutos,omitempty"` // Intervalo de atualização (0 = a cada hora)
+	CreatedAt string `json:"createdAt"`
+}
+
+// EstadoAssinatura é a assinatura com o resultado da última atualização
+type EstadoAssinatura struct {
+	Assinatura
+	AtualizadoEm string `json:"atualizadoEm,omitempty"` // Última cópia bem-sucedida (RFC 3339)
+	Eventos      int    `json:"eventos"`
+	Erro         string `json:"erro,omitempty"` // Por que a última tentativa falhou
+}
+
+// arquivoAssinaturas guarda as assinaturas do workspace. O nome antigo, sem
+// o sufixo _data.json, deixava o arquivo fora dos backups e da exportação;
+// o calendário renomeia o antigo ao abrir o workspace (ver renomearAntigos).
+const (
+	arquivoAssinaturas       = "calendario_assinaturas_data.json"
+	arquivoAssinaturasAntigo = "calendario_assinaturas.json"
+)
+
+// esquemaAssinaturas registra as migrações de calendario_assinaturas_data.json
+var esquemaAssinaturas = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+const (
+	corAssinaturaPadrao     = "#06b6d4"
+	intervaloAssinatura     = 60 * time.Minute
+	verificacaoAssinaturas  = time.Minute
+	tamanhoMaximoAssinatura = 10 << 20
+)
+
+// cacheAssinatura são os eventos lidos de um arquivo em cache, válidos
+// enquanto o arquivo não mudar
+type cacheAssinatura struct {
+	modificado time.Time
+	eventos    []Evento
+}
+
+// ListarAssinaturas retorna os calendários assinados e como está cada um
+func (h *CalendarioHandler) ListarAssinaturas() ([]EstadoAssinatura, error) {
+	assinaturas, err := h.assinaturas.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	estados := make([]EstadoAssinatura, 0, len(assinaturas))
+	for _, a := range assinaturas {
+		estados = append(estados, h.estadoAssinatura(a))
+	}
+	return estados, nil
+}
+
+// AdicionarAssinatura assina um calendário externo. Ele é baixado na hora,
+// para que uma origem errada seja recusada em vez de gravada.
+func (h *CalendarioHandler) AdicionarAssinatura(a Assinatura) (EstadoAssinatura, error) {
+	a.Origem = strings.TrimSpace(a.Origem)
+	if a.Origem == "" {
+		return EstadoAssinatura{}, fmt.Errorf("informe o caminho ou a URL do calendário")
+	}
+	if a.Minutos < 0 {
+		return EstadoAssinatura{}, fmt.Errorf("o intervalo de atualização não pode ser negativo")
+	}
+	if a.ID == "" {
+		a.ID = NovoID("assinatura")
+	}
+	if a.Nome = strings.TrimSpace(a.Nome); a.Nome == "" {
+		a.Nome = nomeDaOrigem(a.Origem)
+	}
+	if a.Cor == "" {
+		a.Cor = corAssinaturaPadrao
+	}
+	if a.CreatedAt == "" {
+		a.CreatedAt = CriadoAgora()
+	}
+
+	if _, err := h.atualizarAssinatura(a); err != nil {
+		return EstadoAssinatura{}, err
+	}
+	err := h.assinaturas.Atualizar(func(assinaturas *[]Assinatura) error {
+		for _, atual := range *assinaturas {
+			if atual.ID == a.ID {
+				return fmt.Errorf("já existe uma assinatura com o ID %s", a.ID)
+			}
+		}
+		*assinaturas = append(*assinaturas, a)
+		return nil
+	})
+	if err != nil {
+		os.Remove(h.arquivoCache(a.ID))
+		return EstadoAssinatura{}, err
+	}
+	return h.estadoAssinatura(a), nil
+}
+
+// RemoverAssinatura deixa de mostrar um calendário e apaga a cópia dele
+func (h *CalendarioHandler) RemoverAssinatura(id string) error {
+	encontrada := false
+	err := h.assinaturas.Atualizar(func(assinaturas *[]Assinatura) error {
+		restantes := []Assinatura{}
+		for _, a := range *assinaturas {
+			if a.ID == id {
+				encontrada = true
+				continue
+			}
+			restantes = append(restantes, a)
+		}
+		*assinaturas = restantes
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !encontrada {
+		return fmt.Errorf("assinatura não encontrada: %s", id)
+	}
+	h.mu.Lock()
+	delete(h.errosAssinaturas, id)
+	delete(h.tentativas, id)
+	h.mu.Unlock()
+	if err := os.Remove(h.arquivoCache(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// AtualizarAssinaturas baixa agora todos os calendários assinados. Falhas
+// ficam no estado de cada um, e a cópia anterior continua valendo.
+func (h *CalendarioHandler) AtualizarAssinaturas() ([]EstadoAssinatura, error) {
+	assinaturas, err := h.assinaturas.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	h.atualizarVarias(assinaturas)
+	return h.ListarAssinaturas()
+}
+
+// Shutdown para as atualizações periódicas e os lembretes, esperando uma
+// verificação em andamento
+func (h *CalendarioHandler) Shutdown(ctx context.Context) {
+	h.pararUma.Do(func() { close(h.parar) })
+	h.atualizador.Wait()
+	h.avisador.Wait()
+}
+
+// --- Funções internas ---
+
+// iniciarAtualizador verifica a cada minuto quais assinaturas passaram do
+// intervalo e as baixa de novo, até Shutdown ou o fim do contexto do Wails
+func (h *CalendarioHandler) iniciarAtualizador(ctx context.Context) {
+	h.atualizador.Add(1)
+	go func() {
+		defer h.atualizador.Done()
+		ticker := time.NewTicker(verificacaoAssinaturas)
+		defer ticker.Stop()
+		for {
+			h.atualizarVencidas()
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.parar:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// atualizarVencidas baixa as assinaturas cuja última tentativa (ou cópia)
+// é mais antiga que o intervalo delas
+func (h *CalendarioHandler) atualizarVencidas() {
+	assinaturas, err := h.assinaturas.Carregar()
+	if err != nil {
+		return
+	}
+	vencidas := []Assinatura{}
+	for _, a := range assinaturas {
+		intervalo := intervaloAssinatura
+		if a.Minutos > 0 {
+			intervalo = time.Duration(a.Minutos) * time.Minute
+		}
+		ultima := time.Time{}
+		if info, err := os.Stat(h.arquivoCache(a.ID)); err == nil {
+			ultima = info.ModTime()
+		}
+		h.mu.RLock()
+		if t := h.tentativas[a.ID]; t.After(ultima) {
+			ultima = t
+		}
+		h.mu.RUnlock()
+		if time.Since(ultima) >= intervalo {
+			vencidas = append(vencidas, a)
+		}
+	}
+	h.atualizarVarias(vencidas)
+}
+
+// atualizarVarias baixa as assinaturas informadas e avisa o frontend se
+// algum calendário mudou
+func (h *CalendarioHandler) atualizarVarias(assinaturas []Assinatura) {
+	h.atualizando.Lock()
+	defer h.atualizando.Unlock()
+	mudou := false
+	for _, a := range assinaturas {
+		if alterado, err := h.atualizarAssinatura(a); err == nil && alterado {
+			mudou = true
+		}
+	}
+	if mudou && h.ctx != nil {
+		runtime.EventsEmit(h.ctx, "calendario:assinaturas")
+	}
+}
+
+// atualizarAssinatura baixa o calendário e troca a cópia em cache. Retorna
+// se o conteúdo mudou.
+func (h *CalendarioHandler) atualizarAssinatura(a Assinatura) (bool, error) {
+	h.mu.Lock()
+	h.tentativas[a.ID] = time.Now()
+	h.mu.Unlock()
+
+	dados, err := h.baixarICS(a.Origem)
+	if err == nil {
+		_, _, err = lerICS(bytes.NewReader(dados))
+	}
+	h.mu.Lock()
+	if err != nil {
+		h.errosAssinaturas[a.ID] = err.Error()
+	} else {
+		delete(h.errosAssinaturas, a.ID)
+	}
+	h.mu.Unlock()
+	if err != nil {
+		return false, err
+	}
+
+	caminho := h.arquivoCache(a.ID)
+	if atual, err := os.ReadFile(caminho); err == nil && bytes.Equal(atual, dados) {
+		// Mesmo conteúdo: só registra que a cópia está em dia
+		agora := time.Now()
+		return false, os.Chtimes(caminho, agora, agora)
+	}
+	if err := os.MkdirAll(filepath.Dir(caminho), 0755); err != nil {
+		return false, err
+	}
+	return true, storage.EscreverAtomico(caminho, dados, 0600)
+}
+
+// baixarICS lê o calendário de um arquivo local ou de uma URL
+func (h *CalendarioHandler) baixarICS(origem string) ([]byte, error) {
+	u, err := url.Parse(origem)
+	if err != nil || u.Scheme == "" || u.Scheme == "file" || len(u.Scheme) == 1 {
+		// Caminho local (len 1: letra de unidade no Windows)
+		caminho := origem
+		if err == nil && u.Scheme == "file" {
+			caminho = u.Path
+		}
+		dados, err := os.ReadFile(caminho)
+		if err != nil {
+			return nil, fmt.Errorf("não foi possível ler o calendário: %w", err)
+		}
+		return dados, nil
+	}
+
+	switch u.Scheme {
+	case "webcal":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("origem não suportada: %s (use um arquivo ou uma URL http)", origem)
+	}
+	resp, err := h.cliente.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("não foi possível baixar o calendário: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("o servidor respondeu %s", resp.Status)
+	}
+	dados, err := io.ReadAll(io.LimitReader(resp.Body, tamanhoMaximoAssinatura+1))
+	if err != nil {
+		return nil, fmt.Errorf("não foi possível baixar o calendário: %w", err)
+	}
+	if len(dados) > tamanhoMaximoAssinatura {
+		return nil, fmt.Errorf("calendário grande demais (mais de %d MB)", tamanhoMaximoAssinatura>>20)
+	}
+	return dados, nil
+}
+
+// eventosAssinatura retorna os eventos da cópia em cache, relendo o arquivo
+// só quando ele muda
+func (h *CalendarioHandler) eventosAssinatura(id string) []Evento {
+	caminho := h.arquivoCache(id)
+	info, err := os.Stat(caminho)
+	if err != nil {
+		return nil
+	}
+	h.mu.RLock()
+	c, ok := h.cache[caminho]
+	h.mu.RUnlock()
+	if ok && c.modificado.Equal(info.ModTime()) {
+		return c.eventos
+	}
+
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil
+	}
+	eventos, _, err := lerICS(bytes.NewReader(dados))
+	if err != nil {
+		return nil
+	}
+	for i := range eventos {
+		uid := eventos[i].UID
+		if uid == "" {
+			uid = fmt.Sprint(i)
+		}
+		eventos[i].ID = id + "/" + uid
+		// Os lembretes são só dos eventos daqui; os alarmes do calendário
+		// assinado não disparam
+		eventos[i].Lembretes = nil
+	}
+	h.mu.Lock()
+	h.cache[caminho] = cacheAssinatura{modificado: info.ModTime(), eventos: eventos}
+	h.mu.Unlock()
+	return eventos
+}
+
+func (h *CalendarioHandler) estadoAssinatura(a Assinatura) EstadoAssinatura {
+	estado := EstadoAssinatura{Assinatura: a, Eventos: len(h.eventosAssinatura(a.ID))}
+	if info, err := os.Stat(h.arquivoCache(a.ID)); err == nil {
+		estado.AtualizadoEm = info.ModTime().Format(time.RFC3339)
+	}
+	h.mu.RLock()
+	estado.Erro = h.errosAssinaturas[a.ID]
+	h.mu.RUnlock()
+	return estado
+}
+
+// arquivoCache é onde fica a cópia do calendário assinado
+func (h *CalendarioHandler) arquivoCache(id string) string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return filepath.Join(h.assetsDir, "calendarios", filepath.Base(id)+".ics")
+}
+
+// nomeDaOrigem sugere um nome para a assinatura a partir do arquivo ou da URL
+func nomeDaOrigem(origem string) string {
+	if u, err := url.Parse(origem); err == nil && u.Host != "" {
+		return u.Host
+	}
+	nome := filepath.Base(origem)
+	return strings.TrimSuffix(nome, filepath.Ext(nome))
+}
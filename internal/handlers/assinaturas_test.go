@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+func TestCalendarioNomesAntigos(t *testing.T) {
+	for nome, backend := range backendsTeste(t) {
+		t.Run(nome, func(t *testing.T) {
+			antigo := `{"schemaVersion":1,"dados":[{"id":"1","nome":"Feriados","origem":"https://exemplo.com/feriados.ics","cor":"#06b6d4"}]}`
+			if err := backend.Gravar(arquivoAssinaturasAntigo, []byte(antigo)); err != nil {
+				t.Fatal(err)
+			}
+			disparos := `{"schemaVersion":1,"dados":[{"id":"e|2026-03-10|15","data":"2026-03-10","disparadoEm":"2026-03-10T14:15:00Z"}]}`
+			if err := backend.Gravar(arquivoLembretesAntigo, []byte(disparos)); err != nil {
+				t.Fatal(err)
+			}
+
+			h := NewCalendarioHandler(t.TempDir(), backend)
+			assinaturas, err := h.ListarAssinaturas()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(assinaturas) != 1 || assinaturas[0].Nome != "Feriados" {
+				t.Fatalf("assinaturas = %+v", assinaturas)
+			}
+			nomes, err := backend.Listar()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for antigo, novo := range map[string]string{
+				arquivoAssinaturasAntigo: arquivoAssinaturas,
+				arquivoLembretesAntigo:   arquivoLembretes,
+			} {
+				if _, err := backend.Ler(antigo); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("%s continua lá: %v", antigo, err)
+				}
+				if !slices.Contains(nomes, novo) {
+					t.Errorf("%s fora de Listar (e dos backups): %v", novo, nomes)
+				}
+			}
+			if conteudo, err := backend.Ler(arquivoLembretes); err != nil || string(conteudo) != disparos {
+				t.Errorf("lembretes disparados = %s, %v", conteudo, err)
+			}
+		})
+	}
+}
+
+// servidorICS serve o conteúdo e o status de resposta atuais, que o teste
+// troca entre uma atualização e outra
+type servidorICS struct {
+	mu       sync.Mutex
+	status   int
+	conteudo string
+}
+
+func (s *servidorICS) responder(status int, conteudo string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.conteudo = status, conteudo
+}
+
+func (s *servidorICS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "text/calendar")
+	w.WriteHeader(s.status)
+	io.WriteString(w, s.conteudo)
+}
+
+func calendarioICS(eventos ...string) string {
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"
+	for _, e := range eventos {
+		ics += "BEGIN:VEVENT\r\n" + e + "END:VEVENT\r\n"
+	}
+	return ics + "END:VCALENDAR\r\n"
+}
+
+func TestAssinaturaPorURL(t *testing.T) {
+	servidor := &servidorICS{}
+	servidor.responder(http.StatusOK, calendarioICS(
+		"UID:carnaval@escola\r\nDTSTART;VALUE=DATE:20260216\r\nSUMMARY:Carnaval\r\n",
+		"UID:reuniao@escola\r\nDTSTART:20260303T190000\r\nSUMMARY:Reunião de pais\r\n"+
+			"RRULE:FREQ=WEEKLY;COUNT=2\r\nBEGIN:VALARM\r\nACTION:DISPLAY\r\nTRIGGER:-PT1H\r\nEND:VALARM\r\n",
+	))
+	srv := httptest.NewTLSServer(servidor)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	h := NewCalendarioHandler(dir, storage.NewBackendArquivos(filepath.Join(dir, "init")))
+	h.cliente = srv.Client()
+	local := Evento{ID: "local", Titulo: "Dentista", Data: "2026-03-03", Hora: "09:00", Cor: CorEventoPadrao}
+	if err := h.AdicionarEvento(local); err != nil {
+		t.Fatal(err)
+	}
+
+	// webcal:// é baixado por https
+	origem := strings.Replace(srv.URL, "https://", "webcal://", 1) + "/escola.ics"
+	estado, err := h.AdicionarAssinatura(Assinatura{Nome: "Escola", Origem: origem, Cor: "#123456"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estado.Eventos != 2 || estado.Erro != "" || estado.AtualizadoEm == "" {
+		t.Errorf("estado = %+v", estado)
+	}
+
+	ocorrencias := func() (locais, assinadas []Ocorrencia) {
+		t.Helper()
+		todas, err := h.ListarOcorrencias("2026-02-01", "2026-03-31")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, o := range todas {
+			if o.SomenteLeitura {
+				assinadas = append(assinadas, o)
+			} else {
+				locais = append(locais, o)
+			}
+		}
+		return locais, assinadas
+	}
+	locais, assinadas := ocorrencias()
+	if len(locais) != 1 || locais[0].ID != local.ID {
+		t.Errorf("eventos locais = %+v", locais)
+	}
+	datas := []string{}
+	for _, o := range assinadas {
+		datas = append(datas, o.Data)
+		if o.Calendario != "Escola" || o.Cor != "#123456" || o.Lembretes != nil || !strings.HasPrefix(o.ID, estado.ID+"/") {
+			t.Errorf("ocorrência assinada = %+v", o)
+		}
+	}
+	if !slices.Equal(datas, []string{"2026-02-16", "2026-03-03", "2026-03-10"}) {
+		t.Errorf("datas assinadas = %v", datas)
+	}
+
+	// Os eventos assinados não podem ser alterados, nem vão para os locais
+	if err := h.PularOcorrencia(assinadas[1].ID, "2026-03-10"); err == nil {
+		t.Error("pular uma data de um evento assinado deveria falhar")
+	}
+	if err := h.DeletarEvento(assinadas[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if eventos, err := h.CarregarEventos(); err != nil || len(eventos) != 1 {
+		t.Errorf("eventos locais gravados = %+v, %v", eventos, err)
+	}
+	if _, depois := ocorrencias(); len(depois) != 3 {
+		t.Errorf("ocorrências assinadas depois de apagar uma = %d, esperado 3", len(depois))
+	}
+}
+
+func TestAssinaturaFalhaMantemCopia(t *testing.T) {
+	servidor := &servidorICS{}
+	srv := httptest.NewServer(servidor)
+	defer srv.Close()
+	dir := t.TempDir()
+	h := NewCalendarioHandler(dir, storage.NewBackendArquivos(filepath.Join(dir, "init")))
+	origem := srv.URL + "/feriados.ics"
+
+	// Uma origem que não responde um calendário não é gravada
+	servidor.responder(http.StatusNotFound, "")
+	if _, err := h.AdicionarAssinatura(Assinatura{Origem: origem}); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("erro = %v, esperado o status 404", err)
+	}
+	if assinaturas, err := h.ListarAssinaturas(); err != nil || len(assinaturas) != 0 {
+		t.Errorf("assinaturas depois da recusa = %+v, %v", assinaturas, err)
+	}
+
+	feriado := "UID:tiradentes\r\nDTSTART;VALUE=DATE:20260421\r\nSUMMARY:Tiradentes\r\n"
+	servidor.responder(http.StatusOK, calendarioICS(feriado))
+	estado, err := h.AdicionarAssinatura(Assinatura{Origem: origem})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estado.Nome != strings.TrimPrefix(srv.URL, "http://") {
+		t.Errorf("nome sugerido = %q", estado.Nome)
+	}
+
+	casos := []struct {
+		nome     string
+		status   int
+		conteudo string
+		erro     string // trecho do erro no estado; vazio = atualizou
+		eventos  int
+	}{
+		{"servidor fora do ar", http.StatusInternalServerError, "", "500", 1},
+		{"resposta que não é um calendário", http.StatusOK, "<html>manutenção</html>", "iCalendar", 1},
+		{"calendário novo", http.StatusOK, calendarioICS(feriado,
+			"UID:trabalho\r\nDTSTART;VALUE=DATE:20260501\r\nSUMMARY:Dia do Trabalho\r\n"), "", 2},
+	}
+	for _, c := range casos {
+		servidor.responder(c.status, c.conteudo)
+		estados, err := h.AtualizarAssinaturas()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(estados) != 1 {
+			t.Fatalf("%s: estados = %+v", c.nome, estados)
+		}
+		e := estados[0]
+		if c.erro == "" && e.Erro != "" || c.erro != "" && !strings.Contains(e.Erro, c.erro) {
+			t.Errorf("%s: erro = %q, esperado %q", c.nome, e.Erro, c.erro)
+		}
+		// Com falha, a última cópia boa continua na agenda
+		ocorrencias, err := h.ListarOcorrencias("2026-04-01", "2026-05-31")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Eventos != c.eventos || len(ocorrencias) != c.eventos {
+			t.Errorf("%s: %d eventos no estado e %d na agenda, esperado %d", c.nome, e.Eventos, len(ocorrencias), c.eventos)
+		}
+	}
+}
@@ -2,60 +2,279 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Cada backup é uma pasta backup_<timestamp> com um manifesto que aponta, para
+// cada arquivo de dados e cada imagem usada no canvas, o hash do seu conteúdo.
+// Os conteúdos ficam uma única vez em backups/objetos/<sha256>, então arquivos
+// que não mudaram entre um backup e outro não ocupam espaço de novo. Backups
+// antigos (com os *_data.json copiados direto na pasta) continuam sendo lidos.
+const (
+	arquivoManifesto = "manifesto.json"
+	pastaObjetos     = "objetos"
 )
 
-const maxBackups = 10
+// manifesto é o conteúdo de backup_<timestamp>/manifesto.json
+type manifesto struct {
+	Arquivos map[string]string `json:"arquivos"`          // nome do arquivo → sha256
+	Imagens  map[string]string `json:"imagens,omitempty"` // arquivo em img → sha256
+	Itens    map[string]int    `json:"itens,omitempty"`   // arquivo de dados → quantidade de itens
+
+	legado bool // backup antigo, sem manifesto: arquivos copiados na pasta
+}
 
 // BackupHandler gerencia backups automáticos e manuais dos dados
 type BackupHandler struct {
 	ctx       context.Context
+	mu        sync.RWMutex // protege as pastas e o backend contra troca de workspace
 	assetsDir string
 	backupDir string
+	backend   storage.Backend
+	cifra     *storage.Cifra // nil: backups em claro
+
+	backupPendente atomic.Bool // backup automático adiado até a senha ser informada
+
+	// Backups periódicos (ver agendamento.go)
+	reagendado chan struct{}
+	parar      chan struct{}
+	pararUma   sync.Once
+	agendador  sync.WaitGroup
 }
 
 // BackupInfo representa informações de um backup disponível
 type BackupInfo struct {
-	Nome  string `json:"nome"`
-	Data  string `json:"data"`
-	Label string `json:"label"`
+	Nome        string             `json:"nome"`
+	Data        string             `json:"data"`
+	Label       string             `json:"label"`
+	Nota        string             `json:"nota,omitempty"`        // Nota dada pelo usuário (ver anotacoes.go)
+	Fixado      bool               `json:"fixado,omitempty"`      // Fixado: a retenção não o apaga
+	Verificacao *VerificacaoBackup `json:"verificacao,omitempty"` // Última verificação, se houver
+}
+
+// ArquivoCorrompido descreve um arquivo de dados que foi para a quarentena
+type ArquivoCorrompido struct {
+	Arquivo    string      `json:"arquivo"`
+	Quarentena string      `json:"quarentena"`
+	Backup     *BackupInfo `json:"backup,omitempty"` // Backup válido mais recente, se houver
 }
 
 // NewBackupHandler cria um novo handler de backup
-func NewBackupHandler(assetsDir string) *BackupHandler {
+func NewBackupHandler(assetsDir string, backend storage.Backend) *BackupHandler {
 	backupDir := filepath.Join(assetsDir, "backups")
 	return &BackupHandler{
-		assetsDir: assetsDir,
-		backupDir: backupDir,
+		assetsDir:  assetsDir,
+		backupDir:  backupDir,
+		backend:    backend,
+		reagendado: make(chan struct{}, 1),
+		parar:      make(chan struct{}),
 	}
 }
 
-// Startup cria backup automático ao iniciar o app
+// Startup cria backup automático ao iniciar o app e começa os backups
+// periódicos
 func (h *BackupHandler) Startup(ctx context.Context) {
 	h.ctx = ctx
-	os.MkdirAll(h.backupDir, 0755)
-	// Backup automático na inicialização (silencioso)
-	h.fazerBackupInterno()
+	h.abrirWorkspace()
+	h.iniciarAgendador(ctx)
+}
+
+// Apontar passa a usar os dados e os backups de outro workspace, sem
+// criptografia
+func (h *BackupHandler) Apontar(assetsDir string, backend storage.Backend) {
+	h.ApontarCifrado(assetsDir, backend, nil)
+}
+
+// ApontarCifrado passa a usar os dados e os backups de outro workspace,
+// cifrando os backups com a cifra dele (nil: backups em claro)
+func (h *BackupHandler) ApontarCifrado(assetsDir string, backend storage.Backend, cifra *storage.Cifra) {
+	h.mu.Lock()
+	h.assetsDir = assetsDir
+	h.backupDir = filepath.Join(assetsDir, "backups")
+	h.backend = backend
+	h.cifra = cifra
+	h.backupPendente.Store(false)
+	h.mu.Unlock()
+
+	if h.ctx != nil {
+		h.abrirWorkspace()
+	}
+	h.reagendar()
 }
 
-// CriarBackup cria um backup manual e retorna suas informações
-func (h *BackupHandler) CriarBackup() (BackupInfo, error) {
-	return h.fazerBackupInterno()
+// CriarBackup cria um backup manual e retorna suas informações. A nota
+// (opcional) aparece na lista de backups, e um backup fixado nunca é apagado
+// pela retenção. Sem nota nem fixação, um backup igual ao último não é
+// repetido (o último é retornado); com elas o backup é sempre novo, para
+// não trocar a nota de um backup anterior.
+func (h *BackupHandler) CriarBackup(nota string, fixar bool) (BackupInfo, error) {
+	if err := validarNota(nota); err != nil {
+		return BackupInfo{}, err
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backupDir)()
+
+	info, err := h.fazerBackup(strings.TrimSpace(nota) != "" || fixar)
+	if err != nil {
+		return info, err
+	}
+	// Anotar antes da retenção, para que um backup fixado não seja apagado
+	if info.Nome != "" {
+		if err := h.anotarBackup(info.Nome, nota, fixar); err != nil {
+			return info, err
+		}
+		anotacoes, _ := storeAnotacoes(h.backupDir).Carregar()
+		a := anotacoes[info.Nome]
+		info.Nota, info.Fixado = a.Nota, a.Fixado
+	}
+	h.limparAntigos()
+	h.copiarParaDestinos()
+	return info, nil
 }
 
-// ListarBackups retorna todos os backups disponíveis (mais recentes primeiro)
+// ListarBackups retorna todos os backups disponíveis (mais recentes
+// primeiro), com as notas, a fixação e o resultado da última verificação de
+// cada um
 func (h *BackupHandler) ListarBackups() ([]BackupInfo, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	backups, err := h.listarBackups()
+	if err != nil {
+		return backups, err
+	}
+	anotacoes, _ := storeAnotacoes(h.backupDir).Carregar()
+	verificacoes, _ := storeVerificacoes(h.backupDir).Carregar()
+	for i := range backups {
+		a := anotacoes[backups[i].Nome]
+		backups[i].Nota, backups[i].Fixado = a.Nota, a.Fixado
+		if v, ok := verificacoes[backups[i].Nome]; ok {
+			backups[i].Verificacao = &v
+		}
+	}
+	return backups, nil
+}
+
+// RestaurarBackup restaura todos os dados a partir de um backup. Antes disso
+// o estado atual é salvo, e DesfazerRestauracao volta a ele.
+func (h *BackupHandler) RestaurarBackup(nome string) error {
+	return h.RestaurarArquivos(nome, nil)
+}
+
+// RestaurarArquivos restaura só os arquivos de dados informados (ex:
+// calendario_data.json) a partir de um backup; lista vazia restaura todos.
+// Antes disso o estado atual é salvo, e DesfazerRestauracao volta a ele.
+func (h *BackupHandler) RestaurarArquivos(nome string, arquivos []string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backupDir)()
+	return h.restaurarComDesfazer(nome, arquivos)
+}
+
+// ArquivosCorrompidos lista os arquivos de dados em quarentena, sugerindo o
+// backup mais recente que contém uma versão legível de cada um
+func (h *BackupHandler) ArquivosCorrompidos() ([]ArquivoCorrompido, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.arquivosCorrompidos()
+}
+
+// RecuperarArquivo restaura um único arquivo de dados a partir de um backup.
+// Se nome for vazio, usa o backup válido mais recente.
+func (h *BackupHandler) RecuperarArquivo(arquivo string, nome string) (BackupInfo, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backupDir)()
+	return h.recuperarArquivo(arquivo, nome)
+}
+
+// DescartarCorrompido apaga a cópia em quarentena, aceitando recomeçar o
+// módulo sem os dados antigos
+func (h *BackupHandler) DescartarCorrompido(arquivo string) error {
+	if !nomeArquivoDadosValido(arquivo) {
+		return fmt.Errorf("arquivo de dados inválido: %s", arquivo)
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backend.Chave(arquivo))()
+	return h.backend.DescartarQuarentena(arquivo)
+}
+
+// ObterRetencao retorna a política de retenção de backups do workspace
+func (h *BackupHandler) ObterRetencao() (PoliticaRetencao, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return storeRetencao(h.backupDir).Carregar()
+}
+
+// SalvarRetencao grava a política de retenção e já apaga os backups que
+// deixaram de ser mantidos
+func (h *BackupHandler) SalvarRetencao(politica PoliticaRetencao) error {
+	if err := politica.validar(); err != nil {
+		return err
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backupDir)()
+
+	if err := storeRetencao(h.backupDir).Salvar(politica); err != nil {
+		return err
+	}
+	return h.limparAntigos()
+}
+
+// --- Funções internas ---
+
+// abrirWorkspace prepara os dados do workspace ativo: isola arquivos
+// corrompidos, faz o backup automático e avisa o frontend se algo precisa
+// ser recuperado
+func (h *BackupHandler) abrirWorkspace() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	os.MkdirAll(h.backupDir, 0755)
+	// Isolar arquivos corrompidos antes que entrem no backup
+	h.verificarArquivos()
+	// Backup automático na inicialização (silencioso). Com os dados ainda
+	// bloqueados ele fica para quando a senha for informada (UsarCifra).
+	if h.cifra != nil && h.cifra.Bloqueada() {
+		h.backupPendente.Store(true)
+	} else {
+		h.backupAutomatico()
+	}
+
+	// Avisar o frontend para oferecer a recuperação
+	if corrompidos, err := h.arquivosCorrompidos(); err == nil && len(corrompidos) > 0 {
+		runtime.EventsEmit(h.ctx, "dados:corrompidos", corrompidos)
+	}
+}
+
+func (h *BackupHandler) listarBackups() ([]BackupInfo, error) {
 	if err := os.MkdirAll(h.backupDir, 0755); err != nil {
 		return []BackupInfo{}, err
 	}
+	return listarBackupsEm(h.backupDir)
+}
 
-	entries, err := os.ReadDir(h.backupDir)
+// listarBackupsEm lista os backups de uma pasta de backups (mais recentes primeiro)
+func listarBackupsEm(dir string) ([]BackupInfo, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return []BackupInfo{}, err
 	}
@@ -80,8 +299,7 @@ func (h *BackupHandler) ListarBackups() ([]BackupInfo, error) {
 	return backups, nil
 }
 
-// RestaurarBackup restaura os dados a partir de um backup
-func (h *BackupHandler) RestaurarBackup(nome string) error {
+func (h *BackupHandler) restaurarBackup(nome string, arquivos []string) error {
 	// Validação básica de segurança: nome não pode conter separadores de path
 	if strings.ContainsAny(nome, "/\\") {
 		return fmt.Errorf("nome de backup inválido")
@@ -95,70 +313,196 @@ func (h *BackupHandler) RestaurarBackup(nome string) error {
 		return fmt.Errorf("backup não encontrado: %s", nome)
 	}
 
-	// Garantir que a pasta init existe
-	initDir := filepath.Join(h.assetsDir, "init")
-	if err := os.MkdirAll(initDir, 0755); err != nil {
-		return fmt.Errorf("erro ao criar pasta init: %w", err)
+	m, err := h.conteudoBackup(nome)
+	if err != nil {
+		return err
 	}
-
-	entries, err := os.ReadDir(backupPath)
+	arquivos, err = selecionarArquivos(m, arquivos)
 	if err != nil {
 		return err
 	}
 
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), "_data.json") {
-			src := filepath.Join(backupPath, entry.Name())
-			// Restaurar para assets/init/
-			dst := filepath.Join(initDir, entry.Name())
-			if err := copiarArquivo(src, dst); err != nil {
-				return fmt.Errorf("erro ao restaurar %s: %w", entry.Name(), err)
-			}
+	// Imagens primeiro: o canvas restaurado nunca aponta para imagens ausentes
+	if slices.Contains(arquivos, arquivoIdeias) {
+		if err := h.restaurarImagens(m); err != nil {
+			return err
+		}
+	}
+	for _, arquivo := range arquivos {
+		dados, err := h.lerDoBackup(nome, m, arquivo)
+		if err != nil {
+			return fmt.Errorf("erro ao ler %s do backup: %w", arquivo, err)
+		}
+		if err := h.restaurarDocumento(arquivo, dados); err != nil {
+			return fmt.Errorf("erro ao restaurar %s: %w", arquivo, err)
 		}
 	}
 
 	return nil
 }
 
-// --- Funções internas ---
+func (h *BackupHandler) arquivosCorrompidos() ([]ArquivoCorrompido, error) {
+	bloqueados, err := h.backend.ListarBloqueados()
+	if err != nil {
+		return []ArquivoCorrompido{}, err
+	}
 
-func (h *BackupHandler) fazerBackupInterno() (BackupInfo, error) {
-	ts := time.Now().Format("2006-01-02_15-04-05")
-	nome := fmt.Sprintf("backup_%s", ts)
-	backupPath := filepath.Join(h.backupDir, nome)
+	corrompidos := []ArquivoCorrompido{}
+	for _, arquivo := range bloqueados {
+		q, bloqueado := h.backend.Bloqueado(arquivo)
+		if !bloqueado {
+			continue
+		}
+		item := ArquivoCorrompido{
+			Arquivo:    arquivo,
+			Quarentena: q,
+		}
+		if info, ok := h.backupValidoMaisRecente(arquivo); ok {
+			item.Backup = &info
+		}
+		corrompidos = append(corrompidos, item)
+	}
+	return corrompidos, nil
+}
 
-	if err := os.MkdirAll(backupPath, 0755); err != nil {
-		return BackupInfo{}, err
+func (h *BackupHandler) recuperarArquivo(arquivo string, nome string) (BackupInfo, error) {
+	if !nomeArquivoDadosValido(arquivo) {
+		return BackupInfo{}, fmt.Errorf("arquivo de dados inválido: %s", arquivo)
+	}
+
+	var info BackupInfo
+	if nome == "" {
+		var ok bool
+		if info, ok = h.backupValidoMaisRecente(arquivo); !ok {
+			return BackupInfo{}, fmt.Errorf("nenhum backup válido de %s encontrado", arquivo)
+		}
+	} else {
+		if strings.ContainsAny(nome, "/\\") {
+			return BackupInfo{}, fmt.Errorf("nome de backup inválido")
+		}
+		ts := strings.TrimPrefix(nome, "backup_")
+		info = BackupInfo{Nome: nome, Data: ts, Label: formatLabel(ts)}
 	}
 
-	// Copiar todos os arquivos *_data.json da pasta init
-	initDir := filepath.Join(h.assetsDir, "init")
-	entries, err := os.ReadDir(initDir)
+	dados, ok := h.versaoValida(info.Nome, arquivo)
+	if !ok {
+		return BackupInfo{}, fmt.Errorf("o backup %s não contém uma versão válida de %s", info.Nome, arquivo)
+	}
+
+	if arquivo == arquivoIdeias {
+		m, err := h.conteudoBackup(info.Nome)
+		if err == nil {
+			err = h.restaurarImagens(m)
+		}
+		if err != nil {
+			return BackupInfo{}, err
+		}
+	}
+	if err := h.restaurarDocumento(arquivo, dados); err != nil {
+		return BackupInfo{}, fmt.Errorf("erro ao restaurar %s: %w", arquivo, err)
+	}
+	return info, nil
+}
+
+// verificarArquivos coloca em quarentena os documentos ilegíveis
+func (h *BackupHandler) verificarArquivos() {
+	nomes, err := h.backend.Listar()
+	if err != nil {
+		return
+	}
+
+	for _, nome := range nomes {
+		unlock := storage.Travar(h.backend.Chave(nome))
+		if dados, err := h.backend.Ler(nome); err == nil && !json.Valid(dados) {
+			h.backend.Quarentenar(nome)
+		}
+		unlock()
+	}
+}
+
+// backupValidoMaisRecente procura o backup mais novo com uma cópia legível do arquivo
+func (h *BackupHandler) backupValidoMaisRecente(arquivo string) (BackupInfo, bool) {
+	backups, err := h.listarBackups()
+	if err != nil {
+		return BackupInfo{}, false
+	}
+	for _, b := range backups {
+		if _, ok := h.versaoValida(b.Nome, arquivo); ok {
+			return b, true
+		}
+	}
+	return BackupInfo{}, false
+}
+
+// versaoValida retorna o conteúdo do arquivo no backup, se for JSON legível
+func (h *BackupHandler) versaoValida(nome, arquivo string) ([]byte, bool) {
+	m, err := h.conteudoBackup(nome)
+	if err != nil {
+		return nil, false
+	}
+	dados, err := h.lerDoBackup(nome, m, arquivo)
+	return dados, err == nil && json.Valid(dados)
+}
+
+// fazerBackupInterno grava um novo backup, a menos que nada tenha mudado
+// desde o último (nesse caso retorna o último). Quem chama deve segurar a
+// trava de backupDir e depois aplicar a retenção (limparAntigos).
+func (h *BackupHandler) fazerBackupInterno() (BackupInfo, error) {
+	return h.fazerBackup(false)
+}
+
+// fazerBackup é fazerBackupInterno; com sempre, grava um backup novo mesmo
+// sem mudanças (ex: para receber uma nota sem mexer na de outro backup). Os
+// objetos são os mesmos, então o backup repetido só ocupa o manifesto.
+func (h *BackupHandler) fazerBackup(sempre bool) (BackupInfo, error) {
+	// Exportar todos os documentos do backend como *_data.json
+	nomes, err := h.backend.Listar()
 	if err != nil {
-		// Se a pasta init não existe, pode ser a primeira execução
+		return BackupInfo{}, err
+	}
+	if len(nomes) == 0 {
+		// Sem documentos ainda, pode ser a primeira execução
 		// Não criar backup vazio
-		os.RemoveAll(backupPath)
 		return BackupInfo{}, nil
 	}
 
-	copiados := 0
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), "_data.json") {
-			src := filepath.Join(initDir, entry.Name())
-			dst := filepath.Join(backupPath, entry.Name())
-			if err := copiarArquivo(src, dst); err == nil {
-				copiados++
+	novo := manifesto{Arquivos: make(map[string]string), Imagens: make(map[string]string), Itens: make(map[string]int)}
+	for _, nome := range nomes {
+		if hash, itens, err := h.exportarDocumento(nome); err == nil {
+			novo.Arquivos[nome] = hash
+			if itens >= 0 {
+				novo.Itens[nome] = itens
 			}
 		}
 	}
-
-	// Se não copiou nada, remover a pasta vazia
-	if copiados == 0 {
-		os.Remove(backupPath)
+	if len(novo.Arquivos) == 0 {
 		return BackupInfo{}, fmt.Errorf("nenhum arquivo de dados encontrado para backup")
 	}
+	h.exportarImagens(&novo)
 
-	h.limparAntigos()
+	// Nada mudou desde o último backup: não criar outro igual
+	if backups, err := h.listarBackups(); err == nil && len(backups) > 0 && !sempre {
+		if ultimo, err := h.conteudoBackup(backups[0].Nome); err == nil &&
+			mesmoConteudo(ultimo.Arquivos, novo.Arquivos) && mesmoConteudo(ultimo.Imagens, novo.Imagens) {
+			return backups[0], nil
+		}
+	}
+
+	// O nome tem resolução de segundos: se já houver um backup neste
+	// segundo, esperar o próximo
+	agora := time.Now()
+	ts := agora.Format("2006-01-02_15-04-05")
+	if _, err := os.Stat(filepath.Join(h.backupDir, "backup_"+ts)); err == nil {
+		agora = agora.Truncate(time.Second).Add(time.Second)
+		time.Sleep(time.Until(agora))
+		ts = agora.Format("2006-01-02_15-04-05")
+	}
+	nome := fmt.Sprintf("backup_%s", ts)
+	if err := h.gravarManifesto(nome, novo); err != nil {
+		return BackupInfo{}, err
+	}
+	// Conferir já o que foi gravado, relendo os objetos do disco
+	h.registrarVerificacao(h.verificarBackup(nome))
 
 	return BackupInfo{
 		Nome:  nome,
@@ -167,43 +511,314 @@ func (h *BackupHandler) fazerBackupInterno() (BackupInfo, error) {
 	}, nil
 }
 
-func (h *BackupHandler) limparAntigos() {
-	entries, err := os.ReadDir(h.backupDir)
+// gravarManifesto cria a pasta do backup de uma vez: o manifesto é escrito
+// em uma pasta temporária que só então recebe o nome final
+func (h *BackupHandler) gravarManifesto(nome string, m manifesto) error {
+	return gravarManifestoEm(h.backupDir, nome, m)
+}
+
+// gravarManifestoEm é gravarManifesto para uma pasta de backups qualquer
+func gravarManifestoEm(dir, nome string, m manifesto) error {
+	tmp := filepath.Join(dir, "."+nome+".tmp")
+	os.RemoveAll(tmp)
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return err
+	}
+
+	dados, err := json.MarshalIndent(m, "", "  ")
+	if err == nil {
+		err = storage.EscreverAtomico(filepath.Join(tmp, arquivoManifesto), dados, 0600)
+	}
+	if err == nil {
+		err = os.Rename(tmp, filepath.Join(dir, nome))
+	}
 	if err != nil {
-		return
+		os.RemoveAll(tmp)
 	}
+	return err
+}
 
-	var dirs []string
-	for _, e := range entries {
-		if e.IsDir() && strings.HasPrefix(e.Name(), "backup_") {
-			dirs = append(dirs, e.Name())
+// conteudoBackup retorna o manifesto de um backup. Em backups antigos, sem
+// manifesto, o hash de cada arquivo é calculado na hora.
+func (h *BackupHandler) conteudoBackup(nome string) (manifesto, error) {
+	return conteudoBackupEm(h.backupDir, nome)
+}
+
+// conteudoBackupEm é conteudoBackup para uma pasta de backups qualquer
+func conteudoBackupEm(dir, nome string) (manifesto, error) {
+	backupPath := filepath.Join(dir, nome)
+
+	dados, err := os.ReadFile(filepath.Join(backupPath, arquivoManifesto))
+	if err == nil {
+		var m manifesto
+		if err := json.Unmarshal(dados, &m); err != nil {
+			return manifesto{}, fmt.Errorf("manifesto do backup %s ilegível: %w", nome, err)
+		}
+		if m.Arquivos == nil {
+			m.Arquivos = map[string]string{}
 		}
+		if m.Imagens == nil {
+			m.Imagens = map[string]string{}
+		}
+		return m, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return manifesto{}, err
 	}
 
-	// Ordenar do mais antigo para o mais novo
-	sort.Strings(dirs)
+	entries, err := os.ReadDir(backupPath)
+	if err != nil {
+		return manifesto{}, err
+	}
+	m := manifesto{Arquivos: map[string]string{}, Imagens: map[string]string{}, legado: true}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), "_data.json") {
+			conteudo, err := os.ReadFile(filepath.Join(backupPath, entry.Name()))
+			if err != nil {
+				return manifesto{}, err
+			}
+			m.Arquivos[entry.Name()] = hashConteudo(conteudo)
+		}
+	}
+	return m, nil
+}
 
-	for len(dirs) > maxBackups {
-		os.RemoveAll(filepath.Join(h.backupDir, dirs[0]))
-		dirs = dirs[1:]
+// lerDoBackup lê um arquivo de um backup, do objeto apontado no manifesto
+// ou, em backups antigos, da cópia direta na pasta
+func (h *BackupHandler) lerDoBackup(nome string, m manifesto, arquivo string) ([]byte, error) {
+	hash, ok := m.Arquivos[arquivo]
+	if !ok {
+		return nil, os.ErrNotExist
 	}
+	if m.legado {
+		return os.ReadFile(filepath.Join(h.backupDir, nome, arquivo))
+	}
+	return h.lerObjeto(hash)
 }
 
-func copiarArquivo(src, dst string) error {
-	in, err := os.Open(src)
+// exportarImagens guarda no backup as imagens usadas pelo canvas de ideias.
+// Imagens que sumiram da pasta img são ignoradas.
+func (h *BackupHandler) exportarImagens(m *manifesto) {
+	hash, ok := m.Arquivos[arquivoIdeias]
+	if !ok {
+		return
+	}
+	dados, err := h.lerObjeto(hash)
 	if err != nil {
+		return
+	}
+	canvas, err := storage.Decodificar[CanvasData](dados, esquemaCanvas)
+	if err != nil {
+		return
+	}
+
+	imgDir := filepath.Join(h.assetsDir, "img")
+	for imagem := range imagensReferenciadas(canvas) {
+		if !nomeImagemValido(imagem) {
+			continue
+		}
+		conteudo, err := os.ReadFile(filepath.Join(imgDir, imagem))
+		if err != nil {
+			continue
+		}
+		if hash, err := h.guardarObjeto(conteudo); err == nil {
+			m.Imagens[imagem] = hash
+		}
+	}
+}
+
+// restaurarImagens devolve à pasta img as imagens do backup que estão
+// faltando ou diferentes
+func (h *BackupHandler) restaurarImagens(m manifesto) error {
+	if len(m.Imagens) == 0 {
+		return nil
+	}
+	imgDir := filepath.Join(h.assetsDir, "img")
+	if err := os.MkdirAll(imgDir, 0755); err != nil {
 		return err
 	}
-	defer in.Close()
 
-	out, err := os.Create(dst)
+	for imagem, hash := range m.Imagens {
+		if !nomeImagemValido(imagem) {
+			continue
+		}
+		destino := filepath.Join(imgDir, imagem)
+		if atual, err := os.ReadFile(destino); err == nil && hashConteudo(atual) == hash {
+			continue
+		}
+		conteudo, err := h.lerObjeto(hash)
+		if err != nil {
+			return fmt.Errorf("imagem %s ausente do backup: %w", imagem, err)
+		}
+		if err := storage.EscreverAtomico(destino, conteudo, 0644); err != nil {
+			return fmt.Errorf("erro ao restaurar a imagem %s: %w", imagem, err)
+		}
+	}
+	return nil
+}
+
+// limparAntigos apaga os backups fora da política de retenção e depois os
+// objetos que nenhum backup restante usa
+func (h *BackupHandler) limparAntigos() error {
+	politica, err := storeRetencao(h.backupDir).Carregar()
+	if err != nil {
+		return err
+	}
+	backups, err := h.listarBackups()
 	if err != nil {
 		return err
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, in)
-	return err
+	manter := politica.manter(backups, time.Now())
+	// Os backups da última restauração ficam enquanto ela puder ser desfeita
+	if r, err := storeRestauracao(h.backupDir).Carregar(); err == nil && r != nil {
+		manter[r.Anterior.Nome] = true
+		manter[r.Backup.Nome] = true
+	}
+	for nome := range h.fixados() {
+		manter[nome] = true
+	}
+	podar(h.backupDir, backups, manter)
+
+	// Esquecer as anotações e verificações dos backups apagados
+	storeAnotacoes(h.backupDir).Atualizar(func(a *map[string]AnotacaoBackup) error {
+		for nome := range *a {
+			if !manter[nome] {
+				delete(*a, nome)
+			}
+		}
+		return nil
+	})
+	storeVerificacoes(h.backupDir).Atualizar(func(v *map[string]VerificacaoBackup) error {
+		for nome := range *v {
+			if !manter[nome] {
+				delete(*v, nome)
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+// podar apaga de uma pasta de backups os que não devem ser mantidos e depois
+// os objetos que nenhum backup restante usa
+func podar(dir string, backups []BackupInfo, manter map[string]bool) {
+	usados := make(map[string]bool)
+	completo := true
+	for _, b := range backups {
+		if !manter[b.Nome] {
+			os.RemoveAll(filepath.Join(dir, b.Nome))
+			continue
+		}
+		m, err := conteudoBackupEm(dir, b.Nome)
+		if err != nil {
+			completo = false
+			continue
+		}
+		for _, hash := range m.Arquivos {
+			usados[hash] = true
+		}
+		for _, hash := range m.Imagens {
+			usados[hash] = true
+		}
+	}
+
+	// Sem saber o que todos os backups usam, não apagar nenhum objeto
+	objetos, err := os.ReadDir(filepath.Join(dir, pastaObjetos))
+	if err != nil || !completo {
+		return
+	}
+	for _, o := range objetos {
+		if !usados[o.Name()] {
+			os.Remove(filepath.Join(dir, pastaObjetos, o.Name()))
+		}
+	}
+}
+
+// exportarDocumento guarda o conteúdo atual do documento como objeto e
+// retorna seu hash e quantos itens ele tem (-1 se o arquivo não for de um
+// módulo conhecido ou não puder ser lido). Segura a trava do documento para
+// não competir com um salvamento em andamento nos handlers.
+func (h *BackupHandler) exportarDocumento(nome string) (string, int, error) {
+	unlock := storage.Travar(h.backend.Chave(nome))
+	dados, err := h.backend.Ler(nome)
+	unlock()
+	if err != nil {
+		return "", 0, err
+	}
+	hash, err := h.guardarObjeto(dados)
+	return hash, contarItens(nome, dados), err
+}
+
+// guardarObjeto grava o conteúdo em objetos/<sha256>, se ainda não existir.
+// O hash é sempre do conteúdo em claro; com uma cifra o objeto é cifrado.
+func (h *BackupHandler) guardarObjeto(dados []byte) (string, error) {
+	hash := hashConteudo(dados)
+	caminho := filepath.Join(h.backupDir, pastaObjetos, hash)
+	if _, err := os.Stat(caminho); err == nil {
+		// Conteúdo já guardado por um backup anterior
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(caminho), 0755); err != nil {
+		return "", err
+	}
+	if h.cifra != nil {
+		var err error
+		if dados, err = h.cifra.Cifrar(dados); err != nil {
+			return "", err
+		}
+	}
+	return hash, storage.EscreverAtomico(caminho, dados, 0600)
+}
+
+// lerObjeto lê um objeto guardado, decifrando-o se preciso
+func (h *BackupHandler) lerObjeto(hash string) ([]byte, error) {
+	dados, err := os.ReadFile(filepath.Join(h.backupDir, pastaObjetos, hash))
+	if err != nil || !storage.Cifrado(dados) {
+		return dados, err
+	}
+	if h.cifra == nil {
+		return nil, storage.ErrBloqueado
+	}
+	return h.cifra.Decifrar(dados)
+}
+
+// removerDocumento apaga do backend um documento que não existia no estado
+// sendo restaurado
+func (h *BackupHandler) removerDocumento(nome string) error {
+	defer storage.Travar(h.backend.Chave(nome))()
+	return h.backend.Remover(nome)
+}
+
+// restaurarDocumento grava no backend o conteúdo vindo de um backup
+func (h *BackupHandler) restaurarDocumento(nome string, dados []byte) error {
+	defer storage.Travar(h.backend.Chave(nome))()
+	return h.backend.Gravar(nome, dados)
+}
+
+func hashConteudo(dados []byte) string {
+	soma := sha256.Sum256(dados)
+	return hex.EncodeToString(soma[:])
+}
+
+func mesmoConteudo(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for arquivo, hash := range a {
+		if b[arquivo] != hash {
+			return false
+		}
+	}
+	return true
+}
+
+func nomeArquivoDadosValido(arquivo string) bool {
+	return strings.HasSuffix(arquivo, "_data.json") && !strings.ContainsAny(arquivo, "/\\")
+}
+
+func nomeImagemValido(imagem string) bool {
+	return imagem != "" && imagem != "." && imagem != ".." && !strings.ContainsAny(imagem, "/\\")
 }
 
 func formatLabel(ts string) string {
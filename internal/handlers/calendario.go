@@ -2,16 +2,35 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
-	"os"
-	"path/filepath"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
 )
 
 // CalendarioHandler gerencia as operações do módulo de calendário
 type CalendarioHandler struct {
-	ctx       context.Context
-	assetsDir string
-	dataFile  string
+	ctx         context.Context
+	mu          sync.RWMutex // protege assetsDir, cache, errosAssinaturas e tentativas
+	assetsDir   string
+	store       *storage.Store[[]Evento]
+	assinaturas *storage.Store[[]Assinatura]
+	lembretes   *storage.Store[[]DisparoLembrete]
+
+	// Calendários assinados: eventos lidos das cópias, falhas e horário da
+	// última tentativa de cada um
+	cache            map[string]cacheAssinatura
+	errosAssinaturas map[string]string
+	tentativas       map[string]time.Time
+
+	cliente     *http.Client
+	atualizando sync.Mutex // uma atualização de assinaturas por vez
+	parar       chan struct{}
+	pararUma    sync.Once
+	atualizador sync.WaitGroup
+	avisador    sync.WaitGroup // verificação dos lembretes
 }
 
 // Evento representa um evento no calendário
@@ -23,104 +42,187 @@ type Evento struct {
 	Descricao string `json:"descricao"`
 	Cor       string `json:"cor"` // Cor do evento (hex)
 	CreatedAt string `json:"createdAt"`
+
+	Recorrencia *Recorrencia `json:"recorrencia,omitempty"` // nil = evento único
+	UID         string       `json:"uid,omitempty"`         // UID de origem, quando importado de um .ics
+	Lembretes   []int        `json:"lembretes,omitempty"`   // Minutos de antecedência de cada aviso
+}
+
+// CorEventoPadrao é a cor usada pelo calendário para eventos novos
+const CorEventoPadrao = "#ec4899"
+
+// esquemaEventos registra as migrações de calendario_data.json
+var esquemaEventos = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: dados passam a ser gravados em envelope
+	},
 }
 
 // NewCalendarioHandler cria um novo handler
-func NewCalendarioHandler(assetsDir string) *CalendarioHandler {
-	initDir := filepath.Join(assetsDir, "init")
+func NewCalendarioHandler(assetsDir string, backend storage.Backend) *CalendarioHandler {
+	renomearAntigos(backend)
 	return &CalendarioHandler{
-		assetsDir: assetsDir,
-		dataFile:  filepath.Join(initDir, "calendario_data.json"),
+		assetsDir:        assetsDir,
+		store:            storage.NewLista[Evento](backend, "calendario_data.json", esquemaEventos),
+		assinaturas:      storage.NewLista[Assinatura](backend, arquivoAssinaturas, esquemaAssinaturas),
+		lembretes:        storage.NewLista[DisparoLembrete](backend, arquivoLembretes, esquemaLembretes),
+		cache:            map[string]cacheAssinatura{},
+		errosAssinaturas: map[string]string{},
+		tentativas:       map[string]time.Time{},
+		cliente:          &http.Client{Timeout: 30 * time.Second},
+		parar:            make(chan struct{}),
 	}
 }
 
-// Startup é chamado quando o app inicia
+// renomearAntigos passa os documentos do calendário que mudaram de nome para
+// o nome atual. Se falhar, o antigo fica onde está e a próxima abertura
+// tenta de novo.
+func renomearAntigos(backend storage.Backend) {
+	storage.Renomear(backend, arquivoAssinaturasAntigo, arquivoAssinaturas)
+	storage.Renomear(backend, arquivoLembretesAntigo, arquivoLembretes)
+}
+
+// Startup é chamado quando o app inicia: começa a atualizar os calendários
+// assinados e a disparar os lembretes
 func (h *CalendarioHandler) Startup(ctx context.Context) {
 	h.ctx = ctx
+	h.iniciarAtualizador(ctx)
+	h.iniciarLembretes(ctx)
 }
 
-// SalvarEventos salva a lista de eventos
-func (h *CalendarioHandler) SalvarEventos(eventos []Evento) error {
-	return h.salvarEventosInterno(eventos)
+// Apontar passa a usar os dados de outro workspace
+func (h *CalendarioHandler) Apontar(assetsDir string, backend storage.Backend) {
+	h.mu.Lock()
+	h.assetsDir = assetsDir
+	h.cache = map[string]cacheAssinatura{}
+	h.errosAssinaturas = map[string]string{}
+	h.tentativas = map[string]time.Time{}
+	h.mu.Unlock()
+	renomearAntigos(backend)
+	h.store.Apontar(backend)
+	h.assinaturas.Apontar(backend)
+	h.lembretes.Apontar(backend)
 }
 
-// CarregarEventos carrega a lista de eventos
-func (h *CalendarioHandler) CarregarEventos() ([]Evento, error) {
-	// Garantir que a pasta init existe
-	initDir := filepath.Join(h.assetsDir, "init")
-	if err := os.MkdirAll(initDir, 0755); err != nil {
-		return []Evento{}, err
-	}
-
-	// Verificar se arquivo existe
-	if _, err := os.Stat(h.dataFile); os.IsNotExist(err) {
-		// Arquivo não existe - retornar lista vazia (app começa do zero)
-		return []Evento{}, nil
-	}
-
-	// Carregar dados
-	jsonData, err := os.ReadFile(h.dataFile)
-	if err != nil {
-		return []Evento{}, err
-	}
-
-	var eventos []Evento
-	err = json.Unmarshal(jsonData, &eventos)
-	if eventos == nil {
-		eventos = []Evento{}
+// SalvarEventos salva a lista de eventos
+func (h *CalendarioHandler) SalvarEventos(eventos []Evento) error {
+	for _, e := range eventos {
+		if err := validarEvento(e); err != nil {
+			return fmt.Errorf("%s: %w", e.Titulo, err)
+		}
 	}
-	return eventos, err
+	return h.store.Salvar(eventos)
 }
 
-// salvarEventosInterno salva lista de eventos (usado internamente)
-func (h *CalendarioHandler) salvarEventosInterno(eventos []Evento) error {
-	jsonData, err := json.MarshalIndent(eventos, "", "  ")
-	if err != nil {
+// validarEvento confere a recorrência e os lembretes antes de gravar
+func validarEvento(e Evento) error {
+	if err := validarRecorrencia(e); err != nil {
 		return err
 	}
-	return os.WriteFile(h.dataFile, jsonData, 0644)
+	return validarLembretes(e)
+}
+
+// CarregarEventos carrega a lista de eventos
+func (h *CalendarioHandler) CarregarEventos() ([]Evento, error) {
+	return h.store.Carregar()
 }
 
 // AdicionarEvento adiciona um novo evento
 func (h *CalendarioHandler) AdicionarEvento(evento Evento) error {
-	eventos, err := h.CarregarEventos()
-	if err != nil {
+	if err := validarEvento(evento); err != nil {
 		return err
 	}
-	eventos = append(eventos, evento)
-	return h.SalvarEventos(eventos)
+	return h.store.Atualizar(func(eventos *[]Evento) error {
+		*eventos = append(*eventos, evento)
+		return nil
+	})
 }
 
 // AtualizarEvento atualiza um evento existente
 func (h *CalendarioHandler) AtualizarEvento(updatedEvento Evento) error {
-	eventos, err := h.CarregarEventos()
-	if err != nil {
+	if err := validarEvento(updatedEvento); err != nil {
 		return err
 	}
-
-	for i, evento := range eventos {
-		if evento.ID == updatedEvento.ID {
-			eventos[i] = updatedEvento
-			break
+	return h.store.Atualizar(func(eventos *[]Evento) error {
+		for i, evento := range *eventos {
+			if evento.ID == updatedEvento.ID {
+				(*eventos)[i] = updatedEvento
+				break
+			}
 		}
-	}
-
-	return h.SalvarEventos(eventos)
+		return nil
+	})
 }
 
 // DeletarEvento remove um evento pelo ID
 func (h *CalendarioHandler) DeletarEvento(id string) error {
-	eventos, err := h.CarregarEventos()
+	return h.store.Atualizar(func(eventos *[]Evento) error {
+		filtered := []Evento{}
+		for _, evento := range *eventos {
+			if evento.ID != id {
+				filtered = append(filtered, evento)
+			}
+		}
+		*eventos = filtered
+		return nil
+	})
+}
+
+// ListarOcorrencias retorna os eventos entre inicio e fim (YYYY-MM-DD,
+// inclusive), com cada evento recorrente repetido em todas as suas datas,
+// ordenados por data e hora. Os eventos dos calendários assinados entram
+// como somente leitura.
+func (h *CalendarioHandler) ListarOcorrencias(inicio, fim string) ([]Ocorrencia, error) {
+	de, ate, err := intervaloConsulta(inicio, fim)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	eventos, err := h.store.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	ocorrencias := ocorrenciasDosEventos(eventos, de, ate)
 
-	var filtered []Evento
-	for _, evento := range eventos {
-		if evento.ID != id {
-			filtered = append(filtered, evento)
+	assinaturas, err := h.assinaturas.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assinaturas {
+		for _, o := range ocorrenciasDosEventos(h.eventosAssinatura(a.ID), de, ate) {
+			o.Cor = a.Cor
+			o.SomenteLeitura = true
+			o.Calendario = a.Nome
+			ocorrencias = append(ocorrencias, o)
 		}
 	}
 
-	return h.SalvarEventos(filtered)
+	ordenarOcorrencias(ocorrencias)
+	return ocorrencias, nil
+}
+
+// PularOcorrencia tira uma data (YYYY-MM-DD) de um evento recorrente, sem
+// mexer nas outras
+func (h *CalendarioHandler) PularOcorrencia(id, data string) error {
+	if _, err := lerData(data); err != nil {
+		return fmt.Errorf("data inválida: %s", data)
+	}
+	return h.store.Atualizar(func(eventos *[]Evento) error {
+		for i := range *eventos {
+			e := &(*eventos)[i]
+			if e.ID != id {
+				continue
+			}
+			if e.Recorrencia == nil {
+				return fmt.Errorf("o evento %s não se repete", id)
+			}
+			for _, d := range e.Recorrencia.Excecoes {
+				if d == data {
+					return nil
+				}
+			}
+			e.Recorrencia.Excecoes = append(e.Recorrencia.Excecoes, data)
+			return nil
+		}
+		return fmt.Errorf("evento não encontrado: %s", id)
+	})
 }
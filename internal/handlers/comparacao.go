@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// ItemDiferente é um item que muda se o backup for restaurado
+type ItemDiferente struct {
+	ID     string          `json:"id"`
+	Titulo string          `json:"titulo"`
+	Tipo   string          `json:"tipo"`             // "adicionado", "removido" ou "alterado"
+	Atual  json.RawMessage `json:"atual,omitempty"`  // Versão nos dados atuais
+	Backup json.RawMessage `json:"backup,omitempty"` // Versão no backup
+}
+
+// DiferencaModulo resume o que restaurar um arquivo de dados mudaria.
+// Adicionados existem só no backup, removidos só nos dados atuais.
+type DiferencaModulo struct {
+	Arquivo     string          `json:"arquivo"`
+	Modulo      string          `json:"modulo"`
+	Adicionados int             `json:"adicionados"`
+	Removidos   int             `json:"removidos"`
+	Alterados   int             `json:"alterados"`
+	Itens       []ItemDiferente `json:"itens"`
+}
+
+// itemComparavel é um item de um módulo já pronto para comparação
+type itemComparavel struct {
+	id       string
+	titulo   string
+	conteudo json.RawMessage
+}
+
+// itensDeLista extrai os itens de um arquivo que é uma lista simples
+func itensDeLista[E any](esquema storage.Esquema, chave func(E) (string, string)) func([]byte) ([]itemComparavel, error) {
+	return func(conteudo []byte) ([]itemComparavel, error) {
+		lista, err := storage.Decodificar[[]E](conteudo, esquema)
+		if err != nil {
+			return nil, err
+		}
+		itens := make([]itemComparavel, 0, len(lista))
+		for _, e := range lista {
+			id, titulo := chave(e)
+			itens = append(itens, novoItemComparavel(id, titulo, e))
+		}
+		return itens, nil
+	}
+}
+
+// itensDoQuadro extrai as tarefas das três colunas do Kanban
+func itensDoQuadro(conteudo []byte) ([]itemComparavel, error) {
+	quadro, err := storage.Decodificar[QuadroKanban](conteudo, esquemaQuadro)
+	if err != nil {
+		return nil, err
+	}
+	itens := []itemComparavel{}
+	for _, coluna := range [][]Tarefa{quadro.Objetivo, quadro.Fazendo, quadro.Feito} {
+		for _, t := range coluna {
+			itens = append(itens, novoItemComparavel(t.ID, t.Titulo, t))
+		}
+	}
+	return itens, nil
+}
+
+// itensDoCanvas extrai os nós do canvas de ideias
+func itensDoCanvas(conteudo []byte) ([]itemComparavel, error) {
+	canvas, err := storage.Decodificar[CanvasData](conteudo, esquemaCanvas)
+	if err != nil {
+		return nil, err
+	}
+	itens := make([]itemComparavel, 0, len(canvas.Nodes))
+	for _, node := range canvas.Nodes {
+		titulo, _ := node.Data["title"].(string)
+		if titulo == "" {
+			titulo = node.Type
+		}
+		itens = append(itens, novoItemComparavel(node.ID, titulo, node))
+	}
+	return itens, nil
+}
+
+func novoItemComparavel(id, titulo string, valor any) itemComparavel {
+	// encoding/json ordena as chaves dos maps, então o JSON serve de comparação
+	conteudo, _ := json.Marshal(valor)
+	return itemComparavel{id: id, titulo: titulo, conteudo: conteudo}
+}
+
+// CompararBackup mostra, por módulo, o que mudaria nos dados atuais se o
+// backup fosse restaurado
+func (h *BackupHandler) CompararBackup(nome string) ([]DiferencaModulo, error) {
+	if strings.ContainsAny(nome, "/\\") {
+		return []DiferencaModulo{}, fmt.Errorf("nome de backup inválido")
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backupDir)()
+
+	m, err := h.conteudoBackup(nome)
+	if err != nil {
+		return []DiferencaModulo{}, fmt.Errorf("backup não encontrado: %s", nome)
+	}
+
+	arquivos := make([]string, 0, len(modulosDados))
+	for arquivo := range modulosDados {
+		arquivos = append(arquivos, arquivo)
+	}
+	sort.Strings(arquivos)
+
+	diferencas := []DiferencaModulo{}
+	for _, arquivo := range arquivos {
+		modulo := modulosDados[arquivo]
+		if _, ok := m.Arquivos[arquivo]; !ok {
+			// Restaurar não mexe em arquivos que o backup não tem
+			continue
+		}
+
+		dadosBackup, err := h.lerDoBackup(nome, m, arquivo)
+		if err != nil {
+			return []DiferencaModulo{}, fmt.Errorf("erro ao ler %s do backup: %w", arquivo, err)
+		}
+		itensBackup, err := modulo.itens(dadosBackup)
+		if err != nil {
+			return []DiferencaModulo{}, fmt.Errorf("%s ilegível no backup: %w", arquivo, err)
+		}
+
+		// Dados atuais ausentes ou ilegíveis contam como vazios
+		var itensAtuais []itemComparavel
+		unlock := storage.Travar(h.backend.Chave(arquivo))
+		dadosAtuais, err := h.backend.Ler(arquivo)
+		unlock()
+		if err == nil {
+			itensAtuais, _ = modulo.itens(dadosAtuais)
+		}
+
+		d := compararItens(itensAtuais, itensBackup)
+		d.Arquivo = arquivo
+		d.Modulo = modulo.modulo
+		diferencas = append(diferencas, d)
+	}
+	return diferencas, nil
+}
+
+// compararItens casa os itens pelo ID, mantendo a ordem em que aparecem
+func compararItens(atuais, backup []itemComparavel) DiferencaModulo {
+	d := DiferencaModulo{Itens: []ItemDiferente{}}
+
+	porID := make(map[string]itemComparavel, len(atuais))
+	for _, item := range atuais {
+		porID[item.id] = item
+	}
+	noBackup := make(map[string]bool, len(backup))
+
+	for _, b := range backup {
+		noBackup[b.id] = true
+		atual, ok := porID[b.id]
+		switch {
+		case !ok:
+			d.Adicionados++
+			d.Itens = append(d.Itens, ItemDiferente{ID: b.id, Titulo: b.titulo, Tipo: "adicionado", Backup: b.conteudo})
+		case string(atual.conteudo) != string(b.conteudo):
+			d.Alterados++
+			d.Itens = append(d.Itens, ItemDiferente{ID: b.id, Titulo: b.titulo, Tipo: "alterado", Atual: atual.conteudo, Backup: b.conteudo})
+		}
+	}
+	for _, a := range atuais {
+		if !noBackup[a.id] {
+			d.Removidos++
+			d.Itens = append(d.Itens, ItemDiferente{ID: a.id, Titulo: a.titulo, Tipo: "removido", Atual: a.conteudo})
+		}
+	}
+	return d
+}
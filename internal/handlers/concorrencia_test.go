@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+const paralelos = 50
+
+// backendsTeste devolve os dois backends de dados, cada um numa pasta nova
+func backendsTeste(t *testing.T) map[string]storage.Backend {
+	t.Helper()
+	sqlite, err := storage.NewBackendSQLite(filepath.Join(t.TempDir(), "dados.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlite.Fechar() })
+	return map[string]storage.Backend{
+		"arquivos": storage.NewBackendArquivos(t.TempDir()),
+		"sqlite":   sqlite,
+	}
+}
+
+func TestAdicionarLinkConcorrente(t *testing.T) {
+	for nome, backend := range backendsTeste(t) {
+		t.Run(nome, func(t *testing.T) {
+			h := NewLinksHandler(t.TempDir(), backend)
+
+			var wg sync.WaitGroup
+			erros := make(chan error, paralelos)
+			for i := range paralelos {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					erros <- h.AdicionarLink(Link{ID: fmt.Sprintf("link-%d", i), URL: "https://exemplo.com"})
+				}()
+			}
+			wg.Wait()
+			close(erros)
+			for err := range erros {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			links, err := h.CarregarLinks()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(links) != paralelos {
+				t.Fatalf("%d links gravados, esperado %d", len(links), paralelos)
+			}
+			vistos := map[string]bool{}
+			for _, l := range links {
+				vistos[l.ID] = true
+			}
+			if len(vistos) != paralelos {
+				t.Errorf("links repetidos ou perdidos: %d IDs distintos", len(vistos))
+			}
+		})
+	}
+}
+
+func TestMoverTarefaConcorrente(t *testing.T) {
+	for nome, backend := range backendsTeste(t) {
+		t.Run(nome, func(t *testing.T) {
+			h := NewPlanejamentoHandler(t.TempDir(), backend)
+			for i := range paralelos {
+				if err := h.AdicionarTarefa(Tarefa{ID: fmt.Sprintf("tarefa-%d", i), Status: "objetivo"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Cada tarefa é movida por uma goroutine, enquanto outras
+			// adicionam tarefas novas na coluna de destino
+			var wg sync.WaitGroup
+			erros := make(chan error, 2*paralelos)
+			for i := range paralelos {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					erros <- h.MoverTarefa(fmt.Sprintf("tarefa-%d", i), "objetivo", "fazendo")
+				}()
+				go func() {
+					defer wg.Done()
+					erros <- h.AdicionarTarefa(Tarefa{ID: fmt.Sprintf("nova-%d", i), Status: "fazendo"})
+				}()
+			}
+			wg.Wait()
+			close(erros)
+			for err := range erros {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			quadro, err := h.CarregarQuadro()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(quadro.Objetivo) != 0 {
+				t.Errorf("%d tarefas ficaram em objetivo", len(quadro.Objetivo))
+			}
+			if len(quadro.Fazendo) != 2*paralelos {
+				t.Fatalf("%d tarefas em fazendo, esperado %d", len(quadro.Fazendo), 2*paralelos)
+			}
+			for _, tarefa := range quadro.Fazendo {
+				if tarefa.Status != "fazendo" {
+					t.Errorf("tarefa %s com status %q", tarefa.ID, tarefa.Status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// EventosNoIntervalo retorna os eventos do workspace entre inicio e fim
+// (YYYY-MM-DD, inclusive), com os recorrentes repetidos em cada data, em
+// ordem cronológica. Ao contrário de ListarOcorrencias, não inclui os
+// calendários assinados: só eventos que podem ser editados.
+func (h *CalendarioHandler) EventosNoIntervalo(inicio, fim string) ([]Ocorrencia, error) {
+	de, ate, err := intervaloConsulta(inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	eventos, err := h.store.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	ocorrencias := ocorrenciasDosEventos(eventos, de, ate)
+	ordenarOcorrencias(ocorrencias)
+	return ocorrencias, nil
+}
+
+// EventosDoDia retorna os eventos de uma data (YYYY-MM-DD), os sem hora
+// primeiro e depois por horário
+func (h *CalendarioHandler) EventosDoDia(data string) ([]Ocorrencia, error) {
+	return h.EventosNoIntervalo(data, data)
+}
+
+// BuscarEventos procura eventos do workspace pelo título e pela descrição.
+// Cada palavra do texto precisa aparecer, sem diferenciar maiúsculas nem
+// acentos ("reuniao" acha "Reunião"). O resultado vem em ordem cronológica
+// pela data do evento (a primeira, nos recorrentes).
+func (h *CalendarioHandler) BuscarEventos(texto string) ([]Evento, error) {
+	termos := strings.Fields(normalizarBusca(texto))
+	if len(termos) == 0 {
+		return nil, fmt.Errorf("informe o texto da busca")
+	}
+	eventos, err := h.store.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	encontrados := []Evento{}
+	for _, e := range eventos {
+		alvo := normalizarBusca(e.Titulo + "\n" + e.Descricao)
+		todos := true
+		for _, t := range termos {
+			if !strings.Contains(alvo, t) {
+				todos = false
+				break
+			}
+		}
+		if todos {
+			encontrados = append(encontrados, e)
+		}
+	}
+	OrdenarEventos(encontrados)
+	return encontrados, nil
+}
+
+// OrdenarEventos ordena pela data e pelo horário do evento (a primeira
+// data, nos recorrentes), com os eventos sem hora primeiro no dia
+func OrdenarEventos(eventos []Evento) {
+	sort.SliceStable(eventos, func(i, j int) bool {
+		return antesCronologico(eventos[i].Data, eventos[i].Hora, eventos[j].Data, eventos[j].Hora)
+	})
+}
+
+// --- Funções internas ---
+
+// intervaloConsulta valida o período de uma consulta de ocorrências
+func intervaloConsulta(inicio, fim string) (time.Time, time.Time, error) {
+	de, err := lerData(inicio)
+	if err != nil {
+		return de, de, fmt.Errorf("data inicial inválida: %s", inicio)
+	}
+	ate, err := lerData(fim)
+	if err != nil {
+		return de, ate, fmt.Errorf("data final inválida: %s", fim)
+	}
+	if ate.Before(de) {
+		return de, ate, fmt.Errorf("a data final é anterior à inicial")
+	}
+	if ate.Sub(de) > maxDiasConsulta*24*time.Hour {
+		return de, ate, fmt.Errorf("intervalo grande demais: até %d dias por consulta", maxDiasConsulta)
+	}
+	return de, ate, nil
+}
+
+// ocorrenciasDosEventos expande os eventos no período. Eventos sem
+// repetição são filtrados comparando a data como texto (YYYY-MM-DD ordena
+// como data), sem calcular nada, para que listas grandes continuem rápidas.
+func ocorrenciasDosEventos(eventos []Evento, de, ate time.Time) []Ocorrencia {
+	inicio, fim := de.Format(time.DateOnly), ate.Format(time.DateOnly)
+	ocorrencias := []Ocorrencia{}
+	for _, e := range eventos {
+		if e.Recorrencia == nil {
+			if e.Data >= inicio && e.Data <= fim {
+				if _, err := lerData(e.Data); err == nil {
+					ocorrencias = append(ocorrencias, Ocorrencia{Evento: e})
+				}
+			}
+			continue
+		}
+		if e.Data > fim {
+			continue
+		}
+		for _, d := range ocorrenciasEntre(e, de, ate) {
+			o := Ocorrencia{Evento: e, Recorrente: true}
+			o.Data = d.Format(time.DateOnly)
+			ocorrencias = append(ocorrencias, o)
+		}
+	}
+	return ocorrencias
+}
+
+// ordenarOcorrencias ordena por data e horário, com os eventos sem hora
+// primeiro no dia
+func ordenarOcorrencias(ocorrencias []Ocorrencia) {
+	sort.SliceStable(ocorrencias, func(i, j int) bool {
+		return antesCronologico(ocorrencias[i].Data, ocorrencias[i].Hora, ocorrencias[j].Data, ocorrencias[j].Hora)
+	})
+}
+
+// antesCronologico compara duas datas (YYYY-MM-DD) e horas (HH:MM). A hora
+// é lida como horário, então "9:05" vem antes de "10:00"; hora vazia ou
+// inválida conta como início do dia.
+func antesCronologico(dataA, horaA, dataB, horaB string) bool {
+	if dataA != dataB {
+		return dataA < dataB
+	}
+	return minutosDoDia(horaA) < minutosDoDia(horaB)
+}
+
+// minutosDoDia converte HH:MM em minutos desde a meia-noite (-1 sem hora)
+func minutosDoDia(hora string) int {
+	t, err := time.Parse("15:04", strings.TrimSpace(hora))
+	if err != nil {
+		return -1
+	}
+	return t.Hour()*60 + t.Minute()
+}
+
+// normalizarBusca deixa o texto em minúsculas e sem acentos
+func normalizarBusca(texto string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(texto) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
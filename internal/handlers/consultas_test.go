@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// calendarioConsultas cria um calendário com os eventos gravados como estão,
+// sem a validação de AdicionarEvento
+func calendarioConsultas(t *testing.T, eventos []Evento) *CalendarioHandler {
+	t.Helper()
+	dir := t.TempDir()
+	h := NewCalendarioHandler(dir, storage.NewBackendArquivos(filepath.Join(dir, "init")))
+	if err := h.store.Salvar(eventos); err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestAntesCronologico(t *testing.T) {
+	casos := []struct {
+		nome                       string
+		dataA, horaA, dataB, horaB string
+		esperado                   bool
+	}{
+		{"data anterior", "2026-03-09", "23:00", "2026-03-10", "08:00", true},
+		{"data posterior", "2026-03-11", "", "2026-03-10", "08:00", false},
+		{"sem hora antes de com hora", "2026-03-10", "", "2026-03-10", "00:00", true},
+		{"com hora depois de sem hora", "2026-03-10", "00:00", "2026-03-10", "", false},
+		{"9:05 antes de 10:00", "2026-03-10", "9:05", "2026-03-10", "10:00", true},
+		{"10:00 depois de 9:05", "2026-03-10", "10:00", "2026-03-10", "9:05", false},
+		{"hora inválida conta como sem hora", "2026-03-10", "depois", "2026-03-10", "00:00", true},
+		{"espaços em volta da hora", "2026-03-10", " 08:00 ", "2026-03-10", "8:30", true},
+		{"mesmo horário não vem antes", "2026-03-10", "09:00", "2026-03-10", "9:00", false},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if got := antesCronologico(c.dataA, c.horaA, c.dataB, c.horaB); got != c.esperado {
+				t.Errorf("antesCronologico(%s %q, %s %q) = %v", c.dataA, c.horaA, c.dataB, c.horaB, got)
+			}
+		})
+	}
+}
+
+func TestEventosNoIntervalo(t *testing.T) {
+	h := calendarioConsultas(t, []Evento{
+		{ID: "dez", Titulo: "Dez", Data: "2026-03-10", Hora: "10:00"},
+		{ID: "nove", Titulo: "Nove", Data: "2026-03-10", Hora: "9:05"},
+		{ID: "diaTodo", Titulo: "Dia todo", Data: "2026-03-10"},
+		{ID: "antes", Titulo: "Antes", Data: "2026-03-09", Hora: "23:00"},
+		{ID: "inicio", Titulo: "Início", Data: "2026-03-01"},
+		{ID: "fim", Titulo: "Fim", Data: "2026-03-31", Hora: "23:59"},
+		{ID: "fora", Titulo: "Fora", Data: "2026-04-01"},
+		{ID: "semanal", Titulo: "Semanal", Data: "2026-02-24", Hora: "08:00",
+			Recorrencia: &Recorrencia{Frequencia: "semanal"}},
+		{ID: "futuro", Titulo: "Futuro", Data: "2026-05-01",
+			Recorrencia: &Recorrencia{Frequencia: "diaria"}},
+		{ID: "dataInvalida", Titulo: "Data inválida", Data: "2026-03-1x"},
+	})
+
+	casos := []struct {
+		nome        string
+		inicio, fim string
+		esperado    []string // ID@data, na ordem
+		erro        bool
+	}{
+		{
+			nome:   "um dia: sem hora primeiro, depois por horário",
+			inicio: "2026-03-10", fim: "2026-03-10",
+			esperado: []string{"diaTodo@2026-03-10", "semanal@2026-03-10", "nove@2026-03-10", "dez@2026-03-10"},
+		},
+		{
+			nome:   "limites inclusivos",
+			inicio: "2026-03-01", fim: "2026-03-03",
+			esperado: []string{"inicio@2026-03-01", "semanal@2026-03-03"},
+		},
+		{
+			nome:   "mês inteiro, sem o dia seguinte",
+			inicio: "2026-03-09", fim: "2026-03-31",
+			esperado: []string{"antes@2026-03-09", "diaTodo@2026-03-10", "semanal@2026-03-10", "nove@2026-03-10",
+				"dez@2026-03-10", "semanal@2026-03-17", "semanal@2026-03-24", "semanal@2026-03-31", "fim@2026-03-31"},
+		},
+		{
+			nome:   "recorrente só a partir da sua data",
+			inicio: "2026-02-16", fim: "2026-02-24",
+			esperado: []string{"semanal@2026-02-24"},
+		},
+		{nome: "data inicial inválida", inicio: "10/03/2026", fim: "2026-03-10", erro: true},
+		{nome: "data final inválida", inicio: "2026-03-10", fim: "", erro: true},
+		{nome: "fim antes do início", inicio: "2026-03-10", fim: "2026-03-09", erro: true},
+		{nome: "intervalo grande demais", inicio: "2026-01-01", fim: "2030-01-01", erro: true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			ocorrencias, err := h.EventosNoIntervalo(c.inicio, c.fim)
+			if c.erro {
+				if err == nil {
+					t.Errorf("esperado erro, veio %d ocorrências", len(ocorrencias))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			obtidos := []string{}
+			for _, o := range ocorrencias {
+				obtidos = append(obtidos, o.ID+"@"+o.Data)
+				if o.Recorrente != (o.Recorrencia != nil) {
+					t.Errorf("%s@%s: Recorrente = %v", o.ID, o.Data, o.Recorrente)
+				}
+			}
+			if !slices.Equal(obtidos, c.esperado) {
+				t.Errorf("ocorrências %v, esperado %v", obtidos, c.esperado)
+			}
+		})
+	}
+}
+
+func TestEventosDoDia(t *testing.T) {
+	h := calendarioConsultas(t, []Evento{
+		{ID: "tarde", Titulo: "Tarde", Data: "2026-03-10", Hora: "14:30"},
+		{ID: "manha", Titulo: "Manhã", Data: "2026-03-10", Hora: "9:05"},
+		{ID: "semHora", Titulo: "Sem hora", Data: "2026-03-10"},
+		{ID: "horaInvalida", Titulo: "Hora inválida", Data: "2026-03-10", Hora: "depois"},
+		{ID: "vespera", Titulo: "Véspera", Data: "2026-03-09", Hora: "23:59"},
+		{ID: "seguinte", Titulo: "Dia seguinte", Data: "2026-03-11"},
+	})
+
+	casos := []struct {
+		nome     string
+		data     string
+		esperado []string
+		erro     bool
+	}{
+		{"sem hora primeiro, na ordem em que foram gravados", "2026-03-10", []string{"semHora", "horaInvalida", "manha", "tarde"}, false},
+		{"só eventos do dia", "2026-03-09", []string{"vespera"}, false},
+		{"dia vazio", "2026-03-12", []string{}, false},
+		{"data inválida", "2026-13-01", nil, true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			ocorrencias, err := h.EventosDoDia(c.data)
+			if c.erro {
+				if err == nil {
+					t.Error("esperado erro para data inválida")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := []string{}
+			for _, o := range ocorrencias {
+				ids = append(ids, o.ID)
+			}
+			if !slices.Equal(ids, c.esperado) {
+				t.Errorf("eventos %v, esperado %v", ids, c.esperado)
+			}
+		})
+	}
+}
+
+func TestBuscarEventos(t *testing.T) {
+	h := calendarioConsultas(t, []Evento{
+		{ID: "reuniao", Titulo: "Reunião de equipe", Data: "2026-03-10", Hora: "10:00"},
+		{ID: "cafe", Titulo: "Café", Data: "2026-03-10", Hora: "9:05", Descricao: "Reunião rápida com a ÉQUIPE"},
+		{ID: "medico", Titulo: "Médico", Data: "2026-03-02", Descricao: "levar exames"},
+		{ID: "aula", Titulo: "Aula de inglês", Data: "2026-02-01", Hora: "19:00",
+			Recorrencia: &Recorrencia{Frequencia: "semanal"}},
+		{ID: "diaTodo", Titulo: "Entrega do relatório", Data: "2026-03-10"},
+	})
+
+	casos := []struct {
+		nome     string
+		texto    string
+		esperado []string
+		erro     bool
+	}{
+		{"sem acento acha com acento", "reuniao", []string{"cafe", "reuniao"}, false},
+		{"com acento acha sem diferenciar maiúsculas", "ÉQUIPE", []string{"cafe", "reuniao"}, false},
+		{"procura também na descrição", "exames", []string{"medico"}, false},
+		{"todas as palavras precisam aparecer", "reuniao rapida", []string{"cafe"}, false},
+		{"palavras em qualquer ordem", "equipe cafe", []string{"cafe"}, false},
+		{"recorrente pela primeira data", "ingles", []string{"aula"}, false},
+		{"sem hora primeiro no dia", "e", []string{"aula", "medico", "diaTodo", "cafe", "reuniao"}, false},
+		{"nada encontrado", "dentista", []string{}, false},
+		{"texto vazio", "   ", nil, true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			eventos, err := h.BuscarEventos(c.texto)
+			if c.erro {
+				if err == nil {
+					t.Error("esperado erro para busca vazia")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := []string{}
+			for _, e := range eventos {
+				ids = append(ids, e.ID)
+			}
+			if !slices.Equal(ids, c.esperado) {
+				t.Errorf("encontrados %v, esperado %v", ids, c.esperado)
+			}
+		})
+	}
+}
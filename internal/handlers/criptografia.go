@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// UsarCifra troca a cifra dos backups e das exportações do workspace atual
+// (nil volta a gravá-los em claro). Os objetos já guardados são convertidos,
+// e o backup automático adiado por falta de senha é feito assim que a cifra
+// estiver desbloqueada.
+func (h *BackupHandler) UsarCifra(cifra *storage.Cifra) error {
+	h.mu.Lock()
+	anterior := h.cifra
+	h.cifra = cifra
+	h.mu.Unlock()
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	unlock := storage.Travar(h.backupDir)
+	err := h.converterObjetos(anterior)
+	unlock()
+	if err != nil {
+		return err
+	}
+
+	if (cifra == nil || !cifra.Bloqueada()) && h.backupPendente.CompareAndSwap(true, false) {
+		h.backupAutomatico()
+	}
+	return nil
+}
+
+// backupAutomatico faz o backup silencioso de quando o workspace é aberto
+func (h *BackupHandler) backupAutomatico() {
+	defer storage.Travar(h.backupDir)()
+	if _, err := h.fazerBackupInterno(); err == nil {
+		h.limparAntigos()
+		h.copiarParaDestinos()
+	}
+}
+
+// converterObjetos regrava os objetos dos backups com a cifra atual: cifra
+// os que estão em claro, decifra todos se a criptografia foi desligada e
+// troca a chave dos que foram cifrados pela cifra anterior. Quem chama deve
+// segurar a trava de backupDir.
+func (h *BackupHandler) converterObjetos(anterior *storage.Cifra) error {
+	if h.cifra != nil && h.cifra.Bloqueada() {
+		// Sem a senha não há o que converter; os objetos já estão cifrados
+		return nil
+	}
+	dir := filepath.Join(h.backupDir, pastaObjetos)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		caminho := filepath.Join(dir, entry.Name())
+
+		// Pelo cabeçalho já se sabe se o objeto está no formato certo
+		inicio, err := lerInicio(caminho, storage.TamanhoCabecalho)
+		if err != nil {
+			return err
+		}
+		if h.cifra == nil && !storage.Cifrado(inicio) || h.cifra != nil && h.cifra.Propria(inicio) {
+			continue
+		}
+
+		dados, err := os.ReadFile(caminho)
+		if err != nil {
+			return err
+		}
+		if storage.Cifrado(dados) {
+			if anterior == nil {
+				return fmt.Errorf("objeto %s do backup cifrado com outra senha", entry.Name())
+			}
+			if dados, err = anterior.Decifrar(dados); err != nil {
+				return fmt.Errorf("objeto %s do backup: %w", entry.Name(), err)
+			}
+		}
+
+		if h.cifra != nil {
+			if dados, err = h.cifra.Cifrar(dados); err != nil {
+				return err
+			}
+		}
+		if err := storage.EscreverAtomico(caminho, dados, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lerInicio lê até n bytes do início de um arquivo
+func lerInicio(caminho string, n int) ([]byte, error) {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	buf := make([]byte, n)
+	lidos, err := io.ReadFull(f, buf)
+	if err == io.ErrUnexpectedEOF || err == io.EOF {
+		err = nil
+	}
+	return buf[:lidos], err
+}
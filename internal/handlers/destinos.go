@@ -0,0 +1,429 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Destino é uma pasta extra para onde os backups são copiados (outro disco,
+// um pendrive ou uma pasta sincronizada como Nextcloud ou Dropbox). A
+// subpasta OrganizadorTDAH/<workspace> recebe a mesma estrutura de backups/
+// e pode ter uma retenção própria, por exemplo guardando backups semanais
+// por mais tempo que a pasta local.
+type Destino struct {
+	ID       string           `json:"id"`
+	Nome     string           `json:"nome"`
+	Pasta    string           `json:"pasta"`
+	Retencao PoliticaRetencao `json:"retencao"`
+
+	// Situação das cópias, atualizada a cada tentativa
+	UltimoBackup string `json:"ultimoBackup,omitempty"` // Backup mais recente presente no destino
+	UltimaCopia  string `json:"ultimaCopia,omitempty"`  // Quando a última cópia deu certo (RFC 3339)
+	Erro         string `json:"erro,omitempty"`         // Erro da última tentativa, vazio se deu certo
+	ErroEm       string `json:"erroEm,omitempty"`       // Quando a última tentativa falhou (RFC 3339)
+}
+
+// pastaDestinos é a subpasta criada dentro da pasta de cada destino
+const pastaDestinos = "OrganizadorTDAH"
+
+// esquemaDestinos registra as migrações de destinos.json
+var esquemaDestinos = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+// storeDestinos abre a lista de destinos gravada na pasta de backups do workspace
+func storeDestinos(backupDir string) *storage.Store[[]Destino] {
+	return storage.NewLista[Destino](storage.NewBackendArquivos(backupDir), "destinos.json", esquemaDestinos)
+}
+
+// ListarDestinos retorna os destinos extras de backup, com a situação da
+// última cópia de cada um
+func (h *BackupHandler) ListarDestinos() ([]Destino, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return storeDestinos(h.backupDir).Carregar()
+}
+
+// EscolherPastaDestino abre o seletor de pastas do sistema. Retorna vazio se
+// o usuário cancelar.
+func (h *BackupHandler) EscolherPastaDestino() (string, error) {
+	if h.ctx == nil {
+		return "", fmt.Errorf("seleção de pasta requer o app desktop")
+	}
+	return runtime.OpenDirectoryDialog(h.ctx, runtime.OpenDialogOptions{
+		Title:                "Pasta para cópias dos backups",
+		CanCreateDirectories: true,
+	})
+}
+
+// AdicionarDestino inclui uma pasta para onde os backups passam a ser
+// copiados, com a retenção padrão
+func (h *BackupHandler) AdicionarDestino(nome string, pasta string) (Destino, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	d := Destino{ID: uuid.New().String()[:8], Nome: strings.TrimSpace(nome), Retencao: retencaoPadrao}
+	var err error
+	if d.Pasta, err = h.validarPastaDestino(pasta); err != nil {
+		return Destino{}, err
+	}
+	if d.Nome == "" {
+		d.Nome = filepath.Base(d.Pasta)
+	}
+
+	err = storeDestinos(h.backupDir).Atualizar(func(destinos *[]Destino) error {
+		for _, outro := range *destinos {
+			if outro.Pasta == d.Pasta {
+				return fmt.Errorf("a pasta %s já é um destino de backup", d.Pasta)
+			}
+		}
+		*destinos = append(*destinos, d)
+		return nil
+	})
+	if err != nil {
+		return Destino{}, err
+	}
+	return d, nil
+}
+
+// AtualizarDestino altera o nome, a pasta e a retenção de um destino. A
+// situação das cópias não é alterada por aqui.
+func (h *BackupHandler) AtualizarDestino(destino Destino) error {
+	if err := destino.Retencao.validar(); err != nil {
+		return err
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	pasta, err := h.validarPastaDestino(destino.Pasta)
+	if err != nil {
+		return err
+	}
+	return storeDestinos(h.backupDir).Atualizar(func(destinos *[]Destino) error {
+		for i := range *destinos {
+			d := &(*destinos)[i]
+			if d.ID != destino.ID {
+				if d.Pasta == pasta {
+					return fmt.Errorf("a pasta %s já é um destino de backup", pasta)
+				}
+				continue
+			}
+			if nome := strings.TrimSpace(destino.Nome); nome != "" {
+				d.Nome = nome
+			}
+			d.Pasta = pasta
+			d.Retencao = destino.Retencao
+			return nil
+		}
+		return fmt.Errorf("destino não encontrado: %s", destino.ID)
+	})
+}
+
+// RemoverDestino para de copiar os backups para um destino. Os backups que
+// já estão na pasta dele não são apagados.
+func (h *BackupHandler) RemoverDestino(id string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return storeDestinos(h.backupDir).Atualizar(func(destinos *[]Destino) error {
+		filtrados := []Destino{}
+		for _, d := range *destinos {
+			if d.ID != id {
+				filtrados = append(filtrados, d)
+			}
+		}
+		if len(filtrados) == len(*destinos) {
+			return fmt.Errorf("destino não encontrado: %s", id)
+		}
+		*destinos = filtrados
+		return nil
+	})
+}
+
+// CopiarParaDestinos copia agora os backups para todos os destinos e
+// retorna a situação de cada um
+func (h *BackupHandler) CopiarParaDestinos() ([]Destino, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	unlock := storage.Travar(h.backupDir)
+	h.copiarParaDestinos()
+	unlock()
+	return storeDestinos(h.backupDir).Carregar()
+}
+
+// --- Funções internas ---
+
+// validarPastaDestino exige uma pasta que já exista (um pendrive
+// desconectado não vira uma pasta vazia no disco local) e que fique fora do
+// workspace
+func (h *BackupHandler) validarPastaDestino(pasta string) (string, error) {
+	pasta = strings.TrimSpace(pasta)
+	if pasta == "" {
+		return "", fmt.Errorf("informe a pasta do destino")
+	}
+	pasta, err := filepath.Abs(pasta)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(pasta); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("pasta não encontrada: %s", pasta)
+	}
+	if rel, err := filepath.Rel(h.assetsDir, pasta); err == nil && filepath.IsLocal(rel) {
+		return "", fmt.Errorf("o destino precisa ficar fora da pasta de dados")
+	}
+	return pasta, nil
+}
+
+// copiarParaDestinos leva os backups a cada destino e registra o resultado.
+// Quem chama deve segurar a trava de backupDir.
+func (h *BackupHandler) copiarParaDestinos() {
+	store := storeDestinos(h.backupDir)
+	destinos, err := store.Carregar()
+	if err != nil || len(destinos) == 0 {
+		return
+	}
+
+	for _, d := range destinos {
+		ultimo, pendentes, err := h.copiarPara(d)
+		agora := time.Now().Format(time.RFC3339)
+		store.Atualizar(func(atuais *[]Destino) error {
+			for i := range *atuais {
+				a := &(*atuais)[i]
+				if a.ID != d.ID {
+					continue
+				}
+				if err != nil {
+					a.Erro = err.Error()
+					a.ErroEm = agora
+				} else {
+					a.Erro = ""
+					a.ErroEm = ""
+					a.UltimaCopia = agora
+					a.UltimoBackup = ultimo
+					if len(pendentes) > 0 {
+						a.Erro = fmt.Sprintf("backups cifrados com uma senha antiga, que não puderam ser convertidos "+
+							"(continuam no destino, mas só abrem com aquela senha): %s", strings.Join(pendentes, ", "))
+						a.ErroEm = agora
+					}
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// pastaNoDestino é onde os backups do workspace ficam dentro da pasta do
+// destino: <pasta>/OrganizadorTDAH/<workspace>. Assim a pasta escolhida pode
+// ter outros arquivos, que a retenção nunca toca, e ser usada por mais de um
+// workspace.
+func (h *BackupHandler) pastaNoDestino(d Destino) string {
+	// O workspace padrão usa a própria pasta de dados; os outros ficam em
+	// <dados>/workspaces/<id>
+	workspace := "padrao"
+	if filepath.Base(filepath.Dir(h.assetsDir)) == "workspaces" {
+		workspace = filepath.Base(h.assetsDir)
+	}
+	return filepath.Join(d.Pasta, pastaDestinos, workspace)
+}
+
+// copiarPara copia para o destino os backups que a retenção dele mantém e
+// que ainda não estão lá, atualiza os objetos que mudaram de formato e
+// aplica a retenção aos que já estavam. Retorna o backup mais recente
+// presente no destino e os backups do destino que não puderam ser
+// convertidos para a senha atual.
+func (h *BackupHandler) copiarPara(d Destino) (string, []string, error) {
+	if info, err := os.Stat(d.Pasta); err != nil || !info.IsDir() {
+		return "", nil, fmt.Errorf("pasta %s não encontrada (o disco está conectado?)", d.Pasta)
+	}
+	pasta := h.pastaNoDestino(d)
+	if err := os.MkdirAll(pasta, 0755); err != nil {
+		return "", nil, err
+	}
+	defer storage.Travar(pasta)()
+
+	locais, err := h.listarBackups()
+	if err != nil {
+		return "", nil, err
+	}
+	remotos, err := listarBackupsEm(pasta)
+	if err != nil {
+		return "", nil, err
+	}
+
+	presentes := make(map[string]bool, len(remotos))
+	todos := append([]BackupInfo{}, remotos...)
+	for _, b := range remotos {
+		presentes[b.Nome] = true
+	}
+	for _, b := range locais {
+		if !presentes[b.Nome] {
+			todos = append(todos, b)
+		}
+	}
+	sort.Slice(todos, func(i, j int) bool {
+		return todos[i].Data > todos[j].Data
+	})
+	manter := d.Retencao.manter(todos, time.Now())
+	for nome := range h.fixados() {
+		manter[nome] = true
+	}
+
+	conferidos := make(map[string]bool)
+	for _, b := range locais {
+		if presentes[b.Nome] || !manter[b.Nome] {
+			continue
+		}
+		m, err := h.conteudoBackup(b.Nome)
+		if err != nil {
+			return "", nil, err
+		}
+		if m.legado {
+			// Backups antigos, sem manifesto, ficam só na pasta local
+			continue
+		}
+		for _, hash := range objetosDe(m) {
+			if _, err := h.sincronizarObjeto(pasta, hash, conferidos); err != nil {
+				return "", nil, err
+			}
+		}
+		if err := gravarManifestoEm(pasta, b.Nome, m); err != nil {
+			return "", nil, err
+		}
+		presentes[b.Nome] = true
+	}
+
+	// Depois de ligar a criptografia ou trocar a senha, os objetos que já
+	// estavam no destino ficam em claro ou com a chave antiga. Os que ainda
+	// existem aqui são copiados de novo. Os backups com objetos que não há
+	// como converter continuam no destino (a retenção decide sobre eles como
+	// sobre os outros) e são informados a quem chamou.
+	pendentes := []string{}
+	for _, b := range todos {
+		if !presentes[b.Nome] || !manter[b.Nome] {
+			continue
+		}
+		m, err := conteudoBackupEm(pasta, b.Nome)
+		if err != nil {
+			continue
+		}
+		for _, hash := range objetosDe(m) {
+			atual, err := h.sincronizarObjeto(pasta, hash, conferidos)
+			if err != nil {
+				return "", nil, err
+			}
+			if !atual {
+				pendentes = append(pendentes, b.Nome)
+				break
+			}
+		}
+	}
+
+	noDestino := []BackupInfo{}
+	for _, b := range todos {
+		if presentes[b.Nome] {
+			noDestino = append(noDestino, b)
+		}
+	}
+	podar(pasta, noDestino, manter)
+
+	for _, b := range noDestino {
+		if manter[b.Nome] {
+			return b.Nome, pendentes, nil
+		}
+	}
+	return "", pendentes, nil
+}
+
+// objetosDe lista os hashes dos objetos usados por um backup
+func objetosDe(m manifesto) []string {
+	hashes := make([]string, 0, len(m.Arquivos)+len(m.Imagens))
+	for _, hash := range m.Arquivos {
+		hashes = append(hashes, hash)
+	}
+	for _, hash := range m.Imagens {
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// sincronizarObjeto deixa o objeto do destino no mesmo formato que o local
+// (em claro ou cifrado com a senha atual), comparando só o cabeçalho: copia
+// o local se faltar ou estiver diferente. Um objeto que só o destino tem é
+// cifrado se estiver em claro; se estiver cifrado com outra senha não há
+// como convertê-lo, e o retorno é false. conferidos guarda o resultado dos
+// objetos já vistos nesta cópia.
+func (h *BackupHandler) sincronizarObjeto(pasta, hash string, conferidos map[string]bool) (bool, error) {
+	if atual, visto := conferidos[hash]; visto {
+		return atual, nil
+	}
+	local := filepath.Join(h.backupDir, pastaObjetos, hash)
+	destino := filepath.Join(pasta, pastaObjetos, hash)
+	inicioDestino, errDestino := lerInicio(destino, storage.TamanhoCabecalho)
+
+	inicioLocal, err := lerInicio(local, storage.TamanhoCabecalho)
+	if err == nil {
+		if errDestino != nil || !bytes.Equal(inicioLocal, inicioDestino) {
+			if err := copiarObjeto(local, destino); err != nil {
+				return false, err
+			}
+		}
+		conferidos[hash] = true
+		return true, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	// O objeto só existe no destino
+	atual := true
+	switch {
+	case errDestino != nil:
+		// Também não está no destino: nada a converter
+	case h.cifra == nil:
+		atual = !storage.Cifrado(inicioDestino)
+	case h.cifra.Propria(inicioDestino):
+	case storage.Cifrado(inicioDestino):
+		atual = false
+	case h.cifra.Bloqueada():
+		// Sem a senha não dá para cifrar agora; fica para a próxima cópia
+	default:
+		dados, err := os.ReadFile(destino)
+		if err != nil {
+			return false, err
+		}
+		if dados, err = h.cifra.Cifrar(dados); err != nil {
+			return false, err
+		}
+		if err := storage.EscreverAtomico(destino, dados, 0600); err != nil {
+			return false, err
+		}
+	}
+	conferidos[hash] = atual
+	return atual, nil
+}
+
+// copiarObjeto copia um objeto para a pasta de objetos do destino, como está
+// (um objeto cifrado continua cifrado)
+func copiarObjeto(local, destino string) error {
+	dados, err := os.ReadFile(local)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(destino), 0755); err != nil {
+		return err
+	}
+	return storage.EscreverAtomico(destino, dados, 0600)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+func TestCopiarParaDestinoSubpastaECifra(t *testing.T) {
+	assets := t.TempDir()
+	h := NewBackupHandler(assets, storage.NewBackendArquivos(filepath.Join(assets, "init")))
+	links := NewLinksHandler(assets, h.backend)
+	if err := links.AdicionarLink(Link{ID: "1", URL: "https://exemplo.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pasta := t.TempDir()
+	alheio := filepath.Join(pasta, "backup_2020-01-01_00-00-00")
+	if err := os.Mkdir(alheio, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.AdicionarDestino("Pendrive", pasta); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.CriarBackup("", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.CopiarParaDestinos(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(alheio); err != nil {
+		t.Errorf("pasta fora da subpasta do app foi mexida: %v", err)
+	}
+	objetos := filepath.Join(pasta, pastaDestinos, "padrao", pastaObjetos)
+	entradas, err := os.ReadDir(objetos)
+	if err != nil || len(entradas) == 0 {
+		t.Fatalf("objetos não copiados para %s: %v", objetos, err)
+	}
+
+	cifra := storage.NovaCifra([]byte("0123456789abcdef"))
+	if err := cifra.Desbloquear("senha", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.UsarCifra(cifra); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.CopiarParaDestinos(); err != nil {
+		t.Fatal(err)
+	}
+	entradas, err = os.ReadDir(objetos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entradas {
+		dados, err := os.ReadFile(filepath.Join(objetos, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cifra.Propria(dados) {
+			t.Errorf("objeto %s continua em claro no destino", e.Name())
+		}
+	}
+}
+
+func TestCopiarParaDestinoMantemFixadoComSenhaAntiga(t *testing.T) {
+	assets := t.TempDir()
+	h := NewBackupHandler(assets, storage.NewBackendArquivos(filepath.Join(assets, "init")))
+	links := NewLinksHandler(assets, h.backend)
+	if err := links.AdicionarLink(Link{ID: "1", URL: "https://exemplo.com"}); err != nil {
+		t.Fatal(err)
+	}
+	antiga := storage.NovaCifra([]byte("0123456789abcdef"))
+	if err := antiga.Desbloquear("senha antiga", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.UsarCifra(antiga); err != nil {
+		t.Fatal(err)
+	}
+
+	pasta := t.TempDir()
+	if _, err := h.AdicionarDestino("Pendrive", pasta); err != nil {
+		t.Fatal(err)
+	}
+	fixado, err := h.CriarBackup("antes da mudança", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.CopiarParaDestinos(); err != nil {
+		t.Fatal(err)
+	}
+
+	// O backup fixado passa a existir só no destino, cifrado com a senha
+	// antiga, e a senha é trocada
+	if err := os.RemoveAll(filepath.Join(h.backupDir, fixado.Nome)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(h.backupDir, pastaObjetos)); err != nil {
+		t.Fatal(err)
+	}
+	nova := storage.NovaCifra([]byte("fedcba9876543210"))
+	if err := nova.Desbloquear("senha nova", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.UsarCifra(nova); err != nil {
+		t.Fatal(err)
+	}
+	destinos, err := h.CopiarParaDestinos()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noDestino := filepath.Join(pasta, pastaDestinos, "padrao")
+	if _, err := os.Stat(filepath.Join(noDestino, fixado.Nome)); err != nil {
+		t.Errorf("backup fixado apagado do destino: %v", err)
+	}
+	entradas, err := os.ReadDir(filepath.Join(noDestino, pastaObjetos))
+	if err != nil || len(entradas) == 0 {
+		t.Errorf("objetos do backup fixado apagados do destino: %v", err)
+	}
+	if len(destinos) != 1 || !strings.Contains(destinos[0].Erro, fixado.Nome) {
+		t.Errorf("destino sem o aviso do backup não convertido: %+v", destinos)
+	}
+}
+
+func TestValidarPastaDestino(t *testing.T) {
+	raiz := t.TempDir()
+	assets := filepath.Join(raiz, "dados")
+	vizinha := filepath.Join(raiz, "dados..backup")
+	dentro := filepath.Join(assets, "sub")
+	pontos := filepath.Join(assets, "..pontos") // dentro, apesar do nome
+	for _, p := range []string{assets, vizinha, dentro, pontos} {
+		if err := os.MkdirAll(p, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewBackupHandler(assets, storage.NewBackendArquivos(filepath.Join(assets, "init")))
+
+	casos := []struct {
+		pasta string
+		ok    bool
+	}{
+		{assets, false},
+		{dentro, false},
+		{pontos, false},
+		{vizinha, true},
+		{raiz, true},
+	}
+	for _, c := range casos {
+		_, err := h.validarPastaDestino(c.pasta)
+		if (err == nil) != c.ok {
+			t.Errorf("%s: erro = %v, esperado ok = %v", c.pasta, err, c.ok)
+		}
+	}
+}
@@ -0,0 +1,472 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Um arquivo exportado é um .zip com todos os dados do workspace:
+//
+//	manifesto.json        formato, data e sha256 de cada arquivo
+//	dados/*_data.json     documentos, como estão no backend
+//	img/*                 imagens do canvas
+//
+// Em um workspace com criptografia o .zip inteiro é cifrado com a senha dele.
+const formatoExportacao = 1
+
+// maxEntradaExportacao limita o tamanho descompactado de cada arquivo do
+// .zip, para que um arquivo malformado não esgote a memória
+const maxEntradaExportacao = 256 << 20
+
+// Modos de importação
+const (
+	ImportarSubstituindo = "substituir" // Os dados do arquivo substituem os atuais; módulos ausentes nele são apagados
+	ImportarMesclando    = "mesclar"    // Itens novos são acrescentados; em conflito de ID ficam os atuais
+)
+
+// manifestoExportacao é o manifesto.json de um arquivo exportado
+type manifestoExportacao struct {
+	Formato  int               `json:"formato"`
+	CriadoEm string            `json:"criadoEm"`
+	Arquivos map[string]string `json:"arquivos"` // caminho no zip → sha256
+}
+
+// ExportarDados pergunta onde salvar e exporta todos os dados do workspace
+// em um único .zip. Retorna o caminho escolhido (vazio se cancelado).
+func (h *BackupHandler) ExportarDados() (string, error) {
+	if h.ctx == nil {
+		return "", fmt.Errorf("exportação requer o app desktop")
+	}
+	caminho, err := runtime.SaveFileDialog(h.ctx, runtime.SaveDialogOptions{
+		Title:           "Exportar dados",
+		DefaultFilename: "organizador-tdah_" + time.Now().Format("2006-01-02") + ".zip",
+		Filters:         []runtime.FileFilter{{DisplayName: "Arquivo zip", Pattern: "*.zip"}},
+	})
+	if err != nil || caminho == "" {
+		return "", err
+	}
+	return caminho, h.ExportarArquivo(caminho)
+}
+
+// ImportarDados pergunta qual arquivo importar e o importa no modo informado.
+// Retorna o caminho escolhido (vazio se cancelado).
+func (h *BackupHandler) ImportarDados(modo string) (string, error) {
+	if h.ctx == nil {
+		return "", fmt.Errorf("importação requer o app desktop")
+	}
+	caminho, err := runtime.OpenFileDialog(h.ctx, runtime.OpenDialogOptions{
+		Title:   "Importar dados",
+		Filters: []runtime.FileFilter{{DisplayName: "Arquivo zip", Pattern: "*.zip"}},
+	})
+	if err != nil || caminho == "" {
+		return "", err
+	}
+	return caminho, h.ImportarArquivo(caminho, modo)
+}
+
+// ExportarArquivo grava em caminho um .zip com os dados e as imagens do workspace
+func (h *BackupHandler) ExportarArquivo(caminho string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	nomes, err := h.backend.Listar()
+	if err != nil {
+		return err
+	}
+
+	conteudos := make(map[string][]byte)
+	for _, nome := range nomes {
+		unlock := storage.Travar(h.backend.Chave(nome))
+		dados, err := h.backend.Ler(nome)
+		unlock()
+		if err != nil {
+			return fmt.Errorf("erro ao ler %s: %w", nome, err)
+		}
+		conteudos["dados/"+nome] = dados
+	}
+
+	imgDir := filepath.Join(h.assetsDir, "img")
+	if entries, err := os.ReadDir(imgDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() || !nomeImagemValido(entry.Name()) {
+				continue
+			}
+			dados, err := os.ReadFile(filepath.Join(imgDir, entry.Name()))
+			if err != nil {
+				return fmt.Errorf("erro ao ler a imagem %s: %w", entry.Name(), err)
+			}
+			conteudos["img/"+entry.Name()] = dados
+		}
+	}
+
+	m := manifestoExportacao{
+		Formato:  formatoExportacao,
+		CriadoEm: time.Now().Format(time.RFC3339),
+		Arquivos: make(map[string]string, len(conteudos)),
+	}
+	caminhos := make([]string, 0, len(conteudos))
+	for c, dados := range conteudos {
+		m.Arquivos[c] = hashConteudo(dados)
+		caminhos = append(caminhos, c)
+	}
+	sort.Strings(caminhos)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	manifestoJSON, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := escreverNoZip(zw, "manifesto.json", manifestoJSON); err != nil {
+		return err
+	}
+	for _, c := range caminhos {
+		if err := escreverNoZip(zw, c, conteudos[c]); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	arquivo := buf.Bytes()
+	if h.cifra != nil {
+		if arquivo, err = h.cifra.Cifrar(arquivo); err != nil {
+			return err
+		}
+	}
+	return storage.EscreverAtomico(caminho, arquivo, 0600)
+}
+
+// ImportarArquivo importa um .zip gerado por ExportarArquivo. Tudo é
+// validado (manifesto, checksums e migrações) antes de qualquer gravação, e
+// o estado atual é salvo como backup: DesfazerRestauracao desfaz a importação.
+// Ao substituir, os documentos que não estão no arquivo são apagados; as
+// imagens atuais ficam, já que o canvas antigo pode voltar com o desfazer.
+func (h *BackupHandler) ImportarArquivo(caminho string, modo string) error {
+	if modo != ImportarSubstituindo && modo != ImportarMesclando {
+		return fmt.Errorf("modo de importação inválido: %s", modo)
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	documentos, imagens, err := lerExportacao(caminho, h.cifra)
+	if err != nil {
+		return err
+	}
+	defer storage.Travar(h.backupDir)()
+
+	arquivos := make([]string, 0, len(documentos))
+	for nome := range documentos {
+		arquivos = append(arquivos, nome)
+	}
+	// Ao substituir, os documentos atuais que não vieram no arquivo saem
+	removidos := []string{}
+	if modo == ImportarSubstituindo {
+		atuais, err := h.backend.Listar()
+		if err != nil {
+			return err
+		}
+		for _, nome := range atuais {
+			if _, ok := documentos[nome]; !ok {
+				removidos = append(removidos, nome)
+			}
+		}
+	}
+	sort.Strings(arquivos)
+
+	ts := time.Now().Format("2006-01-02_15-04-05")
+	r := &InfoRestauracao{
+		Backup:   BackupInfo{Nome: "importacao_" + ts, Data: ts, Label: filepath.Base(caminho)},
+		Arquivos: append(slices.Clone(arquivos), removidos...),
+		Imagens:  imagensNovas(filepath.Join(h.assetsDir, "img"), imagens),
+	}
+	// Sem o backup de segurança a importação não acontece
+	if err := h.registrarDesfazer(r, "antes de importar "+r.Backup.Label); err != nil {
+		return fmt.Errorf("erro ao salvar o estado atual antes de importar: %w", err)
+	}
+
+	// Imagens primeiro: o canvas importado nunca aponta para imagens ausentes
+	imgDir := filepath.Join(h.assetsDir, "img")
+	if err := os.MkdirAll(imgDir, 0755); err != nil {
+		return err
+	}
+	for imagem, dados := range imagens {
+		destino := filepath.Join(imgDir, imagem)
+		if _, err := os.Stat(destino); err == nil && modo == ImportarMesclando {
+			continue
+		}
+		if err := storage.EscreverAtomico(destino, dados, 0644); err != nil {
+			return fmt.Errorf("erro ao importar a imagem %s: %w", imagem, err)
+		}
+	}
+
+	for _, nome := range arquivos {
+		if err := h.importarDocumento(nome, documentos[nome], modo); err != nil {
+			return fmt.Errorf("erro ao importar %s: %w", nome, err)
+		}
+	}
+	for _, nome := range removidos {
+		if err := h.removerDocumento(nome); err != nil {
+			return fmt.Errorf("erro ao remover %s: %w", nome, err)
+		}
+	}
+	return nil
+}
+
+// importarDocumento grava um documento importado (já migrado), mesclando
+// com o atual se for o caso
+func (h *BackupHandler) importarDocumento(nome string, dados json.RawMessage, modo string) error {
+	defer storage.Travar(h.backend.Chave(nome))()
+
+	esquema, conhecido := esquemaDe(nome)
+	if modo == ImportarMesclando {
+		// Sem conseguir ler o atual, mesclar viraria substituir: melhor parar
+		atual, err := h.backend.Ler(nome)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return fmt.Errorf("erro ao ler os dados atuais: %w", err)
+		default:
+			if atual, err = esquema.Migrar(atual); err != nil {
+				return fmt.Errorf("dados atuais ilegíveis: %w", err)
+			}
+			if dados, err = mesclarDados(atual, dados); err != nil {
+				return err
+			}
+		}
+	}
+
+	conteudo := []byte(dados)
+	if conhecido {
+		var err error
+		if conteudo, err = esquema.Envelopar(dados); err != nil {
+			return err
+		}
+	}
+	return h.backend.Gravar(nome, conteudo)
+}
+
+// lerExportacao abre e valida um arquivo exportado, retornando os documentos
+// já migrados para a versão atual e as imagens. Arquivos cifrados precisam
+// de uma cifra com a mesma senha.
+func lerExportacao(caminho string, cifra *storage.Cifra) (map[string]json.RawMessage, map[string][]byte, error) {
+	arquivo, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil, nil, err
+	}
+	if storage.Cifrado(arquivo) {
+		if cifra == nil {
+			return nil, nil, fmt.Errorf("%s é protegido por senha: ative a criptografia com a mesma senha para importá-lo", filepath.Base(caminho))
+		}
+		if arquivo, err = cifra.Decifrar(arquivo); err != nil {
+			return nil, nil, fmt.Errorf("não foi possível abrir %s: %w", filepath.Base(caminho), err)
+		}
+	}
+	zr, err := zip.NewReader(bytes.NewReader(arquivo), int64(len(arquivo)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("não foi possível abrir %s: %w", filepath.Base(caminho), err)
+	}
+
+	entradas := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entradas[f.Name] = f
+	}
+
+	manifestoZip, ok := entradas["manifesto.json"]
+	if !ok {
+		return nil, nil, fmt.Errorf("arquivo sem manifesto.json: não foi gerado pela exportação do app")
+	}
+	manifestoJSON, err := lerDoZip(manifestoZip)
+	if err != nil {
+		return nil, nil, err
+	}
+	var m manifestoExportacao
+	if err := json.Unmarshal(manifestoJSON, &m); err != nil {
+		return nil, nil, fmt.Errorf("manifesto.json ilegível: %w", err)
+	}
+	if m.Formato < 1 || m.Formato > formatoExportacao {
+		return nil, nil, fmt.Errorf("formato de exportação %d não suportado", m.Formato)
+	}
+
+	documentos := make(map[string]json.RawMessage)
+	imagens := make(map[string][]byte)
+	for c, hash := range m.Arquivos {
+		f, ok := entradas[c]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s está no manifesto mas não no arquivo", c)
+		}
+		dados, err := lerDoZip(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		if hashConteudo(dados) != hash {
+			return nil, nil, fmt.Errorf("%s está corrompido (checksum não confere)", c)
+		}
+
+		dir, nome := path.Split(c)
+		switch {
+		case dir == "dados/" && nomeArquivoDadosValido(nome):
+			esquema, conhecido := esquemaDe(nome)
+			if !conhecido {
+				if !json.Valid(dados) {
+					return nil, nil, fmt.Errorf("%s não é um JSON válido", nome)
+				}
+				documentos[nome] = dados
+				continue
+			}
+			migrado, err := esquema.Migrar(dados)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", nome, err)
+			}
+			documentos[nome] = migrado
+		case dir == "img/" && nomeImagemValido(nome):
+			imagens[nome] = dados
+		default:
+			return nil, nil, fmt.Errorf("caminho inesperado no arquivo: %s", c)
+		}
+	}
+	// Um arquivo sem documentos, ao substituir, apagaria todos os dados
+	if len(documentos) == 0 {
+		return nil, nil, fmt.Errorf("%s não tem nenhum arquivo de dados", filepath.Base(caminho))
+	}
+	return documentos, imagens, nil
+}
+
+// esquemaDe retorna o esquema de um arquivo de dados conhecido
+func esquemaDe(nome string) (storage.Esquema, bool) {
+	modulo, ok := modulosDados[nome]
+	return modulo.esquema, ok
+}
+
+// mesclarDados acrescenta aos dados atuais os itens importados cujo ID ainda
+// não existe em nenhuma lista do documento. Campos que não são listas ficam
+// como estão nos dados atuais.
+func mesclarDados(atual, importado json.RawMessage) (json.RawMessage, error) {
+	a, err := decodificarGenerico(atual)
+	if err != nil {
+		return nil, err
+	}
+	b, err := decodificarGenerico(importado)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool)
+	coletarIDs(a, ids)
+	return json.Marshal(mesclarValor(a, b, ids))
+}
+
+func mesclarValor(a, b any, ids map[string]bool) any {
+	switch av := a.(type) {
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			return a
+		}
+		for _, item := range bv {
+			if id := idDe(item); id != "" && !ids[id] {
+				av = append(av, item)
+				ids[id] = true
+			}
+		}
+		return av
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			return a
+		}
+		for k, v := range bv {
+			if atual, existe := av[k]; existe {
+				av[k] = mesclarValor(atual, v, ids)
+			} else {
+				av[k] = v
+			}
+		}
+		return av
+	}
+	return a
+}
+
+// coletarIDs junta os IDs de todos os itens de listas do documento (uma
+// tarefa pode estar em qualquer coluna do Kanban, por exemplo)
+func coletarIDs(v any, ids map[string]bool) {
+	switch vv := v.(type) {
+	case []any:
+		for _, item := range vv {
+			if id := idDe(item); id != "" {
+				ids[id] = true
+			}
+		}
+	case map[string]any:
+		for _, campo := range vv {
+			coletarIDs(campo, ids)
+		}
+	}
+}
+
+func idDe(item any) string {
+	obj, ok := item.(map[string]any)
+	if !ok {
+		return ""
+	}
+	id, _ := obj["id"].(string)
+	return id
+}
+
+// decodificarGenerico lê JSON preservando números como estão
+func decodificarGenerico(dados json.RawMessage) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(dados))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func escreverNoZip(zw *zip.Writer, nome string, dados []byte) error {
+	w, err := zw.Create(nome)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(dados)
+	return err
+}
+
+func lerDoZip(f *zip.File) ([]byte, error) {
+	if strings.Contains(f.Name, "..") {
+		return nil, fmt.Errorf("caminho inválido no arquivo: %s", f.Name)
+	}
+	if f.UncompressedSize64 > maxEntradaExportacao {
+		return nil, fmt.Errorf("%s é grande demais (%d MB)", f.Name, f.UncompressedSize64>>20)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	// O tamanho declarado no zip pode mentir: lê no máximo o limite + 1
+	dados, err := io.ReadAll(io.LimitReader(rc, maxEntradaExportacao+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(dados) > maxEntradaExportacao {
+		return nil, fmt.Errorf("%s é grande demais", f.Name)
+	}
+	return dados, nil
+}
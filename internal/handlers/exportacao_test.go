@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// entradaZip é um arquivo do .zip de teste; tamanho, se informado, é o
+// tamanho descompactado declarado no cabeçalho
+type entradaZip struct {
+	nome    string
+	dados   string
+	tamanho uint64
+}
+
+// zipTeste grava um .zip de exportação com as entradas e um manifesto com
+// os hashes informados (vazio = hash correto; "-" = fora do manifesto)
+func zipTeste(t *testing.T, formato int, entradas []entradaZip, hashes map[string]string) string {
+	t.Helper()
+	m := manifestoExportacao{Formato: formato, CriadoEm: "2026-03-01T00:00:00Z", Arquivos: map[string]string{}}
+	for _, e := range entradas {
+		switch hash := hashes[e.nome]; hash {
+		case "":
+			m.Arquivos[e.nome] = hashConteudo([]byte(e.dados))
+		case "-":
+		default:
+			m.Arquivos[e.nome] = hash
+		}
+	}
+	for nome, hash := range hashes {
+		if _, ok := m.Arquivos[nome]; !ok && hash != "-" {
+			m.Arquivos[nome] = hash
+		}
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	manifestoJSON, _ := json.Marshal(m)
+	if err := escreverNoZip(zw, "manifesto.json", manifestoJSON); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entradas {
+		if e.tamanho == 0 {
+			if err := escreverNoZip(zw, e.nome, []byte(e.dados)); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		w, err := zw.CreateRaw(&zip.FileHeader{
+			Name:               e.nome,
+			Method:             zip.Store,
+			CompressedSize64:   uint64(len(e.dados)),
+			UncompressedSize64: e.tamanho,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e.dados))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	caminho := filepath.Join(t.TempDir(), "exportado.zip")
+	if err := os.WriteFile(caminho, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return caminho
+}
+
+func TestLerExportacao(t *testing.T) {
+	links := `{"schemaVersion":1,"dados":[{"id":"1","url":"https://exemplo.com"}]}`
+	casos := []struct {
+		nome     string
+		formato  int
+		entradas []entradaZip
+		hashes   map[string]string
+		erro     string // trecho da mensagem; vazio = deve aceitar
+	}{
+		{
+			nome:     "válido",
+			formato:  formatoExportacao,
+			entradas: []entradaZip{{nome: "dados/links_data.json", dados: links}, {nome: "img/foto.png", dados: "png"}},
+		},
+		{
+			nome:     "checksum errado",
+			formato:  formatoExportacao,
+			entradas: []entradaZip{{nome: "dados/links_data.json", dados: links}},
+			hashes:   map[string]string{"dados/links_data.json": strings.Repeat("0", 64)},
+			erro:     "checksum",
+		},
+		{
+			nome:     "no manifesto mas não no arquivo",
+			formato:  formatoExportacao,
+			entradas: []entradaZip{{nome: "dados/links_data.json", dados: links}},
+			hashes:   map[string]string{"dados/passos_data.json": strings.Repeat("0", 64)},
+			erro:     "não no arquivo",
+		},
+		{
+			nome:     "caminho inesperado",
+			formato:  formatoExportacao,
+			entradas: []entradaZip{{nome: "dados/links_data.json", dados: links}, {nome: "outros/script.sh", dados: "rm -rf"}},
+			erro:     "caminho inesperado",
+		},
+		{
+			nome:     "dados fora do padrão de nome",
+			formato:  formatoExportacao,
+			entradas: []entradaZip{{nome: "dados/links.json", dados: links}},
+			erro:     "caminho inesperado",
+		},
+		{
+			nome:     "subindo de pasta",
+			formato:  formatoExportacao,
+			entradas: []entradaZip{{nome: "dados/../links_data.json", dados: links}},
+			erro:     "caminho inválido",
+		},
+		{
+			nome:     "tamanho acima do limite",
+			formato:  formatoExportacao,
+			entradas: []entradaZip{{nome: "dados/links_data.json", dados: links, tamanho: maxEntradaExportacao + 1}},
+			erro:     "grande demais",
+		},
+		{
+			nome:     "JSON ilegível",
+			formato:  formatoExportacao,
+			entradas: []entradaZip{{nome: "dados/links_data.json", dados: `{"dados":[`}},
+			erro:     "JSON inválido",
+		},
+		{
+			nome:     "formato mais novo",
+			formato:  formatoExportacao + 1,
+			entradas: []entradaZip{{nome: "dados/links_data.json", dados: links}},
+			erro:     "não suportado",
+		},
+		{
+			nome:     "sem dados",
+			formato:  formatoExportacao,
+			entradas: []entradaZip{{nome: "img/foto.png", dados: "png"}},
+			erro:     "nenhum arquivo de dados",
+		},
+		{
+			nome:    "manifesto vazio",
+			formato: formatoExportacao,
+			erro:    "nenhum arquivo de dados",
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			caminho := zipTeste(t, c.formato, c.entradas, c.hashes)
+			documentos, imagens, err := lerExportacao(caminho, nil)
+			if c.erro == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				var lidos []Link
+				if err := json.Unmarshal(documentos["links_data.json"], &lidos); err != nil || len(lidos) != 1 {
+					t.Errorf("links = %s (%v)", documentos["links_data.json"], err)
+				}
+				if string(imagens["foto.png"]) != "png" {
+					t.Errorf("imagens = %v", imagens)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.erro) {
+				t.Errorf("erro = %v, esperado algo com %q", err, c.erro)
+			}
+		})
+	}
+}
+
+func TestLerExportacaoSemManifesto(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	escreverNoZip(zw, "dados/links_data.json", []byte(`[]`))
+	zw.Close()
+	caminho := filepath.Join(t.TempDir(), "exportado.zip")
+	if err := os.WriteFile(caminho, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := lerExportacao(caminho, nil); err == nil || !strings.Contains(err.Error(), "manifesto.json") {
+		t.Errorf("erro = %v", err)
+	}
+}
+
+func TestMesclarDados(t *testing.T) {
+	casos := []struct {
+		nome             string
+		atual, importado string
+		esperado         string
+	}{
+		{
+			nome:      "lista: só entram os IDs novos, e os atuais ficam como estão",
+			atual:     `[{"id":"1","titulo":"atual"},{"id":"2"}]`,
+			importado: `[{"id":"1","titulo":"importado"},{"id":"3"}]`,
+			esperado:  `[{"id":"1","titulo":"atual"},{"id":"2"},{"id":"3"}]`,
+		},
+		{
+			nome:      "quadro: um ID em outra coluna não é duplicado",
+			atual:     `{"objetivo":[{"id":"a"}],"fazendo":[]}`,
+			importado: `{"objetivo":[],"fazendo":[{"id":"a"},{"id":"b"}]}`,
+			esperado:  `{"objetivo":[{"id":"a"}],"fazendo":[{"id":"b"}]}`,
+		},
+		{
+			nome:      "campos que não são listas ficam os atuais; campos novos entram",
+			atual:     `{"viewport":{"x":1},"nodes":[]}`,
+			importado: `{"viewport":{"x":2},"nodes":[{"id":"n"}],"edges":[{"id":"e"}]}`,
+			esperado:  `{"viewport":{"x":1},"nodes":[{"id":"n"}],"edges":[{"id":"e"}]}`,
+		},
+		{
+			nome:      "itens sem ID não são importados; números ficam como estão",
+			atual:     `[{"id":"1","n":12345678901234567890}]`,
+			importado: `[{"titulo":"sem id"},{"id":"2","n":1.50}]`,
+			esperado:  `[{"id":"1","n":12345678901234567890},{"id":"2","n":1.50}]`,
+		},
+		{
+			nome:      "tipos diferentes: fica o atual",
+			atual:     `{"itens":[]}`,
+			importado: `[{"id":"1"}]`,
+			esperado:  `{"itens":[]}`,
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			mesclado, err := mesclarDados(json.RawMessage(c.atual), json.RawMessage(c.importado))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var obtido, esperado any
+			json.Unmarshal(mesclado, &obtido)
+			json.Unmarshal([]byte(c.esperado), &esperado)
+			if !reflect.DeepEqual(obtido, esperado) {
+				t.Errorf("mesclado = %s, esperado %s", mesclado, c.esperado)
+			}
+			if strings.Contains(c.esperado, "12345678901234567890") && !strings.Contains(string(mesclado), "12345678901234567890") {
+				t.Errorf("número alterado: %s", mesclado)
+			}
+		})
+	}
+}
+
+func TestImportarMesclandoComAtualIlegivel(t *testing.T) {
+	assets := t.TempDir()
+	init := filepath.Join(assets, "init")
+	h := NewBackupHandler(assets, storage.NewBackendArquivos(init))
+	if err := os.MkdirAll(init, 0755); err != nil {
+		t.Fatal(err)
+	}
+	ilegivel := []byte(`{"schemaVersion":1,"dados":[{"id":"1"`)
+	if err := os.WriteFile(filepath.Join(init, "links_data.json"), ilegivel, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	importado := json.RawMessage(`[{"id":"2","url":"https://exemplo.com"}]`)
+	if err := h.importarDocumento("links_data.json", importado, ImportarMesclando); err == nil {
+		t.Fatal("mesclar com os dados atuais ilegíveis deveria falhar")
+	}
+	lido, err := os.ReadFile(filepath.Join(init, "links_data.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(lido, ilegivel) {
+		t.Errorf("dados atuais substituídos: %s", lido)
+	}
+
+	// Sem documento atual, mesclar é só gravar
+	if err := h.importarDocumento("passos_data.json", json.RawMessage(`[{"id":"p"}]`), ImportarMesclando); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportarSubstituindoSemDados(t *testing.T) {
+	assets := t.TempDir()
+	h := NewBackupHandler(assets, storage.NewBackendArquivos(filepath.Join(assets, "init")))
+	links := NewLinksHandler(assets, h.backend)
+	if err := links.AdicionarLink(Link{ID: "1", URL: "https://exemplo.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	caminho := zipTeste(t, formatoExportacao, []entradaZip{{nome: "img/foto.png", dados: "png"}}, nil)
+	if err := h.ImportarArquivo(caminho, ImportarSubstituindo); err == nil {
+		t.Fatal("importar um arquivo sem dados deveria falhar")
+	}
+	if atuais, err := links.CarregarLinks(); err != nil || len(atuais) != 1 {
+		t.Errorf("links depois da importação recusada = %v, %v", atuais, err)
+	}
+}
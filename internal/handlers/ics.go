@@ -0,0 +1,495 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ResultadoICS resume uma importação de .ics
+type ResultadoICS struct {
+	Novos       int      `json:"novos"`
+	Atualizados int      `json:"atualizados"` // Eventos já importados antes (mesmo UID)
+	Avisos      []string `json:"avisos,omitempty"`
+}
+
+// sufixoUID completa o ID nos UIDs dos eventos criados no organizador, para
+// que voltem ao mesmo evento quando o .ics for importado de novo
+const sufixoUID = "@tdah-organizer"
+
+// ExportarICS pergunta onde salvar e exporta os eventos para um .ics.
+// Retorna o caminho escolhido (vazio se cancelado).
+func (h *CalendarioHandler) ExportarICS() (string, error) {
+	if h.ctx == nil {
+		return "", fmt.Errorf("exportação requer o app desktop")
+	}
+	caminho, err := runtime.SaveFileDialog(h.ctx, runtime.SaveDialogOptions{
+		Title:           "Exportar calendário",
+		DefaultFilename: "calendario_" + time.Now().Format("2006-01-02") + ".ics",
+		Filters:         []runtime.FileFilter{{DisplayName: "iCalendar", Pattern: "*.ics"}},
+	})
+	if err != nil || caminho == "" {
+		return "", err
+	}
+	return caminho, h.ExportarArquivoICS(caminho)
+}
+
+// ImportarICS pergunta qual .ics importar e acrescenta os eventos dele
+func (h *CalendarioHandler) ImportarICS() (ResultadoICS, error) {
+	if h.ctx == nil {
+		return ResultadoICS{}, fmt.Errorf("importação requer o app desktop")
+	}
+	caminho, err := runtime.OpenFileDialog(h.ctx, runtime.OpenDialogOptions{
+		Title:   "Importar calendário",
+		Filters: []runtime.FileFilter{{DisplayName: "iCalendar", Pattern: "*.ics"}},
+	})
+	if err != nil || caminho == "" {
+		return ResultadoICS{}, err
+	}
+	return h.ImportarArquivoICS(caminho)
+}
+
+// ExportarArquivoICS grava todos os eventos em caminho, no formato iCalendar
+func (h *CalendarioHandler) ExportarArquivoICS(caminho string) error {
+	eventos, err := h.store.Carregar()
+	if err != nil {
+		return err
+	}
+	return storage.EscreverAtomico(caminho, gerarICS(eventos, time.Now()), 0644)
+}
+
+// ImportarArquivoICS lê os VEVENTs de um .ics. Eventos com um UID já
+// importado (ou exportado daqui) são atualizados em vez de duplicados; se o
+// evento do arquivo não tiver alarmes, os lembretes do atual são mantidos.
+func (h *CalendarioHandler) ImportarArquivoICS(caminho string) (ResultadoICS, error) {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return ResultadoICS{}, err
+	}
+	defer f.Close()
+	importados, avisos, err := lerICS(f)
+	if err != nil {
+		return ResultadoICS{}, err
+	}
+
+	resultado := ResultadoICS{Avisos: avisos}
+	err = h.store.Atualizar(func(eventos *[]Evento) error {
+		resultado.Novos, resultado.Atualizados = 0, 0
+		for _, novo := range importados {
+			if i := indiceUID(*eventos, novo.UID); i >= 0 {
+				atual := &(*eventos)[i]
+				novo.ID, novo.CreatedAt = atual.ID, atual.CreatedAt
+				if atual.UID == "" {
+					novo.UID = "" // Evento daqui: o UID vem do ID
+				}
+				if len(novo.Lembretes) == 0 {
+					// Sem VALARM no arquivo: ficam os lembretes daqui
+					novo.Lembretes = atual.Lembretes
+				}
+				*atual = novo
+				resultado.Atualizados++
+				continue
+			}
+			novo.ID = NovoID("evento")
+			if novo.CreatedAt == "" {
+				novo.CreatedAt = CriadoAgora()
+			}
+			*eventos = append(*eventos, novo)
+			resultado.Novos++
+		}
+		return nil
+	})
+	return resultado, err
+}
+
+// uidEvento é o UID do evento no .ics: o de origem, ou o ID daqui
+func uidEvento(e Evento) string {
+	if e.UID != "" {
+		return e.UID
+	}
+	return e.ID + sufixoUID
+}
+
+// indiceUID procura o evento com o UID informado
+func indiceUID(eventos []Evento, uid string) int {
+	for i, e := range eventos {
+		if uidEvento(e) == uid {
+			return i
+		}
+	}
+	return -1
+}
+
+// --- Escrita ---
+
+// gerarICS monta um VCALENDAR com um VEVENT por evento. As horas saem sem
+// fuso (horário "flutuante"), como o organizador as guarda.
+func gerarICS(eventos []Evento, agora time.Time) []byte {
+	var b bytes.Buffer
+	linha := func(nome, valor string) { escreverLinhaICS(&b, nome+":"+valor) }
+
+	linha("BEGIN", "VCALENDAR")
+	linha("VERSION", "2.0")
+	linha("PRODID", "-//Organizador TDAH Pro//Calendario//PT")
+	linha("CALSCALE", "GREGORIAN")
+	for _, e := range eventos {
+		inicio, err := lerData(e.Data)
+		if err != nil {
+			continue
+		}
+		linha("BEGIN", "VEVENT")
+		linha("UID", escaparTextoICS(uidEvento(e)))
+		linha("DTSTAMP", agora.UTC().Format("20060102T150405Z"))
+		if criado, err := time.Parse(time.RFC3339, e.CreatedAt); err == nil {
+			linha("CREATED", criado.UTC().Format("20060102T150405Z"))
+		}
+		diaInteiro := e.Hora == ""
+		if diaInteiro {
+			linha("DTSTART;VALUE=DATE", inicio.Format("20060102"))
+			linha("DTEND;VALUE=DATE", inicio.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			linha("DTSTART", inicio.Format("20060102")+"T"+strings.ReplaceAll(e.Hora, ":", "")+"00")
+		}
+		linha("SUMMARY", escaparTextoICS(e.Titulo))
+		if e.Descricao != "" {
+			linha("DESCRIPTION", escaparTextoICS(e.Descricao))
+		}
+		if e.Cor != "" {
+			linha("X-TDAH-COR", e.Cor)
+		}
+		if r := e.Recorrencia; r != nil {
+			linha("RRULE", r.regraICS(!diaInteiro))
+			for _, d := range r.Excecoes {
+				d = strings.ReplaceAll(d, "-", "")
+				if diaInteiro {
+					linha("EXDATE;VALUE=DATE", d)
+				} else {
+					linha("EXDATE", d+"T"+strings.ReplaceAll(e.Hora, ":", "")+"00")
+				}
+			}
+		}
+		for _, m := range e.Lembretes {
+			linha("BEGIN", "VALARM")
+			linha("ACTION", "DISPLAY")
+			linha("DESCRIPTION", escaparTextoICS(e.Titulo))
+			linha("TRIGGER", duracaoICS(inicioAlarme(diaInteiro)-m))
+			linha("END", "VALARM")
+		}
+		linha("END", "VEVENT")
+	}
+	linha("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+// escreverLinhaICS grava uma linha dobrada em 75 bytes, sem partir
+// caracteres UTF-8, e terminada em CRLF
+func escreverLinhaICS(b *bytes.Buffer, linha string) {
+	limite := 75
+	for len(linha) > limite {
+		corte := limite
+		for corte > 0 && !utf8.RuneStart(linha[corte]) {
+			corte--
+		}
+		b.WriteString(linha[:corte])
+		b.WriteString("\r\n ")
+		linha = linha[corte:]
+		limite = 74 // O espaço da continuação conta
+	}
+	b.WriteString(linha)
+	b.WriteString("\r\n")
+}
+
+var escapeICS = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escaparTextoICS(s string) string {
+	return escapeICS.Replace(s)
+}
+
+// --- Leitura ---
+
+// propriedadeICS é uma linha de conteúdo: NOME;PARAM=valor:VALOR
+type propriedadeICS struct {
+	nome   string
+	params map[string]string
+	valor  string
+}
+
+// lerICS converte os VEVENTs do calendário em eventos, sem ID. Recursos que
+// o organizador não tem viram avisos em vez de erros.
+func lerICS(r io.Reader) ([]Evento, []string, error) {
+	linhas, err := desdobrarICS(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	eventos := []Evento{}
+	avisos := []string{}
+	var atual []propriedadeICS
+	dentro, achouCalendario := false, false
+	profundidade := 0 // componentes dentro do VEVENT (ex: VALARM)
+	alarme := false   // dentro de um VALARM do VEVENT
+	for _, l := range linhas {
+		p, ok := lerPropriedadeICS(l)
+		if !ok {
+			continue
+		}
+		switch {
+		case p.nome == "BEGIN" && strings.EqualFold(p.valor, "VCALENDAR"):
+			achouCalendario = true
+		case p.nome == "BEGIN" && strings.EqualFold(p.valor, "VEVENT") && !dentro:
+			dentro, atual = true, nil
+		case p.nome == "BEGIN" && dentro:
+			profundidade++
+			alarme = profundidade == 1 && strings.EqualFold(p.valor, "VALARM")
+		case p.nome == "END" && dentro && profundidade > 0:
+			profundidade--
+			alarme = false
+		case dentro && alarme && profundidade == 1 && p.nome == "TRIGGER":
+			// Só o TRIGGER do alarme interessa: vira um lembrete
+			atual = append(atual, p)
+		case p.nome == "END" && strings.EqualFold(p.valor, "VEVENT") && dentro:
+			dentro = false
+			e, aviso, ok := eventoDoICS(atual)
+			if aviso != "" {
+				avisos = append(avisos, aviso)
+			}
+			if ok {
+				eventos = append(eventos, e)
+			}
+		case dentro && profundidade == 0:
+			atual = append(atual, p)
+		}
+	}
+	if !achouCalendario {
+		return nil, nil, fmt.Errorf("o arquivo não é um calendário iCalendar (.ics)")
+	}
+	return eventos, avisos, nil
+}
+
+// desdobrarICS junta as linhas continuadas (iniciadas por espaço ou tab)
+func desdobrarICS(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	linhas := []string{}
+	for scanner.Scan() {
+		l := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(linhas) > 0 {
+			linhas[len(linhas)-1] += l[1:]
+			continue
+		}
+		linhas = append(linhas, l)
+	}
+	return linhas, scanner.Err()
+}
+
+// lerPropriedadeICS separa nome, parâmetros e valor. Os dois-pontos dentro
+// de parâmetros entre aspas não encerram o nome.
+func lerPropriedadeICS(linha string) (propriedadeICS, bool) {
+	aspas := false
+	fim := -1
+	for i, c := range linha {
+		if c == '"' {
+			aspas = !aspas
+		}
+		if c == ':' && !aspas {
+			fim = i
+			break
+		}
+	}
+	if fim < 0 {
+		return propriedadeICS{}, false
+	}
+	partes := strings.Split(linha[:fim], ";")
+	p := propriedadeICS{
+		nome:   strings.ToUpper(partes[0]),
+		params: map[string]string{},
+		valor:  linha[fim+1:],
+	}
+	for _, param := range partes[1:] {
+		if chave, valor, ok := strings.Cut(param, "="); ok {
+			p.params[strings.ToUpper(chave)] = strings.Trim(valor, `"`)
+		}
+	}
+	return p, true
+}
+
+// eventoDoICS converte as propriedades de um VEVENT
+func eventoDoICS(props []propriedadeICS) (Evento, string, bool) {
+	e := Evento{Cor: CorEventoPadrao}
+	var inicio *propriedadeICS
+	var regra string
+	var excecoes, alarmes []propriedadeICS
+	alteracao := false
+	for i, p := range props {
+		switch p.nome {
+		case "UID":
+			e.UID = desescaparTextoICS(p.valor)
+		case "SUMMARY":
+			e.Titulo = desescaparTextoICS(p.valor)
+		case "DESCRIPTION":
+			e.Descricao = desescaparTextoICS(p.valor)
+		case "DTSTART":
+			inicio = &props[i]
+		case "CREATED":
+			if t, err := time.Parse("20060102T150405Z", p.valor); err == nil {
+				e.CreatedAt = t.Format("2006-01-02T15:04:05.000Z")
+			}
+		case "X-TDAH-COR":
+			e.Cor = p.valor
+		case "COLOR":
+			if strings.HasPrefix(p.valor, "#") && e.Cor == CorEventoPadrao {
+				e.Cor = p.valor
+			}
+		case "RRULE":
+			regra = p.valor
+		case "EXDATE":
+			excecoes = append(excecoes, p)
+		case "TRIGGER":
+			alarmes = append(alarmes, p)
+		case "RECURRENCE-ID":
+			alteracao = true
+		}
+	}
+	if e.Titulo == "" {
+		e.Titulo = "(sem título)"
+	}
+	if alteracao {
+		return e, fmt.Sprintf("%q: alterações de uma só data de um evento repetido não são importadas", e.Titulo), false
+	}
+	if inicio == nil {
+		return e, fmt.Sprintf("%q: sem data de início, ignorado", e.Titulo), false
+	}
+	quando, diaInteiro, err := lerDataICS(*inicio)
+	if err != nil {
+		return e, fmt.Sprintf("%q: data inválida (%s), ignorado", e.Titulo, inicio.valor), false
+	}
+	e.Data = quando.Format(time.DateOnly)
+	if !diaInteiro {
+		e.Hora = quando.Format("15:04")
+	}
+	var aviso string
+	if ignorados := 0; len(alarmes) > 0 {
+		e.Lembretes, ignorados = lembretesDoICS(alarmes, diaInteiro)
+		if ignorados > 0 {
+			aviso = fmt.Sprintf("%q: %d alarme(s) não suportado(s) (depois do início, pelo fim ou em data fixa) ignorado(s)", e.Titulo, ignorados)
+		}
+	}
+
+	if regra == "" {
+		return e, aviso, true
+	}
+	rec, err := LerRRule(regra)
+	if err == nil {
+		for _, p := range excecoes {
+			for _, valor := range strings.Split(p.valor, ",") {
+				p.valor = valor
+				if d, _, err := lerDataICS(p); err == nil {
+					rec.Excecoes = append(rec.Excecoes, d.Format(time.DateOnly))
+				}
+			}
+		}
+		e.Recorrencia = rec
+		err = validarRecorrencia(e)
+	}
+	if err != nil {
+		e.Recorrencia = nil
+		return e, fmt.Sprintf("%q: repetição não suportada (%v), importado só na primeira data", e.Titulo, err), true
+	}
+	return e, aviso, true
+}
+
+// inicioAlarme é quantos minutos depois do DTSTART o lembrete de antecedência
+// zero aparece: na hora do evento, ou às 9h num evento de dia inteiro
+func inicioAlarme(diaInteiro bool) int {
+	if diaInteiro {
+		return horaDiaInteiro * 60
+	}
+	return 0
+}
+
+// lembretesDoICS converte os TRIGGER dos VALARMs em minutos de antecedência.
+// Só os relativos ao início e dentro da antecedência aceita viram
+// lembretes; retorna também quantos foram ignorados.
+func lembretesDoICS(alarmes []propriedadeICS, diaInteiro bool) ([]int, int) {
+	lembretes := []int{}
+	ignorados := 0
+	for _, p := range alarmes {
+		deslocamento, ok := lerDuracaoICS(p.valor)
+		m := inicioAlarme(diaInteiro) - deslocamento
+		if !ok || p.params["VALUE"] == "DATE-TIME" || strings.EqualFold(p.params["RELATED"], "END") ||
+			m < 0 || m > maxAntecedencia {
+			ignorados++
+			continue
+		}
+		if !slices.Contains(lembretes, m) {
+			lembretes = append(lembretes, m)
+		}
+	}
+	return lembretes, ignorados
+}
+
+// duracaoICS formata minutos (negativo = antes) como DURATION da RFC 5545
+func duracaoICS(minutos int) string {
+	if minutos < 0 {
+		return fmt.Sprintf("-PT%dM", -minutos)
+	}
+	return fmt.Sprintf("PT%dM", minutos)
+}
+
+// duracaoICSRegex aceita DURATION como -P1W, -PT15M ou P1DT2H30M
+var duracaoICSRegex = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// lerDuracaoICS converte uma DURATION em minutos (os segundos são descartados)
+func lerDuracaoICS(valor string) (int, bool) {
+	partes := duracaoICSRegex.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(valor)))
+	if partes == nil || strings.Join(partes[2:], "") == "" {
+		return 0, false
+	}
+	numero := func(i int) int {
+		n, _ := strconv.Atoi(partes[i])
+		return n
+	}
+	minutos := numero(2)*7*24*60 + numero(3)*24*60 + numero(4)*60 + numero(5)
+	if partes[1] == "-" {
+		minutos = -minutos
+	}
+	return minutos, true
+}
+
+// lerDataICS interpreta DTSTART/EXDATE: data (dia inteiro), hora UTC (Z),
+// hora com TZID ou hora flutuante. Horas com fuso viram horário local.
+func lerDataICS(p propriedadeICS) (time.Time, bool, error) {
+	if p.params["VALUE"] == "DATE" || len(p.valor) == 8 {
+		d, err := time.Parse("20060102", p.valor)
+		return d, true, err
+	}
+	if strings.HasSuffix(p.valor, "Z") {
+		t, err := time.Parse("20060102T150405Z", p.valor)
+		return t.Local(), false, err
+	}
+	if tzid := p.params["TZID"]; tzid != "" {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			t, err := time.ParseInLocation("20060102T150405", p.valor, loc)
+			return t.Local(), false, err
+		}
+	}
+	t, err := time.Parse("20060102T150405", p.valor)
+	return t, false, err
+}
+
+var desescapeICS = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func desescaparTextoICS(s string) string {
+	return desescapeICS.Replace(s)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+func TestICSIdaEVolta(t *testing.T) {
+	criado := "2026-01-05T12:00:00.000Z"
+	eventos := []Evento{
+		{
+			UID: "reuniao@exemplo", Titulo: "Reunião; semanal, com vírgula", Descricao: "linha 1\nlinha 2",
+			Data: "2026-03-02", Hora: "09:30", Cor: "#ff0000", CreatedAt: criado, Lembretes: []int{15, 0},
+			Recorrencia: &Recorrencia{Frequencia: "semanal", DiasSemana: []string{"MO", "WE"},
+				Ate: "2026-06-30", Excecoes: []string{"2026-03-04"}},
+		},
+		{
+			UID: "aluguel@exemplo", Titulo: "Aluguel", Data: "2026-01-31", Cor: CorEventoPadrao, CreatedAt: criado,
+			Lembretes:   []int{0, 24 * 60},
+			Recorrencia: &Recorrencia{Frequencia: "mensal", Intervalo: 2, Ate: "2026-12-31"},
+		},
+		{
+			UID: "fim@exemplo", Titulo: "Última sexta", Data: "2026-01-30", Hora: "18:00", Cor: CorEventoPadrao, CreatedAt: criado,
+			Recorrencia: &Recorrencia{Frequencia: "mensal", DiasSemana: []string{"-1FR"}, Vezes: 5},
+		},
+		{UID: "unico@exemplo", Titulo: "Dentista", Data: "2026-03-10", Hora: "14:30", Cor: CorEventoPadrao, CreatedAt: criado},
+	}
+
+	ics := gerarICS(eventos, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	texto := string(ics)
+	for _, esperado := range []string{
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20260630T235959\r\n",
+		"RRULE:FREQ=MONTHLY;INTERVAL=2;UNTIL=20261231\r\n",
+		"EXDATE:20260304T093000\r\n",
+		"TRIGGER:-PT15M\r\n",
+		"TRIGGER:PT540M\r\n",  // dia inteiro: às 9h do dia
+		"TRIGGER:-PT900M\r\n", // dia inteiro: às 9h do dia anterior
+	} {
+		if !strings.Contains(texto, esperado) {
+			t.Errorf("faltou %q no .ics:\n%s", esperado, texto)
+		}
+	}
+
+	lidos, avisos, err := lerICS(bytes.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(avisos) > 0 {
+		t.Errorf("avisos: %v", avisos)
+	}
+	if !reflect.DeepEqual(lidos, eventos) {
+		t.Errorf("lido:\n%+v\nesperado:\n%+v", lidos, eventos)
+	}
+}
+
+func TestLerRRuleUntilComHora(t *testing.T) {
+	casos := []struct {
+		regra string
+		ate   string
+	}{
+		{"FREQ=DAILY;UNTIL=20260630", "2026-06-30"},
+		{"FREQ=DAILY;UNTIL=20260630T235959", "2026-06-30"},
+		{"FREQ=DAILY;UNTIL=20260630T120000Z", "2026-06-30"},
+	}
+	for _, c := range casos {
+		r, err := LerRRule(c.regra)
+		if err != nil {
+			t.Errorf("%s: %v", c.regra, err)
+			continue
+		}
+		if r.Ate != c.ate {
+			t.Errorf("%s: Ate = %q, esperado %q", c.regra, r.Ate, c.ate)
+		}
+	}
+}
+
+func TestImportarArquivoICSMesmoUID(t *testing.T) {
+	dir := t.TempDir()
+	h := NewCalendarioHandler(dir, storage.NewBackendArquivos(filepath.Join(dir, "init")))
+	evento := Evento{ID: "evento-1", Titulo: "Dentista", Data: "2026-03-10", Hora: "14:30",
+		Cor: CorEventoPadrao, CreatedAt: "2026-01-05T12:00:00.000Z", Lembretes: []int{30}}
+	if err := h.AdicionarEvento(evento); err != nil {
+		t.Fatal(err)
+	}
+	exportado := filepath.Join(dir, "exportado.ics")
+	if err := h.ExportarArquivoICS(exportado); err != nil {
+		t.Fatal(err)
+	}
+	uid := evento.ID + sufixoUID
+
+	// Arquivos com o mesmo UID: o próprio exportado, um sem alarmes e um
+	// com um alarme diferente
+	semAlarme := filepath.Join(dir, "sem-alarme.ics")
+	comAlarme := filepath.Join(dir, "com-alarme.ics")
+	vevent := func(titulo, extra string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:" + uid +
+			"\r\nDTSTART:20260310T150000\r\nSUMMARY:" + titulo + "\r\n" + extra +
+			"END:VEVENT\r\nEND:VCALENDAR\r\n"
+	}
+	if err := os.WriteFile(semAlarme, []byte(vevent("Dentista (remarcado)", "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	alarme := "BEGIN:VALARM\r\nACTION:DISPLAY\r\nTRIGGER:-PT1H\r\nEND:VALARM\r\n"
+	if err := os.WriteFile(comAlarme, []byte(vevent("Dentista", alarme)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	casos := []struct {
+		arquivo   string
+		titulo    string
+		hora      string
+		lembretes []int
+	}{
+		{exportado, "Dentista", "14:30", []int{30}},
+		{semAlarme, "Dentista (remarcado)", "15:00", []int{30}},
+		{comAlarme, "Dentista", "15:00", []int{60}},
+	}
+	for _, c := range casos {
+		resultado, err := h.ImportarArquivoICS(c.arquivo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resultado.Novos != 0 || resultado.Atualizados != 1 {
+			t.Errorf("%s: resultado = %+v", filepath.Base(c.arquivo), resultado)
+		}
+		eventos, err := h.CarregarEventos()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(eventos) != 1 {
+			t.Fatalf("%s: %d eventos, esperado 1 (UID duplicado)", filepath.Base(c.arquivo), len(eventos))
+		}
+		e := eventos[0]
+		if e.ID != evento.ID || e.CreatedAt != evento.CreatedAt || e.UID != "" {
+			t.Errorf("%s: identidade do evento mudou: %+v", filepath.Base(c.arquivo), e)
+		}
+		if e.Titulo != c.titulo || e.Hora != c.hora || !reflect.DeepEqual(e.Lembretes, c.lembretes) {
+			t.Errorf("%s: evento = %+v", filepath.Base(c.arquivo), e)
+		}
+	}
+}
+
+func TestLerDuracaoICS(t *testing.T) {
+	casos := []struct {
+		valor   string
+		minutos int
+		ok      bool
+	}{
+		{"-PT15M", -15, true},
+		{"PT0S", 0, true},
+		{"-P1D", -24 * 60, true},
+		{"-P1DT2H30M", -(24*60 + 150), true},
+		{"-P2W", -2 * 7 * 24 * 60, true},
+		{"+PT1H", 60, true},
+		{"P", 0, false},
+		{"-PT", 0, false},
+		{"15M", 0, false},
+	}
+	for _, c := range casos {
+		minutos, ok := lerDuracaoICS(c.valor)
+		if ok != c.ok || ok && minutos != c.minutos {
+			t.Errorf("%s = %d, %v; esperado %d, %v", c.valor, minutos, ok, c.minutos, c.ok)
+		}
+	}
+}
@@ -7,15 +7,18 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/google/uuid"
+	"github.com/user/tdah-organizer/internal/storage"
 )
 
 // IdeiasHandler gerencia as operações do módulo de ideias
 type IdeiasHandler struct {
 	ctx       context.Context
+	mu        sync.RWMutex // protege assetsDir contra troca de workspace
 	assetsDir string
-	dataFile  string
+	store     *storage.Store[CanvasData]
 }
 
 // NodeData representa um nó no canvas
@@ -48,13 +51,42 @@ type CanvasData struct {
 	Edges []EdgeData `json:"edges"`
 }
 
+// arquivoIdeias é o documento do canvas de ideias
+const arquivoIdeias = "ideias_data.json"
+
+// esquemaCanvas registra as migrações de ideias_data.json
+var esquemaCanvas = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		migrarCanvasParentId, // v1: envelope + parentId como campo canônico
+	},
+}
+
+// migrarCanvasParentId preenche parentId em nós antigos que só tinham parent
+func migrarCanvasParentId(dados json.RawMessage) (json.RawMessage, error) {
+	var canvas struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges json.RawMessage          `json:"edges"`
+	}
+	if err := json.Unmarshal(dados, &canvas); err != nil {
+		return nil, err
+	}
+	for _, node := range canvas.Nodes {
+		parent, _ := node["parent"].(string)
+		parentId, _ := node["parentId"].(string)
+		if parentId == "" && parent != "" {
+			node["parentId"] = parent
+		}
+	}
+	return json.Marshal(canvas)
+}
+
 // NewIdeiasHandler cria um novo handler
-func NewIdeiasHandler(assetsDir string) *IdeiasHandler {
-	initDir := filepath.Join(assetsDir, "init")
-	return &IdeiasHandler{
+func NewIdeiasHandler(assetsDir string, backend storage.Backend) *IdeiasHandler {
+	h := &IdeiasHandler{
 		assetsDir: assetsDir,
-		dataFile:  filepath.Join(initDir, "ideias_data.json"),
 	}
+	h.store = storage.NewStore(backend, arquivoIdeias, esquemaCanvas, h.canvasVazio, h.normalizarCampos)
+	return h
 }
 
 // Startup é chamado quando o app inicia
@@ -62,69 +94,100 @@ func (h *IdeiasHandler) Startup(ctx context.Context) {
 	h.ctx = ctx
 }
 
+// Apontar passa a usar os dados e as imagens de outro workspace
+func (h *IdeiasHandler) Apontar(assetsDir string, backend storage.Backend) {
+	h.mu.Lock()
+	h.assetsDir = assetsDir
+	h.mu.Unlock()
+	h.store.Apontar(backend)
+}
+
+// pastaImagens retorna a pasta img do workspace ativo
+func (h *IdeiasHandler) pastaImagens() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return filepath.Join(h.assetsDir, "img")
+}
+
 // SalvarCanvas salva o estado atual do canvas
 func (h *IdeiasHandler) SalvarCanvas(nodes []NodeData, edges []EdgeData) error {
-	// Normalizar campos parent/parentId
-	for i := range nodes {
-		node := &nodes[i]
-		if node.ParentId == "" && node.Parent != "" {
-			node.ParentId = node.Parent
-		}
-	}
 	data := CanvasData{
 		Nodes: nodes,
 		Edges: edges,
 	}
-
-	return h.salvarCanvasData(data)
+	return h.store.Salvar(data)
 }
 
 // CarregarCanvas carrega o estado salvo do canvas
 func (h *IdeiasHandler) CarregarCanvas() (CanvasData, error) {
-	var data CanvasData
+	return h.store.Carregar()
+}
 
-	// Garantir que a pasta init existe
-	initDir := filepath.Join(h.assetsDir, "init")
-	if err := os.MkdirAll(initDir, 0755); err != nil {
-		return CanvasData{Nodes: []NodeData{}, Edges: []EdgeData{}}, err
-	}
+// AdicionarNo inclui um nó no canvas
+func (h *IdeiasHandler) AdicionarNo(node NodeData) error {
+	return h.store.Atualizar(func(data *CanvasData) error {
+		for _, n := range data.Nodes {
+			if n.ID == node.ID {
+				return fmt.Errorf("já existe um nó com o ID %s", node.ID)
+			}
+		}
+		data.Nodes = append(data.Nodes, node)
+		return nil
+	})
+}
 
-	// Verificar se arquivo existe
-	if _, err := os.Stat(h.dataFile); os.IsNotExist(err) {
-		// Arquivo não existe - retornar dados vazios (app começa do zero)
-		return CanvasData{Nodes: []NodeData{}, Edges: []EdgeData{}}, nil
-	}
+// AtualizarNo substitui um nó existente do canvas
+func (h *IdeiasHandler) AtualizarNo(node NodeData) error {
+	return h.store.Atualizar(func(data *CanvasData) error {
+		for i, n := range data.Nodes {
+			if n.ID == node.ID {
+				data.Nodes[i] = node
+				return nil
+			}
+		}
+		return fmt.Errorf("nó não encontrado: %s", node.ID)
+	})
+}
 
-	// Carregar dados
-	jsonData, err := os.ReadFile(h.dataFile)
-	if err != nil {
-		return CanvasData{Nodes: []NodeData{}, Edges: []EdgeData{}}, err
-	}
+// DeletarNo remove um nó, suas conexões e o vínculo dos nós que estavam
+// dentro dele (quando ele é um grupo)
+func (h *IdeiasHandler) DeletarNo(id string) error {
+	return h.store.Atualizar(func(data *CanvasData) error {
+		nodes := []NodeData{}
+		for _, n := range data.Nodes {
+			if n.ID == id {
+				continue
+			}
+			if n.ParentId == id {
+				n.Parent, n.ParentId = "", ""
+			}
+			nodes = append(nodes, n)
+		}
+		edges := []EdgeData{}
+		for _, e := range data.Edges {
+			if e.Source != id && e.Target != id {
+				edges = append(edges, e)
+			}
+		}
+		data.Nodes, data.Edges = nodes, edges
+		return nil
+	})
+}
 
-	err = json.Unmarshal(jsonData, &data)
-	// Garantir arrays não nulos
+// canvasVazio retorna um canvas sem nós nem conexões
+func (h *IdeiasHandler) canvasVazio() CanvasData {
+	return CanvasData{Nodes: []NodeData{}, Edges: []EdgeData{}}
+}
+
+// normalizarCampos garante arrays não nulos e mantém parent/parentId iguais,
+// já que o frontend ainda lê os dois campos
+func (h *IdeiasHandler) normalizarCampos(data *CanvasData) {
 	if data.Nodes == nil {
 		data.Nodes = []NodeData{}
 	}
 	if data.Edges == nil {
 		data.Edges = []EdgeData{}
 	}
-	// Normalizar campos parent/parentId
-	h.normalizarCampos(&data)
-	return data, err
-}
-
-// salvarCanvasData salva dados do canvas (usado internamente)
-func (h *IdeiasHandler) salvarCanvasData(data CanvasData) error {
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(h.dataFile, jsonData, 0644)
-}
-
-// normalizarCampos normaliza campos parent/parentId
-func (h *IdeiasHandler) normalizarCampos(data *CanvasData) {
 	for i := range data.Nodes {
 		node := &data.Nodes[i]
 		if node.Parent == "" && node.ParentId != "" {
@@ -139,7 +202,7 @@ func (h *IdeiasHandler) normalizarCampos(data *CanvasData) {
 // UploadImagem salva uma imagem na pasta assets/img
 func (h *IdeiasHandler) UploadImagem(filename string, data []byte, nodeID string) (string, error) {
 	// Criar pasta img se não existir
-	imgDir := filepath.Join(h.assetsDir, "img")
+	imgDir := h.pastaImagens()
 	if err := os.MkdirAll(imgDir, 0755); err != nil {
 		return "", err
 	}
@@ -150,7 +213,7 @@ func (h *IdeiasHandler) UploadImagem(filename string, data []byte, nodeID string
 	filePath := filepath.Join(imgDir, newFilename)
 
 	// Salvar arquivo
-	err := os.WriteFile(filePath, data, 0644)
+	err := storage.EscreverAtomico(filePath, data, 0644)
 	if err != nil {
 		return "", err
 	}
@@ -160,31 +223,23 @@ func (h *IdeiasHandler) UploadImagem(filename string, data []byte, nodeID string
 
 // DeletarImagem remove uma imagem da pasta assets/img
 func (h *IdeiasHandler) DeletarImagem(filename string) error {
-	imgDir := filepath.Join(h.assetsDir, "img")
+	imgDir := h.pastaImagens()
 	filePath := filepath.Join(imgDir, filename)
 	return os.Remove(filePath)
 }
 
 // LimparImagensOrfas remove imagens não referenciadas
 func (h *IdeiasHandler) LimparImagensOrfas(nodeIDs []string) error {
-	// Obter lista de imagens referenciadas
-	imagensReferenciadas := make(map[string]bool)
-
 	data, err := h.CarregarCanvas()
 	if err != nil {
 		return err
 	}
 
-	for _, node := range data.Nodes {
-		if node.Type == "image" && node.Data["imageFile"] != nil {
-			if filename, ok := node.Data["imageFile"].(string); ok {
-				imagensReferenciadas[filename] = true
-			}
-		}
-	}
+	// Obter lista de imagens referenciadas
+	referenciadas := imagensReferenciadas(data)
 
 	// Listar arquivos na pasta assets/img
-	imgDir := filepath.Join(h.assetsDir, "img")
+	imgDir := h.pastaImagens()
 	entries, err := os.ReadDir(imgDir)
 	if err != nil {
 		// Se a pasta não existe, não há o que limpar
@@ -201,7 +256,7 @@ func (h *IdeiasHandler) LimparImagensOrfas(nodeIDs []string) error {
 		// Verificar se é uma imagem
 		ext := strings.ToLower(filepath.Ext(name))
 		if ext == ".jpg" || ext == ".jpeg" || ext == ".png" || ext == ".gif" || ext == ".webp" {
-			if !imagensReferenciadas[name] {
+			if !referenciadas[name] {
 				// Verificar se o arquivo pertence a um nó existente
 				isOrfa := true
 				for _, nodeID := range nodeIDs {
@@ -220,3 +275,16 @@ func (h *IdeiasHandler) LimparImagensOrfas(nodeIDs []string) error {
 
 	return nil
 }
+
+// imagensReferenciadas retorna os arquivos de imagem usados pelos nós do canvas
+func imagensReferenciadas(data CanvasData) map[string]bool {
+	imagens := make(map[string]bool)
+	for _, node := range data.Nodes {
+		if node.Type == "image" && node.Data["imageFile"] != nil {
+			if filename, ok := node.Data["imageFile"].(string); ok {
+				imagens[filename] = true
+			}
+		}
+	}
+	return imagens
+}
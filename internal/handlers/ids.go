@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ultimoID guarda o último instante usado por NovoID, para que dois itens
+// criados no mesmo milissegundo não recebam o mesmo ID
+var ultimoID atomic.Int64
+
+// NovoID gera o ID de um item criado fora do frontend, no mesmo formato que
+// ele usa (ex: tarefa_1700000000000)
+func NovoID(prefixo string) string {
+	for {
+		anterior := ultimoID.Load()
+		agora := time.Now().UnixMilli()
+		if agora <= anterior {
+			agora = anterior + 1
+		}
+		if ultimoID.CompareAndSwap(anterior, agora) {
+			return fmt.Sprintf("%s_%d", prefixo, agora)
+		}
+	}
+}
+
+// CriadoAgora retorna o horário atual no formato de createdAt usado pelo
+// frontend (toISOString)
+func CriadoAgora() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Lembrete é o aviso de uma ocorrência de evento, disparado Minutos antes
+// do horário dela. Eventos sem hora são lembrados a partir das 9h do dia.
+type Lembrete struct {
+	ID       string `json:"id"` // evento|data|minutos
+	EventoID string `json:"eventoId"`
+	Titulo   string `json:"titulo"`
+	Data     string `json:"data"` // Data da ocorrência (YYYY-MM-DD)
+	Hora     string `json:"hora"`
+	Cor      string `json:"cor"`
+	Minutos  int    `json:"minutos"` // Antecedência
+}
+
+// DisparoLembrete registra que um lembrete já foi mostrado, para que ele não
+// se repita ao reabrir o app, e até quando foi adiado
+type DisparoLembrete struct {
+	ID          string `json:"id"`
+	Data        string `json:"data"`        // Data da ocorrência, para descartar os antigos
+	DisparadoEm string `json:"disparadoEm"` // RFC 3339
+	AdiadoAte   string `json:"adiadoAte,omitempty"`
+}
+
+// ErrLembreteInvalido indica uma antecedência de lembrete fora do permitido
+var ErrLembreteInvalido = errors.New("lembrete inválido")
+
+// arquivoLembretes guarda os lembretes já disparados do workspace. Como o
+// das assinaturas, o nome antigo não tinha o sufixo _data.json (ver
+// renomearAntigos).
+const (
+	arquivoLembretes       = "calendario_lembretes_data.json"
+	arquivoLembretesAntigo = "calendario_lembretes.json"
+)
+
+// esquemaLembretes registra as migrações de calendario_lembretes_data.json
+var esquemaLembretes = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+const (
+	maxAntecedencia      = 4 * 7 * 24 * 60 // minutos: até 4 semanas antes
+	horaDiaInteiro       = 9               // eventos sem hora são lembrados às 9h
+	verificacaoLembretes = 30 * time.Second
+	// Lembretes perdidos com o app fechado só aparecem se o evento começou
+	// há menos que isso; os mais antigos são ignorados. Um lembrete adiado
+	// aparece quando o adiamento acabar, mesmo depois desse limite.
+	atrasoMaximoLembrete = time.Hour
+	// Disparos de ocorrências mais antigas que isso são descartados
+	retencaoDisparos = 7 * 24 * time.Hour
+)
+
+// AdiarLembrete mostra o lembrete de novo daqui a alguns minutos
+func (h *CalendarioHandler) AdiarLembrete(id string, minutos int) error {
+	return h.adiarLembrete(id, minutos, time.Now())
+}
+
+func (h *CalendarioHandler) adiarLembrete(id string, minutos int, agora time.Time) error {
+	if minutos <= 0 {
+		return fmt.Errorf("informe por quantos minutos adiar")
+	}
+	ate := agora.Add(time.Duration(minutos) * time.Minute).Format(time.RFC3339)
+	return h.lembretes.Atualizar(func(disparos *[]DisparoLembrete) error {
+		for i := range *disparos {
+			if (*disparos)[i].ID == id {
+				(*disparos)[i].AdiadoAte = ate
+				return nil
+			}
+		}
+		return fmt.Errorf("lembrete não encontrado: %s", id)
+	})
+}
+
+// ProximosLembretes retorna os lembretes que ainda vão disparar nos
+// próximos dias, em ordem de horário
+func (h *CalendarioHandler) ProximosLembretes(dias int) ([]Lembrete, error) {
+	if dias <= 0 || dias > maxDiasConsulta {
+		return nil, fmt.Errorf("informe de 1 a %d dias", maxDiasConsulta)
+	}
+	eventos, err := h.store.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	disparos, err := h.lembretes.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	agora := time.Now()
+	limite := agora.AddDate(0, 0, dias)
+	indice := indiceDisparos(disparos)
+	proximos := []Lembrete{}
+	momentos := map[string]time.Time{}
+	for _, l := range lembretesEntre(eventos, agora.AddDate(0, 0, -1), limite) {
+		momento := momentoLembrete(l, agora.Location())
+		if d, ok := indice[l.ID]; ok {
+			adiado, err := time.Parse(time.RFC3339, d.AdiadoAte)
+			if err != nil {
+				continue
+			}
+			momento = adiado
+		}
+		if momento.Before(agora) || momento.After(limite) {
+			continue
+		}
+		momentos[l.ID] = momento
+		proximos = append(proximos, l)
+	}
+	sort.SliceStable(proximos, func(i, j int) bool {
+		return momentos[proximos[i].ID].Before(momentos[proximos[j].ID])
+	})
+	return proximos, nil
+}
+
+// validarLembretes confere as antecedências de um evento antes de gravá-lo
+func validarLembretes(e Evento) error {
+	for _, m := range e.Lembretes {
+		if m < 0 || m > maxAntecedencia {
+			return fmt.Errorf("%w: a antecedência vai de 0 a %d minutos (4 semanas)", ErrLembreteInvalido, maxAntecedencia)
+		}
+	}
+	return nil
+}
+
+// --- Funções internas ---
+
+// notificarSistema mostra a notificação do sistema (trocada nos testes)
+var notificarSistema = notificar
+
+// iniciarLembretes confere os lembretes a cada 30 segundos, até Shutdown ou
+// o fim do contexto do Wails
+func (h *CalendarioHandler) iniciarLembretes(ctx context.Context) {
+	h.avisador.Add(1)
+	go func() {
+		defer h.avisador.Done()
+		ticker := time.NewTicker(verificacaoLembretes)
+		defer ticker.Stop()
+		for {
+			h.dispararLembretes(time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.parar:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// dispararLembretes mostra os lembretes que venceram (ou cujo adiamento
+// acabou) e os retorna. Eles são gravados como disparados antes do aviso: se
+// a gravação falhar, nada é mostrado, em vez de repetir o aviso a cada
+// verificação.
+func (h *CalendarioHandler) dispararLembretes(agora time.Time) []Lembrete {
+	eventos, err := h.store.Carregar()
+	if err != nil {
+		return nil
+	}
+	disparos, err := h.lembretes.Carregar()
+	if err != nil {
+		return nil
+	}
+	indice := indiceDisparos(disparos)
+
+	// Começa um dia antes para pegar os adiados e os eventos que acabaram
+	// de começar
+	devidos := []Lembrete{}
+	for _, l := range lembretesEntre(eventos, agora.AddDate(0, 0, -1), agora) {
+		if d, ok := indice[l.ID]; ok {
+			adiado, err := time.Parse(time.RFC3339, d.AdiadoAte)
+			if err != nil || agora.Before(adiado) {
+				continue
+			}
+		} else if agora.Before(momentoLembrete(l, agora.Location())) ||
+			agora.After(momentoOcorrencia(l.Data, l.Hora, agora.Location()).Add(atrasoMaximoLembrete)) {
+			continue
+		}
+		devidos = append(devidos, l)
+	}
+	if len(devidos) == 0 {
+		return nil
+	}
+
+	err = h.lembretes.Atualizar(func(disparos *[]DisparoLembrete) error {
+		novos := map[string]DisparoLembrete{}
+		for _, l := range devidos {
+			novos[l.ID] = DisparoLembrete{ID: l.ID, Data: l.Data, DisparadoEm: agora.Format(time.RFC3339)}
+		}
+		// Regrava mantendo só os disparos recentes
+		corte := agora.Add(-retencaoDisparos).Format(time.DateOnly)
+		restantes := []DisparoLembrete{}
+		for _, d := range *disparos {
+			if novo, ok := novos[d.ID]; ok {
+				d = novo
+				delete(novos, d.ID)
+			}
+			if d.Data >= corte {
+				restantes = append(restantes, d)
+			}
+		}
+		for _, l := range devidos {
+			if novo, ok := novos[l.ID]; ok {
+				restantes = append(restantes, novo)
+			}
+		}
+		*disparos = restantes
+		return nil
+	})
+	if err != nil {
+		if h.ctx != nil {
+			runtime.LogError(h.ctx, "Erro ao gravar lembretes: "+err.Error())
+		}
+		return nil
+	}
+
+	for _, l := range devidos {
+		if h.ctx != nil {
+			runtime.EventsEmit(h.ctx, "calendario:lembrete", l)
+		}
+		if err := notificarSistema(l.Titulo, descreverLembrete(l, agora)); err != nil && h.ctx != nil {
+			runtime.LogError(h.ctx, "Erro na notificação do sistema: "+err.Error())
+		}
+	}
+	return devidos
+}
+
+// lembretesEntre lista os lembretes das ocorrências cujos eventos começam
+// entre de e o maior horizonte de antecedência depois de ate
+func lembretesEntre(eventos []Evento, de, ate time.Time) []Lembrete {
+	lembretes := []Lembrete{}
+	inicio, _ := lerData(de.Format(time.DateOnly))
+	for _, e := range eventos {
+		maior := -1
+		for _, m := range e.Lembretes {
+			maior = max(maior, m)
+		}
+		if maior < 0 {
+			continue
+		}
+		fim, _ := lerData(ate.Add(time.Duration(maior) * time.Minute).Format(time.DateOnly))
+		for _, d := range ocorrenciasEntre(e, inicio, fim) {
+			for _, m := range e.Lembretes {
+				data := d.Format(time.DateOnly)
+				lembretes = append(lembretes, Lembrete{
+					ID:       fmt.Sprintf("%s|%s|%d", e.ID, data, m),
+					EventoID: e.ID,
+					Titulo:   e.Titulo,
+					Data:     data,
+					Hora:     e.Hora,
+					Cor:      e.Cor,
+					Minutos:  m,
+				})
+			}
+		}
+	}
+	return lembretes
+}
+
+// momentoOcorrencia é o início da ocorrência no fuso local
+func momentoOcorrencia(data, hora string, loc *time.Location) time.Time {
+	d, _ := lerData(data)
+	h, m := horaDiaInteiro, 0
+	if t, err := time.Parse("15:04", hora); err == nil {
+		h, m = t.Hour(), t.Minute()
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), h, m, 0, 0, loc)
+}
+
+// momentoLembrete é quando o lembrete deve aparecer
+func momentoLembrete(l Lembrete, loc *time.Location) time.Time {
+	return momentoOcorrencia(l.Data, l.Hora, loc).Add(-time.Duration(l.Minutos) * time.Minute)
+}
+
+func indiceDisparos(disparos []DisparoLembrete) map[string]DisparoLembrete {
+	indice := make(map[string]DisparoLembrete, len(disparos))
+	for _, d := range disparos {
+		indice[d.ID] = d
+	}
+	return indice
+}
+
+// descreverLembrete monta o texto da notificação: "Hoje às 14:30", "Amanhã"...
+func descreverLembrete(l Lembrete, agora time.Time) string {
+	hoje := agora.Format(time.DateOnly)
+	amanha := agora.AddDate(0, 0, 1).Format(time.DateOnly)
+	var dia string
+	switch l.Data {
+	case hoje:
+		dia = "Hoje"
+	case amanha:
+		dia = "Amanhã"
+	default:
+		d, _ := lerData(l.Data)
+		dia = d.Format("02/01/2006")
+	}
+	if l.Hora == "" {
+		return dia + " (dia inteiro)"
+	}
+	return dia + " às " + l.Hora
+}
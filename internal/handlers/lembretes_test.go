@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+func TestDispararLembretes(t *testing.T) {
+	notificarSistema = func(titulo, corpo string) error { return nil }
+	t.Cleanup(func() { notificarSistema = notificar })
+
+	const (
+		consulta  = "dentista|2026-03-10|15"
+		noHorario = "dentista|2026-03-10|0"
+	)
+	// Passos em sequência sobre os mesmos dados: "reabrir" cria outro
+	// handler, como ao abrir o app de novo
+	type passo struct {
+		hora     string // em 10/03/2026, no fuso local
+		acao     string // "verificar", "adiar" ou "reabrir"
+		id       string
+		minutos  int
+		esperado []string
+	}
+	casos := []struct {
+		nome   string
+		passos []passo
+	}{
+		{
+			nome: "dispara na hora e não repete, nem ao reabrir",
+			passos: []passo{
+				{hora: "14:44", acao: "verificar"},
+				{hora: "14:45", acao: "verificar", esperado: []string{consulta}},
+				{hora: "14:46", acao: "verificar"},
+				{hora: "15:00", acao: "verificar", esperado: []string{noHorario}},
+				{acao: "reabrir"},
+				{hora: "15:01", acao: "verificar"},
+			},
+		},
+		{
+			nome: "adiado volta quando o adiamento acaba, uma vez",
+			passos: []passo{
+				{hora: "14:45", acao: "verificar", esperado: []string{consulta}},
+				{hora: "14:46", acao: "adiar", id: consulta, minutos: 10},
+				{hora: "14:55", acao: "verificar"},
+				{acao: "reabrir"},
+				{hora: "14:56", acao: "verificar", esperado: []string{consulta}},
+				{hora: "14:57", acao: "verificar"},
+			},
+		},
+		{
+			nome: "perdidos com o app fechado só até 1h depois do início",
+			passos: []passo{
+				{hora: "15:59", acao: "verificar", esperado: []string{consulta, noHorario}},
+			},
+		},
+		{
+			nome: "perdidos há mais de 1h são ignorados",
+			passos: []passo{
+				{hora: "16:01", acao: "verificar"},
+				{hora: "16:02", acao: "verificar"},
+			},
+		},
+		{
+			nome: "adiado para depois do limite de atraso ainda aparece",
+			passos: []passo{
+				{hora: "15:00", acao: "verificar", esperado: []string{consulta, noHorario}},
+				{hora: "15:00", acao: "adiar", id: noHorario, minutos: 90},
+				{hora: "16:29", acao: "verificar"},
+				{hora: "16:30", acao: "verificar", esperado: []string{noHorario}},
+				{hora: "16:31", acao: "verificar"},
+			},
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			dir := t.TempDir()
+			backend := storage.NewBackendArquivos(filepath.Join(dir, "init"))
+			h := NewCalendarioHandler(dir, backend)
+			evento := Evento{ID: "dentista", Titulo: "Dentista", Data: "2026-03-10", Hora: "15:00",
+				Cor: CorEventoPadrao, Lembretes: []int{15, 0}}
+			if err := h.AdicionarEvento(evento); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, p := range c.passos {
+				var agora time.Time
+				if p.hora != "" {
+					hora, _ := time.Parse("15:04", p.hora)
+					agora = time.Date(2026, 3, 10, hora.Hour(), hora.Minute(), 0, 0, time.Local)
+				}
+				switch p.acao {
+				case "reabrir":
+					h = NewCalendarioHandler(dir, backend)
+				case "adiar":
+					if err := h.adiarLembrete(p.id, p.minutos, agora); err != nil {
+						t.Fatalf("passo %d: %v", i, err)
+					}
+				case "verificar":
+					disparados := []string{}
+					for _, l := range h.dispararLembretes(agora) {
+						disparados = append(disparados, l.ID)
+					}
+					slices.Sort(disparados)
+					esperado := slices.Clone(p.esperado)
+					slices.Sort(esperado)
+					if !slices.Equal(disparados, esperado) {
+						t.Errorf("passo %d (%s): disparou %v, esperado %v", i, p.hora, disparados, esperado)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAdiarLembreteDesconhecido(t *testing.T) {
+	dir := t.TempDir()
+	h := NewCalendarioHandler(dir, storage.NewBackendArquivos(filepath.Join(dir, "init")))
+	if err := h.AdiarLembrete("nada|2026-03-10|0", 5); err == nil {
+		t.Error("adiar um lembrete que não disparou deveria falhar")
+	}
+	if err := h.AdiarLembrete("nada|2026-03-10|0", 0); err == nil {
+		t.Error("adiar por 0 minutos deveria falhar")
+	}
+}
@@ -2,16 +2,15 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
-	"os"
-	"path/filepath"
+
+	"github.com/user/tdah-organizer/internal/storage"
 )
 
 // LinksHandler gerencia as operações do módulo de links
 type LinksHandler struct {
 	ctx       context.Context
 	assetsDir string
-	dataFile  string
+	store     *storage.Store[[]Link]
 }
 
 // Link representa um link salvo
@@ -23,12 +22,18 @@ type Link struct {
 	CreatedAt   string `json:"createdAt"`
 }
 
+// esquemaLinks registra as migrações de links_data.json
+var esquemaLinks = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: dados passam a ser gravados em envelope
+	},
+}
+
 // NewLinksHandler cria um novo handler
-func NewLinksHandler(assetsDir string) *LinksHandler {
-	initDir := filepath.Join(assetsDir, "init")
+func NewLinksHandler(assetsDir string, backend storage.Backend) *LinksHandler {
 	return &LinksHandler{
 		assetsDir: assetsDir,
-		dataFile:  filepath.Join(initDir, "links_data.json"),
+		store:     storage.NewLista[Link](backend, "links_data.json", esquemaLinks),
 	}
 }
 
@@ -37,88 +42,53 @@ func (h *LinksHandler) Startup(ctx context.Context) {
 	h.ctx = ctx
 }
 
+// Apontar passa a usar os dados de outro workspace
+func (h *LinksHandler) Apontar(assetsDir string, backend storage.Backend) {
+	h.assetsDir = assetsDir
+	h.store.Apontar(backend)
+}
+
 // SalvarLinks salva a lista de links
 func (h *LinksHandler) SalvarLinks(links []Link) error {
-	return h.salvarLinksInterno(links)
+	return h.store.Salvar(links)
 }
 
 // CarregarLinks carrega a lista de links
 func (h *LinksHandler) CarregarLinks() ([]Link, error) {
-	// Garantir que a pasta init existe
-	initDir := filepath.Join(h.assetsDir, "init")
-	if err := os.MkdirAll(initDir, 0755); err != nil {
-		return []Link{}, err
-	}
-
-	// Verificar se arquivo existe
-	if _, err := os.Stat(h.dataFile); os.IsNotExist(err) {
-		// Arquivo não existe - retornar lista vazia (app começa do zero)
-		return []Link{}, nil
-	}
-
-	// Carregar dados
-	jsonData, err := os.ReadFile(h.dataFile)
-	if err != nil {
-		return []Link{}, err
-	}
-
-	var links []Link
-	err = json.Unmarshal(jsonData, &links)
-	if links == nil {
-		links = []Link{}
-	}
-	return links, err
-}
-
-// salvarLinksInterno salva lista de links (usado internamente)
-func (h *LinksHandler) salvarLinksInterno(links []Link) error {
-	jsonData, err := json.MarshalIndent(links, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(h.dataFile, jsonData, 0644)
+	return h.store.Carregar()
 }
 
 // AdicionarLink adiciona um novo link
 func (h *LinksHandler) AdicionarLink(link Link) error {
-	links, err := h.CarregarLinks()
-	if err != nil {
-		return err
-	}
-	links = append(links, link)
-	return h.SalvarLinks(links)
+	return h.store.Atualizar(func(links *[]Link) error {
+		*links = append(*links, link)
+		return nil
+	})
 }
 
 // DeletarLink remove um link pelo ID
 func (h *LinksHandler) DeletarLink(id string) error {
-	links, err := h.CarregarLinks()
-	if err != nil {
-		return err
-	}
-
-	var filtered []Link
-	for _, link := range links {
-		if link.ID != id {
-			filtered = append(filtered, link)
+	return h.store.Atualizar(func(links *[]Link) error {
+		filtered := []Link{}
+		for _, link := range *links {
+			if link.ID != id {
+				filtered = append(filtered, link)
+			}
 		}
-	}
-
-	return h.SalvarLinks(filtered)
+		*links = filtered
+		return nil
+	})
 }
 
 // AtualizarLink atualiza um link existente
 func (h *LinksHandler) AtualizarLink(updatedLink Link) error {
-	links, err := h.CarregarLinks()
-	if err != nil {
-		return err
-	}
-
-	for i, link := range links {
-		if link.ID == updatedLink.ID {
-			links[i] = updatedLink
-			break
+	return h.store.Atualizar(func(links *[]Link) error {
+		for i, link := range *links {
+			if link.ID == updatedLink.ID {
+				(*links)[i] = updatedLink
+				break
+			}
 		}
-	}
-
-	return h.SalvarLinks(links)
+		return nil
+	})
 }
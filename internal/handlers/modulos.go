@@ -0,0 +1,35 @@
+package handlers
+
+import "github.com/user/tdah-organizer/internal/storage"
+
+// moduloDados descreve um arquivo de dados de um módulo: o esquema das suas
+// migrações e como extrair seus itens para comparação
+type moduloDados struct {
+	modulo  string
+	esquema storage.Esquema
+	itens   func(conteudo []byte) ([]itemComparavel, error)
+}
+
+// modulosDados lista os arquivos de dados conhecidos
+var modulosDados = map[string]moduloDados{
+	"links_data.json": {"links", esquemaLinks, itensDeLista(esquemaLinks, func(l Link) (string, string) {
+		return l.ID, l.Title
+	})},
+	"calendario_data.json": {"calendario", esquemaEventos, itensDeLista(esquemaEventos, func(e Evento) (string, string) {
+		return e.ID, e.Titulo
+	})},
+	"objetivos_data.json": {"objetivos", esquemaObjetivos, itensDeLista(esquemaObjetivos, func(o Objetivo) (string, string) {
+		return o.ID, o.Titulo
+	})},
+	"passos_data.json": {"passos", esquemaPassos, itensDeLista(esquemaPassos, func(p Passo) (string, string) {
+		return p.ID, p.Descricao
+	})},
+	arquivoAssinaturas: {"calendario", esquemaAssinaturas, itensDeLista(esquemaAssinaturas, func(a Assinatura) (string, string) {
+		return a.ID, a.Nome
+	})},
+	arquivoLembretes: {"calendario", esquemaLembretes, itensDeLista(esquemaLembretes, func(d DisparoLembrete) (string, string) {
+		return d.ID, "Lembrete de " + d.Data
+	})},
+	"planejamento_data.json": {"planejamento", esquemaQuadro, itensDoQuadro},
+	arquivoIdeias:            {"ideias", esquemaCanvas, itensDoCanvas},
+}
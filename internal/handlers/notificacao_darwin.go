@@ -0,0 +1,14 @@
+//go:build darwin
+
+package handlers
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// notificar mostra uma notificação da Central de Notificações via osascript
+func notificar(titulo, corpo string) error {
+	script := "display notification " + strconv.Quote(corpo) + " with title " + strconv.Quote(titulo)
+	return exec.Command("osascript", "-e", script).Run()
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package handlers
+
+import "github.com/godbus/dbus/v5"
+
+// notificar mostra uma notificação do sistema pelo serviço
+// org.freedesktop.Notifications da sessão (GNOME, KDE, XFCE...)
+func notificar(titulo, corpo string) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+	obj := conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications")
+	dicas := map[string]dbus.Variant{
+		"urgency": dbus.MakeVariant(byte(1)), // normal
+	}
+	return obj.Call("org.freedesktop.Notifications.Notify", 0,
+		"Organizador TDAH Pro", uint32(0), "appointment-soon", titulo, corpo,
+		[]string{}, dicas, int32(-1),
+	).Err
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package handlers
+
+// notificar não tem notificação do sistema nesta plataforma: o lembrete
+// aparece só dentro do app, pelo evento "calendario:lembrete"
+func notificar(titulo, corpo string) error {
+	return nil
+}
@@ -2,16 +2,15 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
-	"os"
-	"path/filepath"
+
+	"github.com/user/tdah-organizer/internal/storage"
 )
 
 // ObjetivosHandler gerencia as operações do módulo de objetivos
 type ObjetivosHandler struct {
 	ctx       context.Context
 	assetsDir string
-	dataFile  string
+	store     *storage.Store[[]Objetivo]
 }
 
 // Objetivo representa uma meta com progresso
@@ -24,12 +23,18 @@ type Objetivo struct {
 	CreatedAt string  `json:"createdAt"`
 }
 
+// esquemaObjetivos registra as migrações de objetivos_data.json
+var esquemaObjetivos = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: dados passam a ser gravados em envelope
+	},
+}
+
 // NewObjetivosHandler cria um novo handler
-func NewObjetivosHandler(assetsDir string) *ObjetivosHandler {
-	initDir := filepath.Join(assetsDir, "init")
+func NewObjetivosHandler(assetsDir string, backend storage.Backend) *ObjetivosHandler {
 	return &ObjetivosHandler{
 		assetsDir: assetsDir,
-		dataFile:  filepath.Join(initDir, "objetivos_data.json"),
+		store:     storage.NewLista[Objetivo](backend, "objetivos_data.json", esquemaObjetivos),
 	}
 }
 
@@ -38,86 +43,53 @@ func (h *ObjetivosHandler) Startup(ctx context.Context) {
 	h.ctx = ctx
 }
 
-// CarregarObjetivos carrega todos os objetivos
-func (h *ObjetivosHandler) CarregarObjetivos() ([]Objetivo, error) {
-	// Garantir que a pasta init existe
-	initDir := filepath.Join(h.assetsDir, "init")
-	if err := os.MkdirAll(initDir, 0755); err != nil {
-		return []Objetivo{}, err
-	}
-
-	// Verificar se arquivo existe
-	if _, err := os.Stat(h.dataFile); os.IsNotExist(err) {
-		// Arquivo não existe - retornar lista vazia (app começa do zero)
-		return []Objetivo{}, nil
-	}
-
-	// Carregar dados
-	jsonData, err := os.ReadFile(h.dataFile)
-	if err != nil {
-		return []Objetivo{}, err
-	}
-
-	var objetivos []Objetivo
-	err = json.Unmarshal(jsonData, &objetivos)
-	if objetivos == nil {
-		objetivos = []Objetivo{}
-	}
-	return objetivos, err
+// Apontar passa a usar os dados de outro workspace
+func (h *ObjetivosHandler) Apontar(assetsDir string, backend storage.Backend) {
+	h.assetsDir = assetsDir
+	h.store.Apontar(backend)
 }
 
-// salvarObjetivosInterno salva lista de objetivos (usado internamente)
-func (h *ObjetivosHandler) salvarObjetivosInterno(objetivos []Objetivo) error {
-	jsonData, err := json.MarshalIndent(objetivos, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(h.dataFile, jsonData, 0644)
+// CarregarObjetivos carrega todos os objetivos
+func (h *ObjetivosHandler) CarregarObjetivos() ([]Objetivo, error) {
+	return h.store.Carregar()
 }
 
 // SalvarObjetivos salva todos os objetivos
 func (h *ObjetivosHandler) SalvarObjetivos(objetivos []Objetivo) error {
-	return h.salvarObjetivosInterno(objetivos)
+	return h.store.Salvar(objetivos)
 }
 
 // AdicionarObjetivo adiciona um novo objetivo
 func (h *ObjetivosHandler) AdicionarObjetivo(objetivo Objetivo) error {
-	objetivos, err := h.CarregarObjetivos()
-	if err != nil {
-		return err
-	}
-	objetivos = append(objetivos, objetivo)
-	return h.SalvarObjetivos(objetivos)
+	return h.store.Atualizar(func(objetivos *[]Objetivo) error {
+		*objetivos = append(*objetivos, objetivo)
+		return nil
+	})
 }
 
 // DeletarObjetivo remove um objetivo pelo ID
 func (h *ObjetivosHandler) DeletarObjetivo(id string) error {
-	objetivos, err := h.CarregarObjetivos()
-	if err != nil {
-		return err
-	}
-
-	filtered := []Objetivo{}
-	for _, o := range objetivos {
-		if o.ID != id {
-			filtered = append(filtered, o)
+	return h.store.Atualizar(func(objetivos *[]Objetivo) error {
+		filtered := []Objetivo{}
+		for _, o := range *objetivos {
+			if o.ID != id {
+				filtered = append(filtered, o)
+			}
 		}
-	}
-	return h.SalvarObjetivos(filtered)
+		*objetivos = filtered
+		return nil
+	})
 }
 
 // AtualizarObjetivo atualiza um objetivo existente
 func (h *ObjetivosHandler) AtualizarObjetivo(objetivo Objetivo) error {
-	objetivos, err := h.CarregarObjetivos()
-	if err != nil {
-		return err
-	}
-
-	for i, o := range objetivos {
-		if o.ID == objetivo.ID {
-			objetivos[i] = objetivo
-			break
+	return h.store.Atualizar(func(objetivos *[]Objetivo) error {
+		for i, o := range *objetivos {
+			if o.ID == objetivo.ID {
+				(*objetivos)[i] = objetivo
+				break
+			}
 		}
-	}
-	return h.SalvarObjetivos(objetivos)
+		return nil
+	})
 }
@@ -2,16 +2,15 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
-	"os"
-	"path/filepath"
+
+	"github.com/user/tdah-organizer/internal/storage"
 )
 
 // PassosHandler gerencia as operações do módulo de passos/objetivos
 type PassosHandler struct {
 	ctx       context.Context
 	assetsDir string
-	dataFile  string
+	store     *storage.Store[[]Passo]
 }
 
 // Passo representa um passo do objetivo
@@ -23,12 +22,18 @@ type Passo struct {
 	CreatedAt string `json:"createdAt"`
 }
 
+// esquemaPassos registra as migrações de passos_data.json
+var esquemaPassos = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: dados passam a ser gravados em envelope
+	},
+}
+
 // NewPassosHandler cria um novo handler
-func NewPassosHandler(assetsDir string) *PassosHandler {
-	initDir := filepath.Join(assetsDir, "init")
+func NewPassosHandler(assetsDir string, backend storage.Backend) *PassosHandler {
 	return &PassosHandler{
 		assetsDir: assetsDir,
-		dataFile:  filepath.Join(initDir, "passos_data.json"),
+		store:     storage.NewLista[Passo](backend, "passos_data.json", esquemaPassos),
 	}
 }
 
@@ -37,146 +42,104 @@ func (h *PassosHandler) Startup(ctx context.Context) {
 	h.ctx = ctx
 }
 
+// Apontar passa a usar os dados de outro workspace
+func (h *PassosHandler) Apontar(assetsDir string, backend storage.Backend) {
+	h.assetsDir = assetsDir
+	h.store.Apontar(backend)
+}
+
 // SalvarPassos salva a lista de passos
 func (h *PassosHandler) SalvarPassos(passos []Passo) error {
-	return h.salvarPassosInterno(passos)
+	return h.store.Salvar(passos)
 }
 
 // CarregarPassos carrega a lista de passos
 func (h *PassosHandler) CarregarPassos() ([]Passo, error) {
-	// Garantir que a pasta init existe
-	initDir := filepath.Join(h.assetsDir, "init")
-	if err := os.MkdirAll(initDir, 0755); err != nil {
-		return []Passo{}, err
-	}
-
-	// Verificar se arquivo existe
-	if _, err := os.Stat(h.dataFile); os.IsNotExist(err) {
-		// Arquivo não existe - retornar lista vazia (app começa do zero)
-		return []Passo{}, nil
-	}
-
-	// Carregar dados
-	jsonData, err := os.ReadFile(h.dataFile)
-	if err != nil {
-		return []Passo{}, err
-	}
-
-	var passos []Passo
-	err = json.Unmarshal(jsonData, &passos)
-	if passos == nil {
-		passos = []Passo{}
-	}
-	return passos, err
-}
-
-// salvarPassosInterno salva lista de passos (usado internamente)
-func (h *PassosHandler) salvarPassosInterno(passos []Passo) error {
-	jsonData, err := json.MarshalIndent(passos, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(h.dataFile, jsonData, 0644)
+	return h.store.Carregar()
 }
 
 // AdicionarPasso adiciona um novo passo
 func (h *PassosHandler) AdicionarPasso(passo Passo) error {
-	passos, err := h.CarregarPassos()
-	if err != nil {
-		return err
-	}
-
-	// Define a ordem como o próximo número
-	passo.Ordem = len(passos) + 1
-	passos = append(passos, passo)
-
-	return h.SalvarPassos(passos)
+	return h.store.Atualizar(func(passos *[]Passo) error {
+		// Define a ordem como o próximo número
+		passo.Ordem = len(*passos) + 1
+		*passos = append(*passos, passo)
+		return nil
+	})
 }
 
 // AtualizarPasso atualiza um passo existente
 func (h *PassosHandler) AtualizarPasso(updatedPasso Passo) error {
-	passos, err := h.CarregarPassos()
-	if err != nil {
-		return err
-	}
-
-	for i, passo := range passos {
-		if passo.ID == updatedPasso.ID {
-			passos[i] = updatedPasso
-			break
+	return h.store.Atualizar(func(passos *[]Passo) error {
+		for i, passo := range *passos {
+			if passo.ID == updatedPasso.ID {
+				(*passos)[i] = updatedPasso
+				break
+			}
 		}
-	}
-
-	return h.SalvarPassos(passos)
+		return nil
+	})
 }
 
 // DeletarPasso remove um passo pelo ID
 func (h *PassosHandler) DeletarPasso(id string) error {
-	passos, err := h.CarregarPassos()
-	if err != nil {
-		return err
-	}
-
-	var filtered []Passo
-	for _, passo := range passos {
-		if passo.ID != id {
-			filtered = append(filtered, passo)
+	return h.store.Atualizar(func(passos *[]Passo) error {
+		filtered := []Passo{}
+		for _, passo := range *passos {
+			if passo.ID != id {
+				filtered = append(filtered, passo)
+			}
 		}
-	}
 
-	// Reordena os passos restantes
-	for i := range filtered {
-		filtered[i].Ordem = i + 1
-	}
+		// Reordena os passos restantes
+		for i := range filtered {
+			filtered[i].Ordem = i + 1
+		}
 
-	return h.SalvarPassos(filtered)
+		*passos = filtered
+		return nil
+	})
 }
 
 // MoverPasso move um passo para cima ou para baixo
 func (h *PassosHandler) MoverPasso(passoID string, direcao string) error {
-	passos, err := h.CarregarPassos()
-	if err != nil {
-		return err
-	}
-
-	// Encontra o índice do passo
-	var idx int
-	for i, passo := range passos {
-		if passo.ID == passoID {
-			idx = i
-			break
+	return h.store.Atualizar(func(passos *[]Passo) error {
+		lista := *passos
+
+		// Encontra o índice do passo
+		var idx int
+		for i, passo := range lista {
+			if passo.ID == passoID {
+				idx = i
+				break
+			}
 		}
-	}
 
-	if direcao == "cima" && idx > 0 {
-		// Troca com o passo anterior
-		passos[idx], passos[idx-1] = passos[idx-1], passos[idx]
-	} else if direcao == "baixo" && idx < len(passos)-1 {
-		// Troca com o próximo passo
-		passos[idx], passos[idx+1] = passos[idx+1], passos[idx]
-	}
-
-	// Atualiza a ordem de todos os passos
-	for i := range passos {
-		passos[i].Ordem = i + 1
-	}
+		if direcao == "cima" && idx > 0 {
+			// Troca com o passo anterior
+			lista[idx], lista[idx-1] = lista[idx-1], lista[idx]
+		} else if direcao == "baixo" && idx < len(lista)-1 {
+			// Troca com o próximo passo
+			lista[idx], lista[idx+1] = lista[idx+1], lista[idx]
+		}
 
-	return h.SalvarPassos(passos)
+		// Atualiza a ordem de todos os passos
+		for i := range lista {
+			lista[i].Ordem = i + 1
+		}
+		return nil
+	})
 }
 
 // ToggleConcluido marca/desmarca um passo como concluído
 func (h *PassosHandler) ToggleConcluido(passoID string) error {
-	passos, err := h.CarregarPassos()
-	if err != nil {
-		return err
-	}
-
-	for i, passo := range passos {
-		if passo.ID == passoID {
-			passos[i].Concluido = !passos[i].Concluido
-			break
+	return h.store.Atualizar(func(passos *[]Passo) error {
+		for i, passo := range *passos {
+			if passo.ID == passoID {
+				(*passos)[i].Concluido = !(*passos)[i].Concluido
+				break
+			}
 		}
-	}
-
-	return h.SalvarPassos(passos)
+		return nil
+	})
 }
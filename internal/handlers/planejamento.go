@@ -2,16 +2,15 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
-	"os"
-	"path/filepath"
+
+	"github.com/user/tdah-organizer/internal/storage"
 )
 
 // PlanejamentoHandler gerencia as operações do módulo de planejamento Kanban
 type PlanejamentoHandler struct {
 	ctx       context.Context
 	assetsDir string
-	dataFile  string
+	store     *storage.Store[QuadroKanban]
 }
 
 // Tarefa representa uma tarefa no quadro Kanban
@@ -30,13 +29,23 @@ type QuadroKanban struct {
 	Feito    []Tarefa `json:"feito"`
 }
 
+// StatusTarefas são as colunas do quadro, na ordem em que aparecem no app
+var StatusTarefas = []string{"objetivo", "fazendo", "feito"}
+
+// esquemaQuadro registra as migrações de planejamento_data.json
+var esquemaQuadro = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: dados passam a ser gravados em envelope
+	},
+}
+
 // NewPlanejamentoHandler cria um novo handler
-func NewPlanejamentoHandler(assetsDir string) *PlanejamentoHandler {
-	initDir := filepath.Join(assetsDir, "init")
-	return &PlanejamentoHandler{
+func NewPlanejamentoHandler(assetsDir string, backend storage.Backend) *PlanejamentoHandler {
+	h := &PlanejamentoHandler{
 		assetsDir: assetsDir,
-		dataFile:  filepath.Join(initDir, "planejamento_data.json"),
 	}
+	h.store = storage.NewStore(backend, "planejamento_data.json", esquemaQuadro, h.quadroVazio, h.garantirColunas)
+	return h
 }
 
 // Startup é chamado quando o app inicia
@@ -44,39 +53,37 @@ func (h *PlanejamentoHandler) Startup(ctx context.Context) {
 	h.ctx = ctx
 }
 
+// Apontar passa a usar os dados de outro workspace
+func (h *PlanejamentoHandler) Apontar(assetsDir string, backend storage.Backend) {
+	h.assetsDir = assetsDir
+	h.store.Apontar(backend)
+}
+
 // SalvarQuadro salva o quadro Kanban completo
 func (h *PlanejamentoHandler) SalvarQuadro(quadro QuadroKanban) error {
-	// Garantir colunas não nulas antes de salvar
-	h.garantirColunas(&quadro)
-	return h.salvarQuadroInterno(quadro)
+	return h.store.Salvar(quadro)
 }
 
 // CarregarQuadro carrega o quadro Kanban
 func (h *PlanejamentoHandler) CarregarQuadro() (QuadroKanban, error) {
-	var quadro QuadroKanban
-
-	// Garantir que a pasta init existe
-	initDir := filepath.Join(h.assetsDir, "init")
-	if err := os.MkdirAll(initDir, 0755); err != nil {
-		return h.quadroVazio(), err
-	}
-
-	// Verificar se arquivo existe
-	if _, err := os.Stat(h.dataFile); os.IsNotExist(err) {
-		// Arquivo não existe - retornar quadro vazio (app começa do zero)
-		return h.quadroVazio(), nil
-	}
+	return h.store.Carregar()
+}
 
-	// Carregar dados
-	jsonData, err := os.ReadFile(h.dataFile)
+// ListarTarefas retorna as tarefas das três colunas, na ordem do quadro,
+// com o status de cada uma tirado da coluna em que ela está
+func (h *PlanejamentoHandler) ListarTarefas() ([]Tarefa, error) {
+	quadro, err := h.store.Carregar()
 	if err != nil {
-		return h.quadroVazio(), err
+		return []Tarefa{}, err
 	}
-
-	err = json.Unmarshal(jsonData, &quadro)
-	// Garante que as 3 colunas existam mesmo se o JSON estiver incompleto
-	h.garantirColunas(&quadro)
-	return quadro, err
+	tarefas := []Tarefa{}
+	for _, status := range StatusTarefas {
+		for _, t := range *h.coluna(&quadro, status) {
+			t.Status = status
+			tarefas = append(tarefas, t)
+		}
+	}
+	return tarefas, nil
 }
 
 // quadroVazio retorna um quadro Kanban vazio
@@ -88,15 +95,6 @@ func (h *PlanejamentoHandler) quadroVazio() QuadroKanban {
 	}
 }
 
-// salvarQuadroInterno salva dados do quadro (usado internamente)
-func (h *PlanejamentoHandler) salvarQuadroInterno(quadro QuadroKanban) error {
-	jsonData, err := json.MarshalIndent(quadro, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(h.dataFile, jsonData, 0644)
-}
-
 // garantirColunas garante que as 3 colunas existam
 func (h *PlanejamentoHandler) garantirColunas(quadro *QuadroKanban) {
 	if quadro.Objetivo == nil {
@@ -110,122 +108,92 @@ func (h *PlanejamentoHandler) garantirColunas(quadro *QuadroKanban) {
 	}
 }
 
-// AdicionarTarefa adiciona uma nova tarefa
-func (h *PlanejamentoHandler) AdicionarTarefa(tarefa Tarefa) error {
-	quadro, err := h.CarregarQuadro()
-	if err != nil {
-		return err
-	}
-
-	switch tarefa.Status {
+// coluna retorna a lista de tarefas do status informado (nil se inválido)
+func (h *PlanejamentoHandler) coluna(quadro *QuadroKanban, status string) *[]Tarefa {
+	switch status {
 	case "objetivo":
-		quadro.Objetivo = append(quadro.Objetivo, tarefa)
+		return &quadro.Objetivo
 	case "fazendo":
-		quadro.Fazendo = append(quadro.Fazendo, tarefa)
+		return &quadro.Fazendo
 	case "feito":
-		quadro.Feito = append(quadro.Feito, tarefa)
+		return &quadro.Feito
 	}
+	return nil
+}
 
-	return h.SalvarQuadro(quadro)
+// AdicionarTarefa adiciona uma nova tarefa
+func (h *PlanejamentoHandler) AdicionarTarefa(tarefa Tarefa) error {
+	return h.store.Atualizar(func(quadro *QuadroKanban) error {
+		if lista := h.coluna(quadro, tarefa.Status); lista != nil {
+			*lista = append(*lista, tarefa)
+		}
+		return nil
+	})
 }
 
 // MoverTarefa move uma tarefa entre colunas
 func (h *PlanejamentoHandler) MoverTarefa(tarefaID string, statusOrigem string, statusDestino string) error {
-	quadro, err := h.CarregarQuadro()
-	if err != nil {
-		return err
-	}
-
-	// Encontrar e remover da origem
-	var tarefaEncontrada *Tarefa
-	var listaOrigem *[]Tarefa
-
-	switch statusOrigem {
-	case "objetivo":
-		listaOrigem = &quadro.Objetivo
-	case "fazendo":
-		listaOrigem = &quadro.Fazendo
-	case "feito":
-		listaOrigem = &quadro.Feito
-	}
-
-	for i, t := range *listaOrigem {
-		if t.ID == tarefaID {
-			tarefaEncontrada = &t
-			*listaOrigem = append((*listaOrigem)[:i], (*listaOrigem)[i+1:]...)
-			break
+	return h.store.Atualizar(func(quadro *QuadroKanban) error {
+		listaOrigem := h.coluna(quadro, statusOrigem)
+		listaDestino := h.coluna(quadro, statusDestino)
+		if listaOrigem == nil || listaDestino == nil {
+			return nil // Status inválido
 		}
-	}
 
-	if tarefaEncontrada == nil {
-		return nil // Tarefa não encontrada
-	}
+		// Encontrar e remover da origem
+		var tarefaEncontrada *Tarefa
+		for i, t := range *listaOrigem {
+			if t.ID == tarefaID {
+				tarefaEncontrada = &t
+				*listaOrigem = append((*listaOrigem)[:i], (*listaOrigem)[i+1:]...)
+				break
+			}
+		}
 
-	// Adicionar ao destino
-	tarefaEncontrada.Status = statusDestino
-	switch statusDestino {
-	case "objetivo":
-		quadro.Objetivo = append(quadro.Objetivo, *tarefaEncontrada)
-	case "fazendo":
-		quadro.Fazendo = append(quadro.Fazendo, *tarefaEncontrada)
-	case "feito":
-		quadro.Feito = append(quadro.Feito, *tarefaEncontrada)
-	}
+		if tarefaEncontrada == nil {
+			return nil // Tarefa não encontrada
+		}
 
-	return h.SalvarQuadro(quadro)
+		// Adicionar ao destino
+		tarefaEncontrada.Status = statusDestino
+		*listaDestino = append(*listaDestino, *tarefaEncontrada)
+		return nil
+	})
 }
 
 // DeletarTarefa remove uma tarefa pelo ID e status
 func (h *PlanejamentoHandler) DeletarTarefa(tarefaID string, status string) error {
-	quadro, err := h.CarregarQuadro()
-	if err != nil {
-		return err
-	}
-
-	var lista *[]Tarefa
-	switch status {
-	case "objetivo":
-		lista = &quadro.Objetivo
-	case "fazendo":
-		lista = &quadro.Fazendo
-	case "feito":
-		lista = &quadro.Feito
-	}
-
-	var filtered []Tarefa
-	for _, t := range *lista {
-		if t.ID != tarefaID {
-			filtered = append(filtered, t)
+	return h.store.Atualizar(func(quadro *QuadroKanban) error {
+		lista := h.coluna(quadro, status)
+		if lista == nil {
+			return nil
 		}
-	}
-	*lista = filtered
 
-	return h.SalvarQuadro(quadro)
+		filtered := []Tarefa{}
+		for _, t := range *lista {
+			if t.ID != tarefaID {
+				filtered = append(filtered, t)
+			}
+		}
+		*lista = filtered
+		return nil
+	})
 }
 
 // AtualizarTarefa atualiza uma tarefa existente
 func (h *PlanejamentoHandler) AtualizarTarefa(tarefa Tarefa, status string) error {
-	quadro, err := h.CarregarQuadro()
-	if err != nil {
-		return err
-	}
-
-	var lista *[]Tarefa
-	switch status {
-	case "objetivo":
-		lista = &quadro.Objetivo
-	case "fazendo":
-		lista = &quadro.Fazendo
-	case "feito":
-		lista = &quadro.Feito
-	}
-
-	for i, t := range *lista {
-		if t.ID == tarefa.ID {
-			(*lista)[i] = tarefa
-			break
+	return h.store.Atualizar(func(quadro *QuadroKanban) error {
+		lista := h.coluna(quadro, status)
+		if lista == nil {
+			return nil
 		}
-	}
 
-	return h.SalvarQuadro(quadro)
+		for i, t := range *lista {
+			if t.ID == tarefa.ID {
+				(*lista)[i] = tarefa
+				break
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recorrencia é a regra de repetição de um evento, um subconjunto do RRULE
+// da RFC 5545: FREQ, INTERVAL, BYDAY, UNTIL e COUNT, mais as datas puladas
+// (EXDATE). A data do evento é a primeira ocorrência.
+type Recorrencia struct {
+	Frequencia string   `json:"frequencia"`           // diaria, semanal, mensal ou anual
+	Intervalo  int      `json:"intervalo,omitempty"`  // A cada quantos dias/semanas/meses/anos (0 = 1)
+	DiasSemana []string `json:"diasSemana,omitempty"` // BYDAY: MO..SU; no mensal aceita ordinal (2MO, -1FR)
+	Ate        string   `json:"ate,omitempty"`        // UNTIL, inclusivo. Formato: YYYY-MM-DD
+	Vezes      int      `json:"vezes,omitempty"`      // COUNT, contando as datas puladas
+	Excecoes   []string `json:"excecoes,omitempty"`   // EXDATE. Formato: YYYY-MM-DD
+}
+
+// Ocorrencia é um evento numa data, já com a recorrência expandida
+type Ocorrencia struct {
+	Evento                // Data é a da ocorrência; ID e Recorrencia são os da série
+	Recorrente     bool   `json:"recorrente,omitempty"`
+	SomenteLeitura bool   `json:"somenteLeitura,omitempty"` // Vem de um calendário assinado
+	Calendario     string `json:"calendario,omitempty"`     // Nome do calendário assinado
+}
+
+// ErrRecorrenciaInvalida indica uma regra de recorrência que não pode ser usada
+var ErrRecorrenciaInvalida = errors.New("recorrência inválida")
+
+// Frequências aceitas, com o nome correspondente no RRULE
+var frequencias = map[string]string{
+	"diaria":  "DAILY",
+	"semanal": "WEEKLY",
+	"mensal":  "MONTHLY",
+	"anual":   "YEARLY",
+}
+
+// diasRRule são os dias da semana no formato do RRULE, na ordem de time.Weekday
+var diasRRule = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// maxDiasConsulta limita o intervalo pedido a ListarOcorrencias
+const maxDiasConsulta = 3 * 366
+
+// LerRRule converte uma regra no formato da RFC 5545, como
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20250630", em Recorrencia
+func LerRRule(texto string) (*Recorrencia, error) {
+	r := &Recorrencia{}
+	texto = strings.TrimPrefix(strings.TrimSpace(texto), "RRULE:")
+	for _, parte := range strings.Split(texto, ";") {
+		chave, valor, ok := strings.Cut(parte, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: parte sem valor: %q", ErrRecorrenciaInvalida, parte)
+		}
+		var err error
+		switch strings.ToUpper(chave) {
+		case "FREQ":
+			r.Frequencia = ""
+			for nome, freq := range frequencias {
+				if strings.EqualFold(valor, freq) {
+					r.Frequencia = nome
+				}
+			}
+			if r.Frequencia == "" {
+				return nil, fmt.Errorf("%w: frequência não suportada: %s", ErrRecorrenciaInvalida, valor)
+			}
+		case "INTERVAL":
+			r.Intervalo, err = strconv.Atoi(valor)
+		case "COUNT":
+			r.Vezes, err = strconv.Atoi(valor)
+		case "BYDAY":
+			r.DiasSemana = strings.Split(strings.ToUpper(valor), ",")
+		case "UNTIL":
+			// Só a data importa: a hora é a do evento
+			var ate time.Time
+			if len(valor) < 8 {
+				err = fmt.Errorf("data curta demais")
+			} else if ate, err = time.Parse("20060102", valor[:8]); err == nil {
+				r.Ate = ate.Format(time.DateOnly)
+			}
+		case "WKST":
+			if !strings.EqualFold(valor, "MO") {
+				return nil, fmt.Errorf("%w: só WKST=MO é suportado", ErrRecorrenciaInvalida)
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s não é suportado", ErrRecorrenciaInvalida, chave)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s inválido: %s", ErrRecorrenciaInvalida, chave, valor)
+		}
+	}
+	if r.Frequencia == "" {
+		return nil, fmt.Errorf("%w: faltou FREQ", ErrRecorrenciaInvalida)
+	}
+	return r, nil
+}
+
+// RRule retorna a regra no formato da RFC 5545 (sem as exceções), com o
+// UNTIL como data, como o de um evento de dia inteiro
+func (r Recorrencia) RRule() string {
+	return r.regraICS(false)
+}
+
+// regraICS é o RRule de um evento com ou sem hora. A RFC 5545 exige que o
+// UNTIL tenha o mesmo tipo do DTSTART: num evento com hora ele vira o fim
+// do dia, também sem fuso, para continuar inclusivo.
+func (r Recorrencia) regraICS(comHora bool) string {
+	partes := []string{"FREQ=" + frequencias[r.Frequencia]}
+	if r.Intervalo > 1 {
+		partes = append(partes, fmt.Sprintf("INTERVAL=%d", r.Intervalo))
+	}
+	if len(r.DiasSemana) > 0 {
+		partes = append(partes, "BYDAY="+strings.Join(r.DiasSemana, ","))
+	}
+	if r.Ate != "" {
+		ate := strings.ReplaceAll(r.Ate, "-", "")
+		if comHora {
+			ate += "T235959"
+		}
+		partes = append(partes, "UNTIL="+ate)
+	}
+	if r.Vezes > 0 {
+		partes = append(partes, fmt.Sprintf("COUNT=%d", r.Vezes))
+	}
+	return strings.Join(partes, ";")
+}
+
+// validarRecorrencia confere a regra de um evento antes de gravá-lo
+func validarRecorrencia(e Evento) error {
+	r := e.Recorrencia
+	if r == nil {
+		return nil
+	}
+	if _, err := lerData(e.Data); err != nil {
+		return fmt.Errorf("%w: evento recorrente precisa de data (YYYY-MM-DD)", ErrRecorrenciaInvalida)
+	}
+	if _, ok := frequencias[r.Frequencia]; !ok {
+		return fmt.Errorf("%w: frequência desconhecida: %q", ErrRecorrenciaInvalida, r.Frequencia)
+	}
+	if r.Intervalo < 0 || r.Vezes < 0 {
+		return fmt.Errorf("%w: intervalo e número de vezes não podem ser negativos", ErrRecorrenciaInvalida)
+	}
+	if r.Ate != "" && r.Vezes > 0 {
+		return fmt.Errorf("%w: use data final ou número de vezes, não os dois", ErrRecorrenciaInvalida)
+	}
+	if r.Ate != "" {
+		if _, err := lerData(r.Ate); err != nil {
+			return fmt.Errorf("%w: data final inválida: %s", ErrRecorrenciaInvalida, r.Ate)
+		}
+	}
+	for _, d := range r.Excecoes {
+		if _, err := lerData(d); err != nil {
+			return fmt.Errorf("%w: exceção inválida: %s", ErrRecorrenciaInvalida, d)
+		}
+	}
+	for _, d := range r.DiasSemana {
+		ordinal, _, err := lerDiaSemana(d)
+		if err != nil {
+			return err
+		}
+		switch {
+		case r.Frequencia == "anual":
+			return fmt.Errorf("%w: dias da semana não são suportados na recorrência anual", ErrRecorrenciaInvalida)
+		case ordinal != 0 && r.Frequencia != "mensal":
+			return fmt.Errorf("%w: %s: ordinal só vale na recorrência mensal", ErrRecorrenciaInvalida, d)
+		}
+	}
+	return nil
+}
+
+// ocorrenciasEntre retorna as datas do evento entre de e ate (inclusive).
+// Tudo é calculado em datas civis (UTC), então o horário de verão não
+// desloca nem duplica ocorrências: a hora do evento vale em todas elas.
+func ocorrenciasEntre(e Evento, de, ate time.Time) []time.Time {
+	inicio, err := lerData(e.Data)
+	if err != nil {
+		return nil
+	}
+	r := e.Recorrencia
+	if r == nil {
+		if inicio.Before(de) || inicio.After(ate) {
+			return nil
+		}
+		return []time.Time{inicio}
+	}
+
+	fim := ate
+	if r.Ate != "" {
+		if limite, err := lerData(r.Ate); err == nil && limite.Before(fim) {
+			fim = limite
+		}
+	}
+	puladas := map[string]bool{}
+	for _, d := range r.Excecoes {
+		puladas[d] = true
+	}
+
+	datas := []time.Time{}
+	vezes := 0
+	// emitir conta a ocorrência e diz se ainda cabem outras
+	emitir := func(d time.Time) bool {
+		if d.After(fim) {
+			return false
+		}
+		vezes++
+		if r.Vezes > 0 && vezes > r.Vezes {
+			return false
+		}
+		if !d.Before(de) && !puladas[d.Format(time.DateOnly)] {
+			datas = append(datas, d)
+		}
+		return true
+	}
+
+	// A data do evento é sempre a primeira ocorrência, como o DTSTART
+	if !emitir(inicio) {
+		return datas
+	}
+	for periodo := 0; ; periodo++ {
+		comeco, candidatas := periodoRecorrencia(inicio, r, periodo)
+		if comeco.After(fim) {
+			return datas
+		}
+		for _, d := range candidatas {
+			if !d.After(inicio) {
+				continue
+			}
+			if !emitir(d) {
+				return datas
+			}
+		}
+	}
+}
+
+// periodoRecorrencia retorna o primeiro dia do n-ésimo período (dia, semana,
+// mês ou ano) da série e as datas dela nesse período, em ordem
+func periodoRecorrencia(inicio time.Time, r *Recorrencia, n int) (time.Time, []time.Time) {
+	passo := r.Intervalo
+	if passo < 1 {
+		passo = 1
+	}
+	n *= passo
+	ano, mes, dia := inicio.Date()
+
+	switch r.Frequencia {
+	case "diaria":
+		d := inicio.AddDate(0, 0, n)
+		if len(r.DiasSemana) > 0 && !temDiaSemana(r.DiasSemana, d.Weekday()) {
+			return d, nil
+		}
+		return d, []time.Time{d}
+
+	case "semanal":
+		// Semanas de segunda a domingo (WKST=MO)
+		segunda := inicio.AddDate(0, 0, -((int(inicio.Weekday())+6)%7)+7*n)
+		if len(r.DiasSemana) == 0 {
+			return segunda, []time.Time{inicio.AddDate(0, 0, 7*n)}
+		}
+		datas := []time.Time{}
+		for i := 0; i < 7; i++ {
+			d := segunda.AddDate(0, 0, i)
+			if temDiaSemana(r.DiasSemana, d.Weekday()) {
+				datas = append(datas, d)
+			}
+		}
+		return segunda, datas
+
+	case "mensal":
+		primeiro := time.Date(ano, mes+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.DiasSemana) == 0 {
+			// Meses sem esse dia (31, 30, 29 de fevereiro) ficam de fora
+			d := time.Date(ano, mes+time.Month(n), dia, 0, 0, 0, 0, time.UTC)
+			if d.Month() != primeiro.Month() {
+				return primeiro, nil
+			}
+			return primeiro, []time.Time{d}
+		}
+		return primeiro, diasDoMes(primeiro, r.DiasSemana)
+
+	default: // anual
+		primeiro := time.Date(ano+n, 1, 1, 0, 0, 0, 0, time.UTC)
+		d := time.Date(ano+n, mes, dia, 0, 0, 0, 0, time.UTC)
+		if d.Month() != mes {
+			return primeiro, nil
+		}
+		return primeiro, []time.Time{d}
+	}
+}
+
+// diasDoMes retorna os dias do mês que atendem a BYDAY, em ordem. "MO" são
+// todas as segundas, "2MO" a segunda segunda e "-1FR" a última sexta.
+func diasDoMes(primeiro time.Time, dias []string) []time.Time {
+	ultimo := primeiro.AddDate(0, 1, -1).Day()
+	marcados := map[int]bool{}
+	for _, d := range dias {
+		ordinal, semana, err := lerDiaSemana(d)
+		if err != nil {
+			continue
+		}
+		doDia := []int{}
+		for dia := 1; dia <= ultimo; dia++ {
+			if primeiro.AddDate(0, 0, dia-1).Weekday() == semana {
+				doDia = append(doDia, dia)
+			}
+		}
+		switch {
+		case ordinal == 0:
+			for _, dia := range doDia {
+				marcados[dia] = true
+			}
+		case ordinal > 0 && ordinal <= len(doDia):
+			marcados[doDia[ordinal-1]] = true
+		case ordinal < 0 && -ordinal <= len(doDia):
+			marcados[doDia[len(doDia)+ordinal]] = true
+		}
+	}
+	datas := []time.Time{}
+	for dia := range marcados {
+		datas = append(datas, primeiro.AddDate(0, 0, dia-1))
+	}
+	sort.Slice(datas, func(i, j int) bool { return datas[i].Before(datas[j]) })
+	return datas
+}
+
+// lerDiaSemana separa o ordinal opcional e o dia de um item de BYDAY
+func lerDiaSemana(texto string) (int, time.Weekday, error) {
+	texto = strings.ToUpper(strings.TrimSpace(texto))
+	if len(texto) >= 2 {
+		for i, nome := range diasRRule {
+			if !strings.HasSuffix(texto, nome) {
+				continue
+			}
+			ordinal := 0
+			if prefixo := texto[:len(texto)-2]; prefixo != "" {
+				n, err := strconv.Atoi(prefixo)
+				if err != nil || n == 0 || n < -5 || n > 5 {
+					break
+				}
+				ordinal = n
+			}
+			return ordinal, time.Weekday(i), nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: dia da semana inválido: %q", ErrRecorrenciaInvalida, texto)
+}
+
+func temDiaSemana(dias []string, dia time.Weekday) bool {
+	for _, d := range dias {
+		if _, semana, err := lerDiaSemana(d); err == nil && semana == dia {
+			return true
+		}
+	}
+	return false
+}
+
+// lerData interpreta uma data YYYY-MM-DD como data civil (meia-noite UTC)
+func lerData(texto string) (time.Time, error) {
+	return time.Parse(time.DateOnly, texto)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata" // America/New_York nos testes de horário de verão
+)
+
+func TestOcorrenciasEntre(t *testing.T) {
+	casos := []struct {
+		nome     string
+		data     string
+		regra    Recorrencia
+		de, ate  string
+		esperado []string
+	}{
+		{
+			nome:     "mensal no dia 31 pula os meses curtos",
+			data:     "2026-01-31",
+			regra:    Recorrencia{Frequencia: "mensal"},
+			de:       "2026-01-01",
+			ate:      "2026-12-31",
+			esperado: []string{"2026-01-31", "2026-03-31", "2026-05-31", "2026-07-31", "2026-08-31", "2026-10-31", "2026-12-31"},
+		},
+		{
+			nome:     "mensal no dia 31 com COUNT conta só os meses que têm o dia",
+			data:     "2026-01-31",
+			regra:    Recorrencia{Frequencia: "mensal", Vezes: 3},
+			de:       "2026-01-01",
+			ate:      "2026-12-31",
+			esperado: []string{"2026-01-31", "2026-03-31", "2026-05-31"},
+		},
+		{
+			nome:     "anual em 29 de fevereiro só nos bissextos",
+			data:     "2024-02-29",
+			regra:    Recorrencia{Frequencia: "anual"},
+			de:       "2024-01-01",
+			ate:      "2032-12-31",
+			esperado: []string{"2024-02-29", "2028-02-29", "2032-02-29"},
+		},
+		{
+			nome:     "última sexta do mês (-1FR)",
+			data:     "2026-01-30",
+			regra:    Recorrencia{Frequencia: "mensal", DiasSemana: []string{"-1FR"}},
+			de:       "2026-01-01",
+			ate:      "2026-05-31",
+			esperado: []string{"2026-01-30", "2026-02-27", "2026-03-27", "2026-04-24", "2026-05-29"},
+		},
+		{
+			nome:     "segunda segunda e última sexta",
+			data:     "2026-03-09",
+			regra:    Recorrencia{Frequencia: "mensal", DiasSemana: []string{"2MO", "-1FR"}},
+			de:       "2026-03-01",
+			ate:      "2026-04-30",
+			esperado: []string{"2026-03-09", "2026-03-27", "2026-04-13", "2026-04-24"},
+		},
+		{
+			nome: "COUNT conta as datas puladas pelo EXDATE",
+			data: "2026-03-02",
+			regra: Recorrencia{Frequencia: "semanal", Vezes: 4,
+				Excecoes: []string{"2026-03-09"}},
+			de:       "2026-01-01",
+			ate:      "2026-12-31",
+			esperado: []string{"2026-03-02", "2026-03-16", "2026-03-23"},
+		},
+		{
+			nome: "COUNT com consulta começando no meio da série",
+			data: "2026-03-02",
+			regra: Recorrencia{Frequencia: "semanal", Vezes: 4,
+				Excecoes: []string{"2026-03-23"}},
+			de:       "2026-03-10",
+			ate:      "2026-12-31",
+			esperado: []string{"2026-03-16"},
+		},
+		{
+			nome:     "UNTIL é inclusivo",
+			data:     "2026-03-01",
+			regra:    Recorrencia{Frequencia: "diaria", Intervalo: 2, Ate: "2026-03-07"},
+			de:       "2026-01-01",
+			ate:      "2026-12-31",
+			esperado: []string{"2026-03-01", "2026-03-03", "2026-03-05", "2026-03-07"},
+		},
+		{
+			// Nos EUA o horário de verão começa em 08/03/2026 e acaba em 01/11/2026
+			nome:     "semanal atravessando o horário de verão",
+			data:     "2026-03-01",
+			regra:    Recorrencia{Frequencia: "semanal", DiasSemana: []string{"SU"}, Vezes: 3},
+			de:       "2026-01-01",
+			ate:      "2026-12-31",
+			esperado: []string{"2026-03-01", "2026-03-08", "2026-03-15"},
+		},
+		{
+			nome:     "diária atravessando o fim do horário de verão",
+			data:     "2026-10-31",
+			regra:    Recorrencia{Frequencia: "diaria", Ate: "2026-11-02"},
+			de:       "2026-01-01",
+			ate:      "2026-12-31",
+			esperado: []string{"2026-10-31", "2026-11-01", "2026-11-02"},
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			e := Evento{Data: c.data, Hora: "10:00", Recorrencia: &c.regra}
+			if err := validarRecorrencia(e); err != nil {
+				t.Fatal(err)
+			}
+			de, _ := lerData(c.de)
+			ate, _ := lerData(c.ate)
+			datas := []string{}
+			for _, d := range ocorrenciasEntre(e, de, ate) {
+				datas = append(datas, d.Format(time.DateOnly))
+			}
+			if !reflect.DeepEqual(datas, c.esperado) {
+				t.Errorf("ocorrências %v, esperado %v", datas, c.esperado)
+			}
+		})
+	}
+}
+
+func TestMomentoOcorrenciaHorarioDeVerao(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Evento{Data: "2026-03-07", Hora: "10:00", Recorrencia: &Recorrencia{Frequencia: "diaria", Vezes: 3}}
+	de, _ := lerData("2026-03-01")
+	ate, _ := lerData("2026-03-31")
+
+	var anterior time.Time
+	for i, d := range ocorrenciasEntre(e, de, ate) {
+		momento := momentoOcorrencia(d.Format(time.DateOnly), e.Hora, loc)
+		if momento.Hour() != 10 || momento.Minute() != 0 {
+			t.Errorf("%s às %s, esperado 10:00", d.Format(time.DateOnly), momento.Format("15:04"))
+		}
+		// De sábado para domingo (08/03) o dia tem 23 horas
+		if i == 1 && momento.Sub(anterior) != 23*time.Hour {
+			t.Errorf("intervalo na virada do horário de verão = %v", momento.Sub(anterior))
+		}
+		anterior = momento
+	}
+}
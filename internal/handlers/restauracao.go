@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// InfoRestauracao descreve a última restauração, que ainda pode ser desfeita
+type InfoRestauracao struct {
+	Backup   BackupInfo `json:"backup"`            // Backup que foi restaurado
+	Anterior BackupInfo `json:"anterior"`          // Estado salvo logo antes da restauração (vazio se não havia dados)
+	Arquivos []string   `json:"arquivos"`          // Arquivos de dados restaurados
+	Imagens  []string   `json:"imagens,omitempty"` // Imagens que não existiam antes e foram trazidas
+}
+
+// esquemaRestauracao registra as migrações de desfazer.json
+var esquemaRestauracao = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+// storeRestauracao abre o registro da última restauração (nil se não houver)
+func storeRestauracao(backupDir string) *storage.Store[*InfoRestauracao] {
+	return storage.NewStore(storage.NewBackendArquivos(backupDir), "desfazer.json", esquemaRestauracao,
+		func() *InfoRestauracao { return nil },
+		nil,
+	)
+}
+
+// UltimaRestauracao retorna a restauração que pode ser desfeita, ou nil
+func (h *BackupHandler) UltimaRestauracao() (*InfoRestauracao, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return storeRestauracao(h.backupDir).Carregar()
+}
+
+// DesfazerRestauracao volta os arquivos restaurados ao estado de antes da
+// última restauração
+func (h *BackupHandler) DesfazerRestauracao() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backupDir)()
+
+	store := storeRestauracao(h.backupDir)
+	r, err := store.Carregar()
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return fmt.Errorf("não há restauração para desfazer")
+	}
+
+	// Arquivos que não existiam antes da restauração são apagados
+	anteriores := map[string]string{}
+	if r.Anterior.Nome != "" {
+		m, err := h.conteudoBackup(r.Anterior.Nome)
+		if err != nil {
+			return fmt.Errorf("estado anterior à restauração não encontrado: %w", err)
+		}
+		anteriores = m.Arquivos
+	}
+	arquivos := []string{}
+	for _, arquivo := range r.Arquivos {
+		if _, ok := anteriores[arquivo]; ok {
+			arquivos = append(arquivos, arquivo)
+		} else if err := h.removerDocumento(arquivo); err != nil {
+			return fmt.Errorf("erro ao remover %s: %w", arquivo, err)
+		}
+	}
+	if len(arquivos) > 0 {
+		if err := h.restaurarBackup(r.Anterior.Nome, arquivos); err != nil {
+			return err
+		}
+	}
+	// Só depois dos documentos: o canvas de antes não aponta para elas
+	imgDir := filepath.Join(h.assetsDir, "img")
+	for _, imagem := range r.Imagens {
+		if !nomeImagemValido(imagem) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(imgDir, imagem)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("erro ao remover a imagem %s: %w", imagem, err)
+		}
+	}
+
+	if err := store.Salvar(nil); err != nil {
+		return err
+	}
+	h.soltarDesfazer(r)
+	h.limparAntigos()
+	return nil
+}
+
+// restaurarComDesfazer salva o estado atual como backup, registra o ponto
+// para desfazer e só então restaura. Quem chama deve segurar a trava de
+// backupDir.
+func (h *BackupHandler) restaurarComDesfazer(nome string, arquivos []string) error {
+	if strings.ContainsAny(nome, "/\\") {
+		return fmt.Errorf("nome de backup inválido")
+	}
+	m, err := h.conteudoBackup(nome)
+	if err != nil {
+		return fmt.Errorf("backup não encontrado: %s", nome)
+	}
+	arquivos, err = selecionarArquivos(m, arquivos)
+	if err != nil {
+		return err
+	}
+
+	ts := strings.TrimPrefix(nome, "backup_")
+	r := &InfoRestauracao{
+		Backup:   BackupInfo{Nome: nome, Data: ts, Label: formatLabel(ts)},
+		Arquivos: arquivos,
+	}
+	if slices.Contains(arquivos, arquivoIdeias) {
+		r.Imagens = imagensNovas(filepath.Join(h.assetsDir, "img"), m.Imagens)
+	}
+	// Sem o backup de segurança a restauração não acontece
+	if err := h.registrarDesfazer(r, "antes de restaurar "+r.Backup.Label); err != nil {
+		return fmt.Errorf("erro ao salvar o estado atual antes de restaurar: %w", err)
+	}
+
+	return h.restaurarBackup(nome, arquivos)
+}
+
+// registrarDesfazer salva o estado atual num backup novo, com a nota
+// informada e fixado enquanto r puder ser desfeita, e grava r como a última
+// restauração. Sem dados no momento, r fica com Anterior vazio e desfazer
+// apaga o que foi restaurado. O registro é sempre regravado, para nunca
+// desfazer para o estado de uma restauração mais antiga. Quem chama deve
+// segurar a trava de backupDir.
+func (h *BackupHandler) registrarDesfazer(r *InfoRestauracao, nota string) error {
+	anterior, err := h.fazerBackup(true)
+	if err != nil {
+		return err
+	}
+	if anterior.Nome != "" {
+		if err := h.anotarBackup(anterior.Nome, nota, true); err != nil {
+			return err
+		}
+		anterior.Nota = strings.TrimSpace(nota)
+		anterior.Fixado = true
+	}
+	r.Anterior = anterior
+
+	store := storeRestauracao(h.backupDir)
+	antiga, _ := store.Carregar()
+	if err := store.Salvar(r); err != nil {
+		return err
+	}
+	h.soltarDesfazer(antiga)
+	h.limparAntigos()
+	return nil
+}
+
+// soltarDesfazer solta o backup de antes de r, que já não pode ser desfeita:
+// ele fica com a nota e volta a seguir a retenção
+func (h *BackupHandler) soltarDesfazer(r *InfoRestauracao) {
+	if r == nil || r.Anterior.Nome == "" || !r.Anterior.Fixado {
+		return
+	}
+	storeAnotacoes(h.backupDir).Atualizar(func(anotacoes *map[string]AnotacaoBackup) error {
+		if a, ok := (*anotacoes)[r.Anterior.Nome]; ok {
+			a.Fixado = false
+			guardarAnotacao(*anotacoes, r.Anterior.Nome, a)
+		}
+		return nil
+	})
+}
+
+// imagensNovas retorna, das imagens informadas, as que ainda não existem em
+// imgDir (as que desfazer deve apagar)
+func imagensNovas[V any](imgDir string, imagens map[string]V) []string {
+	novas := []string{}
+	for imagem := range imagens {
+		if !nomeImagemValido(imagem) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(imgDir, imagem)); errors.Is(err, os.ErrNotExist) {
+			novas = append(novas, imagem)
+		}
+	}
+	sort.Strings(novas)
+	return novas
+}
+
+// selecionarArquivos valida os arquivos pedidos contra o conteúdo do backup.
+// Lista vazia seleciona todos.
+func selecionarArquivos(m manifesto, arquivos []string) ([]string, error) {
+	if len(arquivos) == 0 {
+		todos := make([]string, 0, len(m.Arquivos))
+		for arquivo := range m.Arquivos {
+			todos = append(todos, arquivo)
+		}
+		sort.Strings(todos)
+		return todos, nil
+	}
+
+	selecionados := []string{}
+	for _, arquivo := range arquivos {
+		if !nomeArquivoDadosValido(arquivo) {
+			return nil, fmt.Errorf("arquivo de dados inválido: %s", arquivo)
+		}
+		if _, ok := m.Arquivos[arquivo]; !ok {
+			return nil, fmt.Errorf("o backup não contém %s", arquivo)
+		}
+		if !slices.Contains(selecionados, arquivo) {
+			selecionados = append(selecionados, arquivo)
+		}
+	}
+	return selecionados, nil
+}
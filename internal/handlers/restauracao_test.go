@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+func TestDesfazerRestauracao(t *testing.T) {
+	assets := t.TempDir()
+	h := NewBackupHandler(assets, storage.NewBackendArquivos(filepath.Join(assets, "init")))
+	ideias := NewIdeiasHandler(assets, h.backend)
+	foto := filepath.Join(assets, "img", "foto.png")
+	if err := os.MkdirAll(filepath.Dir(foto), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(foto, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	no := NodeData{ID: "n1", Type: "image", Position: map[string]float64{}, Data: map[string]interface{}{"imageFile": "foto.png"}}
+	if err := ideias.SalvarCanvas([]NodeData{no}, nil); err != nil {
+		t.Fatal(err)
+	}
+	comFoto, err := h.CriarBackup("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Depois do backup a imagem sai do canvas e da pasta
+	if err := ideias.SalvarCanvas(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(foto); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.RestaurarBackup(comFoto.Nome); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(foto); err != nil {
+		t.Fatalf("imagem não restaurada: %v", err)
+	}
+	r, err := h.UltimaRestauracao()
+	if err != nil || r == nil {
+		t.Fatalf("UltimaRestauracao = %v, %v", r, err)
+	}
+	if !reflect.DeepEqual(r.Imagens, []string{"foto.png"}) {
+		t.Errorf("imagens trazidas = %v", r.Imagens)
+	}
+	nota := "antes de restaurar " + comFoto.Label
+	if anotacao := storeAnotacoesTeste(t, h)[r.Anterior.Nome]; anotacao.Nota != nota || !anotacao.Fixado {
+		t.Errorf("backup de antes da restauração = %+v, esperado %q fixado", anotacao, nota)
+	}
+
+	if err := h.DesfazerRestauracao(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(foto); !os.IsNotExist(err) {
+		t.Errorf("imagem trazida pela restauração continua na pasta (%v)", err)
+	}
+	if canvas, err := ideias.CarregarCanvas(); err != nil || len(canvas.Nodes) != 0 {
+		t.Errorf("canvas depois de desfazer = %+v, %v", canvas, err)
+	}
+	// Sem restauração para desfazer, o backup de antes volta à retenção
+	if anotacao := storeAnotacoesTeste(t, h)[r.Anterior.Nome]; anotacao.Nota != nota || anotacao.Fixado {
+		t.Errorf("backup de antes da restauração depois de desfazer = %+v", anotacao)
+	}
+}
+
+func TestRestauracaoNovaSoltaAnterior(t *testing.T) {
+	assets := t.TempDir()
+	h := NewBackupHandler(assets, storage.NewBackendArquivos(filepath.Join(assets, "init")))
+	links := NewLinksHandler(assets, h.backend)
+	if err := links.AdicionarLink(Link{ID: "1", URL: "https://exemplo.com"}); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := h.CriarBackup("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.RestaurarBackup(backup.Nome); err != nil {
+		t.Fatal(err)
+	}
+	primeira, _ := h.UltimaRestauracao()
+	if err := h.RestaurarBackup(backup.Nome); err != nil {
+		t.Fatal(err)
+	}
+	segunda, _ := h.UltimaRestauracao()
+	if primeira == nil || segunda == nil || primeira.Anterior.Nome == segunda.Anterior.Nome {
+		t.Fatalf("restaurações = %+v, %+v", primeira, segunda)
+	}
+
+	anotacoes := storeAnotacoesTeste(t, h)
+	if anotacoes[primeira.Anterior.Nome].Fixado {
+		t.Errorf("o backup da restauração substituída continua fixado: %+v", anotacoes[primeira.Anterior.Nome])
+	}
+	if !anotacoes[segunda.Anterior.Nome].Fixado {
+		t.Errorf("o backup da restauração atual não está fixado: %+v", anotacoes[segunda.Anterior.Nome])
+	}
+}
+
+func storeAnotacoesTeste(t *testing.T, h *BackupHandler) map[string]AnotacaoBackup {
+	t.Helper()
+	anotacoes, err := storeAnotacoes(h.backupDir).Carregar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return anotacoes
+}
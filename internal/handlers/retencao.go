@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// PoliticaRetencao define quantos backups antigos são mantidos.
+// Em cada período é mantido o backup mais recente; zero desliga o período.
+type PoliticaRetencao struct {
+	Ultimos int `json:"ultimos"` // Os N backups mais recentes, sempre
+	Horas   int `json:"horas"`   // Um backup por hora nas últimas N horas
+	Dias    int `json:"dias"`    // Um backup por dia nos últimos N dias
+	Semanas int `json:"semanas"` // Um backup por semana nas últimas N semanas
+}
+
+// retencaoPadrao mantém os 10 últimos, um por hora no último dia, um por dia
+// no último mês e um por semana no último ano
+var retencaoPadrao = PoliticaRetencao{Ultimos: 10, Horas: 24, Dias: 30, Semanas: 52}
+
+// esquemaRetencao registra as migrações de retencao.json
+var esquemaRetencao = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+// storeRetencao abre a política gravada na pasta de backups do workspace
+func storeRetencao(backupDir string) *storage.Store[PoliticaRetencao] {
+	return storage.NewStore(storage.NewBackendArquivos(backupDir), "retencao.json", esquemaRetencao,
+		func() PoliticaRetencao { return retencaoPadrao },
+		nil,
+	)
+}
+
+func (p PoliticaRetencao) validar() error {
+	if p.Ultimos < 0 || p.Horas < 0 || p.Dias < 0 || p.Semanas < 0 {
+		return fmt.Errorf("os períodos de retenção não podem ser negativos")
+	}
+	return nil
+}
+
+// manter decide quais backups ficam. backups deve estar do mais recente para
+// o mais antigo; o mais recente sempre fica, assim como backups sem data
+// reconhecível.
+func (p PoliticaRetencao) manter(backups []BackupInfo, agora time.Time) map[string]bool {
+	manter := make(map[string]bool)
+	periodos := make(map[string]bool)
+
+	marcar := func(nome, periodo string) {
+		if !periodos[periodo] {
+			periodos[periodo] = true
+			manter[nome] = true
+		}
+	}
+
+	for i, b := range backups {
+		t, err := time.ParseInLocation("2006-01-02_15-04-05", b.Data, agora.Location())
+		if err != nil {
+			manter[b.Nome] = true
+			continue
+		}
+		if i == 0 || i < p.Ultimos {
+			manter[b.Nome] = true
+		}
+		idade := agora.Sub(t)
+		if idade < time.Duration(p.Horas)*time.Hour {
+			marcar(b.Nome, "h"+t.Format("2006-01-02_15"))
+		}
+		if idade < time.Duration(p.Dias)*24*time.Hour {
+			marcar(b.Nome, "d"+t.Format("2006-01-02"))
+		}
+		if idade < time.Duration(p.Semanas)*7*24*time.Hour {
+			ano, semana := t.ISOWeek()
+			marcar(b.Nome, fmt.Sprintf("s%d-%02d", ano, semana))
+		}
+	}
+	return manter
+}
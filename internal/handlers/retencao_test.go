@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+func TestPoliticaRetencaoManter(t *testing.T) {
+	// Domingo, fim da semana ISO 11 (09/03 a 15/03)
+	agora := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	casos := []struct {
+		nome     string
+		politica PoliticaRetencao
+		backups  []string // do mais recente para o mais antigo
+		esperado []string
+	}{
+		{
+			nome:     "os N últimos",
+			politica: PoliticaRetencao{Ultimos: 3},
+			backups:  []string{"2026-03-15_11-00-00", "2026-03-14_11-00-00", "2026-03-13_11-00-00", "2026-03-12_11-00-00", "2025-01-01_00-00-00"},
+			esperado: []string{"2026-03-15_11-00-00", "2026-03-14_11-00-00", "2026-03-13_11-00-00"},
+		},
+		{
+			nome:     "tudo desligado ainda mantém o mais recente",
+			politica: PoliticaRetencao{},
+			backups:  []string{"2026-03-01_11-00-00", "2026-02-01_11-00-00"},
+			esperado: []string{"2026-03-01_11-00-00"},
+		},
+		{
+			nome:     "um por hora, o mais recente de cada",
+			politica: PoliticaRetencao{Horas: 3},
+			backups:  []string{"2026-03-15_11-50-00", "2026-03-15_11-10-00", "2026-03-15_10-30-00", "2026-03-15_10-05-00", "2026-03-15_09-40-00", "2026-03-15_08-59-00"},
+			esperado: []string{"2026-03-15_11-50-00", "2026-03-15_10-30-00", "2026-03-15_09-40-00"},
+		},
+		{
+			nome:     "um por dia, contando a idade e não o calendário",
+			politica: PoliticaRetencao{Dias: 2},
+			backups:  []string{"2026-03-15_11-00-00", "2026-03-15_08-00-00", "2026-03-14_20-00-00", "2026-03-14_09-00-00", "2026-03-13_13-00-00", "2026-03-13_11-00-00"},
+			esperado: []string{"2026-03-15_11-00-00", "2026-03-14_20-00-00", "2026-03-13_13-00-00"},
+		},
+		{
+			nome:     "um por semana ISO",
+			politica: PoliticaRetencao{Semanas: 2},
+			backups:  []string{"2026-03-14_10-00-00", "2026-03-10_10-00-00", "2026-03-08_10-00-00", "2026-03-02_10-00-00", "2026-03-01_13-00-00", "2026-03-01_11-00-00"},
+			esperado: []string{"2026-03-14_10-00-00", "2026-03-08_10-00-00", "2026-03-01_13-00-00"},
+		},
+		{
+			nome:     "períodos somados",
+			politica: PoliticaRetencao{Ultimos: 2, Horas: 1, Dias: 3, Semanas: 4},
+			backups: []string{"2026-03-15_11-30-00", "2026-03-15_11-20-00", "2026-03-15_11-10-00", "2026-03-15_09-00-00",
+				"2026-03-13_18-00-00", "2026-03-13_08-00-00", "2026-03-04_10-00-00", "2026-02-20_10-00-00", "2026-02-01_10-00-00"},
+			esperado: []string{"2026-03-15_11-30-00", "2026-03-15_11-20-00", "2026-03-13_18-00-00",
+				"2026-03-04_10-00-00", "2026-02-20_10-00-00"},
+		},
+		{
+			nome:     "sem data reconhecível sempre fica",
+			politica: PoliticaRetencao{Ultimos: 1},
+			backups:  []string{"2026-03-15_11-00-00", "copia-manual", "2026-03-14_11-00-00"},
+			esperado: []string{"2026-03-15_11-00-00", "copia-manual"},
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			backups := []BackupInfo{}
+			for _, data := range c.backups {
+				backups = append(backups, BackupInfo{Nome: "backup_" + data, Data: data})
+			}
+			manter := c.politica.manter(backups, agora)
+			mantidos := []string{}
+			for _, data := range c.backups {
+				if manter["backup_"+data] {
+					mantidos = append(mantidos, data)
+				}
+			}
+			if !slices.Equal(mantidos, c.esperado) {
+				t.Errorf("mantidos %v, esperado %v", mantidos, c.esperado)
+			}
+		})
+	}
+}
+
+func TestPodarObjetosCompartilhados(t *testing.T) {
+	assets := t.TempDir()
+	h := NewBackupHandler(assets, storage.NewBackendArquivos(filepath.Join(assets, "init")))
+	gravar := func(nome, conteudo string) {
+		t.Helper()
+		if err := h.backend.Gravar(nome, []byte(conteudo)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gravar("passos_data.json", `{"schemaVersion":1,"dados":[{"id":"p1","titulo":"Passo"}]}`)
+	gravar("links_data.json", `{"schemaVersion":1,"dados":[{"id":"1","url":"https://exemplo.com"}]}`)
+	antigo, err := h.CriarBackup("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// passos_data.json não muda: os dois backups usam o mesmo objeto
+	gravar("links_data.json", `{"schemaVersion":1,"dados":[]}`)
+	novo, err := h.CriarBackup("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mAntigo, _ := conteudoBackupEm(h.backupDir, antigo.Nome)
+	mNovo, _ := conteudoBackupEm(h.backupDir, novo.Nome)
+	if mAntigo.Arquivos["passos_data.json"] != mNovo.Arquivos["passos_data.json"] {
+		t.Fatalf("passos_data.json deveria ser o mesmo objeto: %v, %v", mAntigo.Arquivos, mNovo.Arquivos)
+	}
+
+	objetos := func() []string {
+		t.Helper()
+		entradas, err := os.ReadDir(filepath.Join(h.backupDir, pastaObjetos))
+		if err != nil {
+			t.Fatal(err)
+		}
+		nomes := []string{}
+		for _, e := range entradas {
+			nomes = append(nomes, e.Name())
+		}
+		return nomes
+	}
+	backups, err := h.listarBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Com o manifesto de um backup mantido ilegível, nenhum objeto é apagado
+	manifestoNovo := filepath.Join(h.backupDir, novo.Nome, arquivoManifesto)
+	original, err := os.ReadFile(manifestoNovo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(manifestoNovo, []byte("{"), 0600)
+	podar(h.backupDir, backups, map[string]bool{novo.Nome: true})
+	if len(objetos()) != 3 {
+		t.Errorf("objetos apagados sem saber o que o backup mantido usa: %v", objetos())
+	}
+	os.WriteFile(manifestoNovo, original, 0600)
+
+	podar(h.backupDir, backups, map[string]bool{novo.Nome: true})
+	if _, err := os.Stat(filepath.Join(h.backupDir, antigo.Nome)); !os.IsNotExist(err) {
+		t.Errorf("backup antigo continua lá (%v)", err)
+	}
+	esperado := []string{mNovo.Arquivos["links_data.json"], mNovo.Arquivos["passos_data.json"]}
+	slices.Sort(esperado)
+	if restantes := objetos(); !slices.Equal(restantes, esperado) {
+		t.Errorf("objetos = %v, esperado só os do backup mantido %v", restantes, esperado)
+	}
+	for arquivo := range mNovo.Arquivos {
+		if dados, err := h.lerDoBackup(novo.Nome, mNovo, arquivo); err != nil || !strings.Contains(string(dados), "dados") {
+			t.Errorf("%s ilegível no backup mantido: %v", arquivo, err)
+		}
+	}
+}
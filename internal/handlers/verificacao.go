@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/tdah-organizer/internal/storage"
+)
+
+// VerificacaoBackup é o resultado da conferência de um backup: cada objeto
+// é relido e tem o hash recalculado, e cada arquivo de dados é decodificado
+// e tem os itens contados de novo
+type VerificacaoBackup struct {
+	Backup       string   `json:"backup"`
+	Integro      bool     `json:"integro"`
+	Problemas    []string `json:"problemas"`
+	VerificadoEm string   `json:"verificadoEm"` // RFC 3339
+}
+
+// esquemaVerificacoes registra as migrações de verificacao.json
+var esquemaVerificacoes = storage.Esquema{
+	Migracoes: []storage.Migracao{
+		storage.SemAlteracao, // v1: formato inicial
+	},
+}
+
+// storeVerificacoes abre o resultado da última verificação de cada backup,
+// gravado na pasta de backups do workspace
+func storeVerificacoes(backupDir string) *storage.Store[map[string]VerificacaoBackup] {
+	return storage.NewStore(storage.NewBackendArquivos(backupDir), "verificacao.json", esquemaVerificacoes,
+		func() map[string]VerificacaoBackup { return map[string]VerificacaoBackup{} },
+		nil,
+	)
+}
+
+// VerificarBackup confere a integridade de um backup e guarda o resultado,
+// que passa a aparecer em ListarBackups
+func (h *BackupHandler) VerificarBackup(nome string) (VerificacaoBackup, error) {
+	if strings.ContainsAny(nome, "/\\") {
+		return VerificacaoBackup{}, fmt.Errorf("nome de backup inválido")
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backupDir)()
+
+	if _, err := os.Stat(filepath.Join(h.backupDir, nome)); err != nil {
+		return VerificacaoBackup{}, fmt.Errorf("backup não encontrado: %s", nome)
+	}
+	if h.cifra != nil && h.cifra.Bloqueada() {
+		return VerificacaoBackup{}, storage.ErrBloqueado
+	}
+	v := h.verificarBackup(nome)
+	h.registrarVerificacao(v)
+	return v, nil
+}
+
+// VerificarTodos confere todos os backups (mais recentes primeiro)
+func (h *BackupHandler) VerificarTodos() ([]VerificacaoBackup, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	defer storage.Travar(h.backupDir)()
+
+	if h.cifra != nil && h.cifra.Bloqueada() {
+		return []VerificacaoBackup{}, storage.ErrBloqueado
+	}
+	backups, err := h.listarBackups()
+	if err != nil {
+		return []VerificacaoBackup{}, err
+	}
+	resultados := make([]VerificacaoBackup, 0, len(backups))
+	for _, b := range backups {
+		v := h.verificarBackup(b.Nome)
+		h.registrarVerificacao(v)
+		resultados = append(resultados, v)
+	}
+	return resultados, nil
+}
+
+// --- Funções internas ---
+
+// verificarBackup relê tudo o que o backup aponta. Backups antigos, sem
+// manifesto, não têm hashes gravados: só é conferido se os arquivos são
+// legíveis. Quem chama deve segurar a trava de backupDir.
+func (h *BackupHandler) verificarBackup(nome string) VerificacaoBackup {
+	v := VerificacaoBackup{Backup: nome, Problemas: []string{}, VerificadoEm: time.Now().Format(time.RFC3339)}
+	problema := func(formato string, args ...any) {
+		v.Problemas = append(v.Problemas, fmt.Sprintf(formato, args...))
+	}
+
+	m, err := h.conteudoBackup(nome)
+	if err != nil {
+		problema("%v", err)
+		return v
+	}
+
+	arquivos := make([]string, 0, len(m.Arquivos))
+	for arquivo := range m.Arquivos {
+		arquivos = append(arquivos, arquivo)
+	}
+	sort.Strings(arquivos)
+	for _, arquivo := range arquivos {
+		dados, err := h.lerDoBackup(nome, m, arquivo)
+		if err != nil {
+			problema("%s: %v", arquivo, descreverErroObjeto(err))
+			continue
+		}
+		if !m.legado && hashConteudo(dados) != m.Arquivos[arquivo] {
+			problema("%s: conteúdo diferente do registrado no manifesto", arquivo)
+			continue
+		}
+		if !json.Valid(dados) {
+			problema("%s: JSON ilegível", arquivo)
+			continue
+		}
+		itens := contarItens(arquivo, dados)
+		if _, conhecido := modulosDados[arquivo]; conhecido && itens < 0 {
+			problema("%s: não foi possível ler os itens", arquivo)
+			continue
+		}
+		if esperado, ok := m.Itens[arquivo]; ok && itens != esperado {
+			problema("%s: %d itens, o manifesto registra %d", arquivo, itens, esperado)
+		}
+	}
+
+	imagens := make([]string, 0, len(m.Imagens))
+	for imagem := range m.Imagens {
+		imagens = append(imagens, imagem)
+	}
+	sort.Strings(imagens)
+	for _, imagem := range imagens {
+		dados, err := h.lerObjeto(m.Imagens[imagem])
+		if err != nil {
+			problema("img/%s: %v", imagem, descreverErroObjeto(err))
+			continue
+		}
+		if hashConteudo(dados) != m.Imagens[imagem] {
+			problema("img/%s: conteúdo diferente do registrado no manifesto", imagem)
+		}
+	}
+
+	v.Integro = len(v.Problemas) == 0
+	return v
+}
+
+// registrarVerificacao guarda o resultado para ListarBackups
+func (h *BackupHandler) registrarVerificacao(v VerificacaoBackup) {
+	storeVerificacoes(h.backupDir).Atualizar(func(verificacoes *map[string]VerificacaoBackup) error {
+		(*verificacoes)[v.Backup] = v
+		return nil
+	})
+}
+
+// contarItens conta os itens de um arquivo de dados de módulo conhecido.
+// Retorna -1 para outros arquivos ou se o conteúdo não puder ser decodificado.
+func contarItens(arquivo string, dados []byte) int {
+	mod, ok := modulosDados[arquivo]
+	if !ok {
+		return -1
+	}
+	itens, err := mod.itens(dados)
+	if err != nil {
+		return -1
+	}
+	return len(itens)
+}
+
+// descreverErroObjeto troca os erros de leitura mais comuns por mensagens
+// para quem vai ler a lista de backups
+func descreverErroObjeto(err error) error {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("objeto não encontrado")
+	case errors.Is(err, storage.ErrSenhaIncorreta):
+		return fmt.Errorf("objeto cifrado com outra senha ou alterado")
+	}
+	return err
+}
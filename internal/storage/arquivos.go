@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sufixoDados identifica os arquivos de dados dentro da pasta
+const sufixoDados = "_data.json"
+
+// sufixoQuarentena é acrescentado ao nome de um arquivo corrompido
+const sufixoQuarentena = ".corrupt-"
+
+// BackendArquivos guarda cada documento como um arquivo JSON em uma pasta
+// (normalmente assets/init)
+type BackendArquivos struct {
+	dir string
+}
+
+// NewBackendArquivos cria um backend de arquivos na pasta informada
+func NewBackendArquivos(dir string) *BackendArquivos {
+	return &BackendArquivos{dir: dir}
+}
+
+// Dir retorna a pasta dos arquivos de dados
+func (b *BackendArquivos) Dir() string {
+	return b.dir
+}
+
+func (b *BackendArquivos) caminho(nome string) string {
+	return filepath.Join(b.dir, nome)
+}
+
+// Chave usa o caminho do arquivo
+func (b *BackendArquivos) Chave(nome string) string {
+	return b.caminho(nome)
+}
+
+// Ler retorna o conteúdo do arquivo
+func (b *BackendArquivos) Ler(nome string) ([]byte, error) {
+	return os.ReadFile(b.caminho(nome))
+}
+
+// Gravar grava o arquivo de forma atômica
+func (b *BackendArquivos) Gravar(nome string, conteudo []byte) error {
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return err
+	}
+	return EscreverAtomico(b.caminho(nome), conteudo, 0600)
+}
+
+// Listar retorna os arquivos *_data.json da pasta
+func (b *BackendArquivos) Listar() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nomes := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), sufixoDados) {
+			nomes = append(nomes, entry.Name())
+		}
+	}
+	return nomes, nil
+}
+
+// Remover apaga o arquivo
+func (b *BackendArquivos) Remover(nome string) error {
+	if err := os.Remove(b.caminho(nome)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	sincronizarPasta(b.dir)
+	return nil
+}
+
+// Quarentenar move um arquivo corrompido para <arquivo>.corrupt-<timestamp>.
+// Uma segunda quarentena no mesmo segundo ganha um contador (-001, -002...),
+// que mantém a ordem por nome, em vez de sobrescrever a primeira cópia.
+func (b *BackendArquivos) Quarentenar(nome string) (string, error) {
+	ts := time.Now().Format("2006-01-02_15-04-05")
+	destino := b.caminho(nome) + sufixoQuarentena + ts
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(destino); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		destino = fmt.Sprintf("%s%s%s-%03d", b.caminho(nome), sufixoQuarentena, ts, i)
+	}
+	if err := os.Rename(b.caminho(nome), destino); err != nil {
+		return "", err
+	}
+	sincronizarPasta(b.dir)
+	return filepath.Base(destino), nil
+}
+
+// quarentenas retorna as cópias em quarentena de um arquivo (mais recente primeiro)
+func (b *BackendArquivos) quarentenas(nome string) []string {
+	matches, _ := filepath.Glob(b.caminho(nome) + sufixoQuarentena + "*")
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	return matches
+}
+
+// Bloqueado informa se o arquivo foi para a quarentena e ainda não foi
+// substituído por uma versão válida
+func (b *BackendArquivos) Bloqueado(nome string) (string, bool) {
+	if _, err := os.Stat(b.caminho(nome)); err == nil {
+		return "", false
+	}
+	q := b.quarentenas(nome)
+	if len(q) == 0 {
+		return "", false
+	}
+	return filepath.Base(q[0]), true
+}
+
+// ListarBloqueados retorna os arquivos em quarentena sem versão válida
+func (b *BackendArquivos) ListarBloqueados() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(b.dir, "*"+sufixoDados+sufixoQuarentena+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	vistos := make(map[string]bool)
+	nomes := []string{}
+	for _, m := range matches {
+		nome := filepath.Base(m)
+		nome = nome[:strings.Index(nome, sufixoQuarentena)]
+		if vistos[nome] {
+			continue
+		}
+		vistos[nome] = true
+		if _, bloqueado := b.Bloqueado(nome); bloqueado {
+			nomes = append(nomes, nome)
+		}
+	}
+	return nomes, nil
+}
+
+// DescartarQuarentena apaga as cópias corrompidas de um arquivo
+func (b *BackendArquivos) DescartarQuarentena(nome string) error {
+	for _, q := range b.quarentenas(nome) {
+		if err := os.Remove(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestQuarentenarMesmoSegundo(t *testing.T) {
+	dir := t.TempDir()
+	b := NewBackendArquivos(dir)
+
+	copias := map[string]string{}
+	for _, conteudo := range []string{"primeira {", "segunda {", "terceira {"} {
+		if err := os.WriteFile(filepath.Join(dir, "links_data.json"), []byte(conteudo), 0644); err != nil {
+			t.Fatal(err)
+		}
+		q, err := b.Quarentenar("links_data.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, repetida := copias[q]; repetida {
+			t.Fatalf("quarentena %s reaproveitada", q)
+		}
+		copias[q] = conteudo
+	}
+
+	for q, conteudo := range copias {
+		lido, err := os.ReadFile(filepath.Join(dir, q))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(lido) != conteudo {
+			t.Errorf("%s = %q, esperado %q", q, lido, conteudo)
+		}
+	}
+	// A mais recente vem primeiro
+	if q, ok := b.Bloqueado("links_data.json"); !ok || copias[q] != "terceira {" {
+		t.Errorf("Bloqueado = %s (%q), esperado a terceira cópia", q, copias[q])
+	}
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// EscreverAtomico grava dados em caminho sem nunca deixar o arquivo pela metade.
+// O conteúdo vai primeiro para um arquivo temporário na mesma pasta, que é
+// sincronizado no disco e só então renomeado por cima do original. Se o
+// processo cair no meio, a versão anterior continua intacta.
+func EscreverAtomico(caminho string, dados []byte, perm os.FileMode) error {
+	return gravarAtomico(caminho, perm, func(w io.Writer) error {
+		_, err := w.Write(dados)
+		return err
+	})
+}
+
+// CopiarAtomico copia src para dst com as mesmas garantias de EscreverAtomico
+func CopiarAtomico(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return gravarAtomico(dst, 0644, func(w io.Writer) error {
+		_, err := io.Copy(w, in)
+		return err
+	})
+}
+
+// GravarAtomico grava em caminho o que escrever produzir, com as mesmas
+// garantias de EscreverAtomico. Útil para conteúdos grandes gerados aos poucos.
+func GravarAtomico(caminho string, perm os.FileMode, escrever func(io.Writer) error) error {
+	return gravarAtomico(caminho, perm, escrever)
+}
+
+func gravarAtomico(caminho string, perm os.FileMode, escrever func(io.Writer) error) (err error) {
+	dir := filepath.Dir(caminho)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(caminho)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Em qualquer falha o temporário é descartado e o original fica como estava
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if err = escrever(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, caminho); err != nil {
+		return err
+	}
+
+	sincronizarPasta(dir)
+	return nil
+}
+
+// sincronizarPasta grava no disco a entrada de diretório criada pelo rename.
+// Não é suportado em todos os sistemas (ex: Windows), então erros são ignorados.
+func sincronizarPasta(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
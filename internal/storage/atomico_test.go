@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// temporarios lista os .tmp-* que sobraram na pasta
+func temporarios(t *testing.T, dir string) []string {
+	t.Helper()
+	entradas, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nomes []string
+	for _, e := range entradas {
+		if strings.Contains(e.Name(), ".tmp-") {
+			nomes = append(nomes, e.Name())
+		}
+	}
+	return nomes
+}
+
+func TestGravarAtomicoFalhaNoMeio(t *testing.T) {
+	dir := t.TempDir()
+	caminho := filepath.Join(dir, "tarefas_data.json")
+	original := []byte(`{"schemaVersion":1,"dados":[]}`)
+	if err := EscreverAtomico(caminho, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	falha := errors.New("disco cheio")
+	err := GravarAtomico(caminho, 0644, func(w io.Writer) error {
+		if _, err := w.Write([]byte(`{"schemaVersion":1,"dados":[{"id":`)); err != nil {
+			return err
+		}
+		return falha
+	})
+	if !errors.Is(err, falha) {
+		t.Fatalf("erro = %v, esperado %v", err, falha)
+	}
+
+	lido, err := os.ReadFile(caminho)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(lido) != string(original) {
+		t.Errorf("original alterado: %q", lido)
+	}
+	if sobras := temporarios(t, dir); len(sobras) > 0 {
+		t.Errorf("temporários não removidos: %v", sobras)
+	}
+}
+
+func TestGravarAtomicoArquivoNovo(t *testing.T) {
+	dir := t.TempDir()
+	caminho := filepath.Join(dir, "novo_data.json")
+
+	err := GravarAtomico(caminho, 0644, func(w io.Writer) error {
+		w.Write([]byte("pela metade"))
+		return errors.New("interrompido")
+	})
+	if err == nil {
+		t.Fatal("esperava erro")
+	}
+	if _, err := os.Stat(caminho); !os.IsNotExist(err) {
+		t.Errorf("arquivo criado mesmo com a falha: %v", err)
+	}
+	if sobras := temporarios(t, dir); len(sobras) > 0 {
+		t.Errorf("temporários não removidos: %v", sobras)
+	}
+}
+
+func TestEscreverAtomicoSubstitui(t *testing.T) {
+	dir := t.TempDir()
+	caminho := filepath.Join(dir, "ideias_data.json")
+	if err := EscreverAtomico(caminho, []byte("antigo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := EscreverAtomico(caminho, []byte("novo"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lido, err := os.ReadFile(caminho)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(lido) != "novo" {
+		t.Errorf("conteúdo = %q, esperado %q", lido, "novo")
+	}
+	if sobras := temporarios(t, dir); len(sobras) > 0 {
+		t.Errorf("temporários não removidos: %v", sobras)
+	}
+}
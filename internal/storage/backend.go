@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Backend é onde os documentos de dados (links_data.json, ...) ficam guardados.
+// O conteúdo é sempre o JSON completo do documento, com ou sem envelope.
+type Backend interface {
+	// Ler retorna o conteúdo do documento ou um erro os.ErrNotExist
+	Ler(nome string) ([]byte, error)
+	// Gravar substitui o documento de forma atômica
+	Gravar(nome string, conteudo []byte) error
+	// Listar retorna os nomes dos documentos existentes
+	Listar() ([]string, error)
+	// Remover apaga o documento; não é erro se ele não existir
+	Remover(nome string) error
+	// Chave identifica o documento para a trava compartilhada (ver Travar)
+	Chave(nome string) string
+
+	// Quarentenar tira de uso um documento ilegível, preservando uma cópia,
+	// e retorna onde ela ficou
+	Quarentenar(nome string) (string, error)
+	// Bloqueado informa se o documento está em quarentena sem ter sido
+	// substituído por uma versão válida
+	Bloqueado(nome string) (string, bool)
+	// ListarBloqueados retorna os documentos bloqueados
+	ListarBloqueados() ([]string, error)
+	// DescartarQuarentena apaga as cópias em quarentena de um documento
+	DescartarQuarentena(nome string) error
+}
+
+// ErroCorrompido indica que um documento não pôde ser lido e foi colocado em
+// quarentena. Enquanto ele não for recuperado (ou descartado) o store se
+// recusa a gravar por cima.
+type ErroCorrompido struct {
+	Nome       string
+	Quarentena string
+	Err        error
+}
+
+func (e *ErroCorrompido) Error() string {
+	msg := fmt.Sprintf("arquivo de dados corrompido: %s (cópia preservada em %s); restaure um backup antes de continuar",
+		e.Nome, e.Quarentena)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ErroCorrompido) Unwrap() error {
+	return e.Err
+}
+
+// Importar copia para destino todos os documentos de origem.
+// Usado na primeira execução de um backend novo (ex: JSON → SQLite).
+func Importar(destino, origem Backend) (int, error) {
+	nomes, err := origem.Listar()
+	if err != nil {
+		return 0, err
+	}
+
+	importados := 0
+	for _, nome := range nomes {
+		conteudo, err := origem.Ler(nome)
+		if err != nil {
+			return importados, fmt.Errorf("erro ao ler %s: %w", nome, err)
+		}
+		unlock := Travar(destino.Chave(nome))
+		err = destino.Gravar(nome, conteudo)
+		unlock()
+		if err != nil {
+			return importados, fmt.Errorf("erro ao importar %s: %w", nome, err)
+		}
+		importados++
+	}
+	return importados, nil
+}
+
+// Renomear move o documento antigo para o nome novo, se o antigo existir e
+// o novo ainda não. O conteúdo é copiado como está, cifrado ou não. Usado
+// quando um documento passa a ter outro nome (ex: para ganhar o sufixo
+// _data.json e entrar nos backups).
+func Renomear(backend Backend, antigo, novo string) error {
+	if c, ok := backend.(*BackendCifrado); ok {
+		backend = c.Backend
+	}
+	unlockAntigo := Travar(backend.Chave(antigo))
+	defer unlockAntigo()
+	unlockNovo := Travar(backend.Chave(novo))
+	defer unlockNovo()
+
+	conteudo, err := backend.Ler(antigo)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", antigo, err)
+	}
+	if _, err := backend.Ler(novo); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("erro ao ler %s: %w", novo, err)
+	}
+	if err := backend.Gravar(novo, conteudo); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", novo, err)
+	}
+	return backend.Remover(antigo)
+}
@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Conteúdo cifrado:
+//
+//	"TDAHCRY1" | sal (16 bytes) | nonce (12 bytes) | dados em AES-256-GCM
+//
+// O sal vai junto para que um arquivo cifrado com a mesma senha em outro
+// workspace (ex: uma exportação) também possa ser aberto.
+const (
+	cabecalhoCifra = "TDAHCRY1"
+	tamanhoSal     = 16
+)
+
+// Parâmetros do scrypt recomendados para uso interativo (~32 MB de memória)
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// textoVerificador é cifrado junto com a configuração para conferir a senha
+var textoVerificador = []byte("tdah-organizer")
+
+// ErrBloqueado indica que os dados são cifrados e a senha ainda não foi informada
+var ErrBloqueado = errors.New("dados protegidos por senha: desbloqueie o app antes de continuar")
+
+// ErrSenhaIncorreta indica que a senha não abre os dados
+var ErrSenhaIncorreta = errors.New("senha incorreta")
+
+// Cifra cifra e decifra conteúdos com AES-256-GCM, usando uma chave derivada
+// da senha por scrypt. Ela nasce bloqueada: até Desbloquear, Cifrar e
+// Decifrar retornam ErrBloqueado.
+type Cifra struct {
+	mu     sync.RWMutex
+	sal    []byte
+	senha  []byte
+	chaves map[string]cipher.AEAD // sal → chave já derivada
+}
+
+// NovaCifra cria uma cifra bloqueada que cifra com o sal informado
+func NovaCifra(sal []byte) *Cifra {
+	return &Cifra{sal: sal, chaves: map[string]cipher.AEAD{}}
+}
+
+// NovoSal gera um sal aleatório para uma cifra nova
+func NovoSal() ([]byte, error) {
+	sal := make([]byte, tamanhoSal)
+	_, err := rand.Read(sal)
+	return sal, err
+}
+
+// Desbloquear informa a senha. Com um verificador (gerado por Verificador)
+// a senha é conferida antes de ser aceita; sem ele é aceita como está, o que
+// só faz sentido ao criar a cifra.
+func (c *Cifra) Desbloquear(senha string, verificador []byte) error {
+	if senha == "" {
+		return fmt.Errorf("a senha não pode ser vazia")
+	}
+	aead, err := derivarChave([]byte(senha), c.sal)
+	if err != nil {
+		return err
+	}
+	if verificador != nil {
+		texto, err := abrirCifrado(verificador, func(sal []byte) (cipher.AEAD, error) {
+			if !bytes.Equal(sal, c.sal) {
+				return nil, ErrSenhaIncorreta
+			}
+			return aead, nil
+		})
+		if err != nil || !bytes.Equal(texto, textoVerificador) {
+			return ErrSenhaIncorreta
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.senha = []byte(senha)
+	c.chaves = map[string]cipher.AEAD{string(c.sal): aead}
+	return nil
+}
+
+// Bloqueada informa se a senha ainda não foi informada
+func (c *Cifra) Bloqueada() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.senha == nil
+}
+
+// Verificador cifra um texto conhecido, guardado para conferir a senha
+func (c *Cifra) Verificador() ([]byte, error) {
+	return c.Cifrar(textoVerificador)
+}
+
+// Cifrar cifra o conteúdo com a chave do sal da cifra
+func (c *Cifra) Cifrar(dados []byte) ([]byte, error) {
+	aead, err := c.chave(c.sal)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	saida := make([]byte, 0, len(cabecalhoCifra)+len(c.sal)+len(nonce)+len(dados)+aead.Overhead())
+	saida = append(saida, cabecalhoCifra...)
+	saida = append(saida, c.sal...)
+	saida = append(saida, nonce...)
+	return aead.Seal(saida, nonce, dados, []byte(cabecalhoCifra)), nil
+}
+
+// Decifrar abre um conteúdo gerado por Cifrar, com esta ou outra chave
+// derivada da mesma senha
+func (c *Cifra) Decifrar(dados []byte) ([]byte, error) {
+	return abrirCifrado(dados, c.chave)
+}
+
+// TamanhoCabecalho é quantos bytes do início de um conteúdo cifrado bastam
+// para Cifrado e Propria
+const TamanhoCabecalho = len(cabecalhoCifra) + tamanhoSal
+
+// Cifrado informa se o conteúdo foi gerado por Cifrar
+func Cifrado(dados []byte) bool {
+	return bytes.HasPrefix(dados, []byte(cabecalhoCifra))
+}
+
+// Propria informa se o conteúdo foi cifrado com o sal desta cifra, ou seja,
+// se não precisa ser cifrado de novo. Basta o início do conteúdo.
+func (c *Cifra) Propria(dados []byte) bool {
+	return Cifrado(dados) && len(dados) >= TamanhoCabecalho &&
+		bytes.Equal(dados[len(cabecalhoCifra):TamanhoCabecalho], c.sal)
+}
+
+// chave retorna a chave de um sal, derivando-a da senha na primeira vez
+func (c *Cifra) chave(sal []byte) (cipher.AEAD, error) {
+	c.mu.RLock()
+	aead, ok := c.chaves[string(sal)]
+	senha := c.senha
+	c.mu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+	if senha == nil {
+		return nil, ErrBloqueado
+	}
+
+	aead, err := derivarChave(senha, sal)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.chaves[string(sal)] = aead
+	c.mu.Unlock()
+	return aead, nil
+}
+
+func derivarChave(senha, sal []byte) (cipher.AEAD, error) {
+	chave, err := scrypt.Key(senha, sal, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	bloco, err := aes.NewCipher(chave)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(bloco)
+}
+
+func abrirCifrado(dados []byte, chave func(sal []byte) (cipher.AEAD, error)) ([]byte, error) {
+	if !Cifrado(dados) || len(dados) < len(cabecalhoCifra)+tamanhoSal {
+		return nil, fmt.Errorf("conteúdo não está cifrado")
+	}
+	resto := dados[len(cabecalhoCifra):]
+	sal, resto := resto[:tamanhoSal], resto[tamanhoSal:]
+
+	aead, err := chave(sal)
+	if err != nil {
+		return nil, err
+	}
+	if len(resto) < aead.NonceSize() {
+		return nil, fmt.Errorf("conteúdo cifrado incompleto")
+	}
+	nonce, resto := resto[:aead.NonceSize()], resto[aead.NonceSize():]
+	texto, err := aead.Open(nil, nonce, resto, []byte(cabecalhoCifra))
+	if err != nil {
+		// Senha diferente ou conteúdo alterado: o GCM não distingue
+		return nil, ErrSenhaIncorreta
+	}
+	return texto, nil
+}
+
+// BackendCifrado guarda os documentos de outro backend cifrados. Cada
+// documento vira {"cifrado": "<base64>"}, que continua sendo JSON válido
+// para qualquer backend (o SQLite exige JSON).
+type BackendCifrado struct {
+	Backend
+	cifra   *Cifra
+	emClaro bool // só decifra: grava os documentos em claro
+}
+
+// documentoCifrado é como um documento cifrado é gravado no backend
+type documentoCifrado struct {
+	Cifrado []byte `json:"cifrado"`
+}
+
+// NewBackendCifrado cifra os documentos de backend com a cifra informada
+func NewBackendCifrado(backend Backend, cifra *Cifra) *BackendCifrado {
+	return &BackendCifrado{Backend: backend, cifra: cifra}
+}
+
+// NewBackendDecifrando lê documentos cifrados ou em claro, mas grava sempre
+// em claro. Usado ao desligar a criptografia, enquanto os documentos ainda
+// cifrados não foram regravados.
+func NewBackendDecifrando(backend Backend, cifra *Cifra) *BackendCifrado {
+	return &BackendCifrado{Backend: backend, cifra: cifra, emClaro: true}
+}
+
+// Interno retorna o backend que guarda os documentos já cifrados
+func (b *BackendCifrado) Interno() Backend {
+	return b.Backend
+}
+
+// Ler decifra o documento. Documentos ainda em claro (gravados antes de a
+// criptografia ser ativada) são retornados como estão.
+func (b *BackendCifrado) Ler(nome string) ([]byte, error) {
+	conteudo, err := b.Backend.Ler(nome)
+	if err != nil {
+		return nil, err
+	}
+	cifrado, ok := extrairCifrado(conteudo)
+	if !ok {
+		return conteudo, nil
+	}
+	dados, err := b.cifra.Decifrar(cifrado)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", nome, err)
+	}
+	return dados, nil
+}
+
+// Gravar cifra o documento antes de gravá-lo
+func (b *BackendCifrado) Gravar(nome string, conteudo []byte) error {
+	if b.emClaro {
+		return b.Backend.Gravar(nome, conteudo)
+	}
+	cifrado, err := b.cifra.Cifrar(conteudo)
+	if err != nil {
+		return err
+	}
+	documento, err := json.Marshal(documentoCifrado{Cifrado: cifrado})
+	if err != nil {
+		return err
+	}
+	return b.Backend.Gravar(nome, documento)
+}
+
+// Fechar fecha o backend interno, se ele mantiver recursos abertos
+func (b *BackendCifrado) Fechar() error {
+	if f, ok := b.Backend.(interface{ Fechar() error }); ok {
+		return f.Fechar()
+	}
+	return nil
+}
+
+// extrairCifrado reconhece um documento gravado por BackendCifrado. O
+// SQLite devolve o documento dentro de um envelope, que é aberto antes.
+func extrairCifrado(conteudo []byte) ([]byte, bool) {
+	dados, _ := abrirEnvelope(conteudo)
+	var d documentoCifrado
+	if err := json.Unmarshal(dados, &d); err != nil || !Cifrado(d.Cifrado) {
+		return nil, false
+	}
+	return d.Cifrado, true
+}
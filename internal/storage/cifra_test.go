@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cifraTeste cria uma cifra já desbloqueada com a senha
+func cifraTeste(t *testing.T, senha string) *Cifra {
+	t.Helper()
+	sal, err := NovoSal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NovaCifra(sal)
+	if err := c.Desbloquear(senha, nil); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCifraIdaEVolta(t *testing.T) {
+	c := cifraTeste(t, "senha certa")
+	texto := []byte(`{"dados":[{"id":"1","titulo":"ação"}]}`)
+
+	cifrado, err := c.Cifrar(texto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(cifrado, []byte("titulo")) {
+		t.Error("o conteúdo cifrado tem o texto em claro")
+	}
+	outro, _ := c.Cifrar(texto)
+	if bytes.Equal(cifrado, outro) {
+		t.Error("cifrar duas vezes deu o mesmo resultado (nonce repetido)")
+	}
+	decifrado, err := c.Decifrar(cifrado)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decifrado, texto) {
+		t.Errorf("decifrado = %s", decifrado)
+	}
+
+	// A mesma senha abre conteúdos cifrados com outro sal (ex: uma exportação
+	// de outro workspace)
+	deOutroWorkspace, err := cifraTeste(t, "senha certa").Cifrar(texto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decifrado, err := c.Decifrar(deOutroWorkspace); err != nil || !bytes.Equal(decifrado, texto) {
+		t.Errorf("decifrar com outro sal = %s, %v", decifrado, err)
+	}
+}
+
+func TestCifraSenhaErrada(t *testing.T) {
+	certa := cifraTeste(t, "senha certa")
+	verificador, err := certa.Verificador()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cifrado, err := certa.Cifrar([]byte("segredo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reaberta, a cifra nasce bloqueada
+	reaberta := NovaCifra(certa.sal)
+	if !reaberta.Bloqueada() {
+		t.Error("cifra nova deveria estar bloqueada")
+	}
+	if _, err := reaberta.Decifrar(cifrado); !errors.Is(err, ErrBloqueado) {
+		t.Errorf("decifrar bloqueada = %v, esperado ErrBloqueado", err)
+	}
+	if _, err := reaberta.Cifrar([]byte("x")); !errors.Is(err, ErrBloqueado) {
+		t.Errorf("cifrar bloqueada = %v, esperado ErrBloqueado", err)
+	}
+	if err := reaberta.Desbloquear("senha errada", verificador); !errors.Is(err, ErrSenhaIncorreta) {
+		t.Errorf("desbloquear com a senha errada = %v", err)
+	}
+	if !reaberta.Bloqueada() {
+		t.Error("a senha errada desbloqueou a cifra")
+	}
+	if err := reaberta.Desbloquear("senha certa", verificador); err != nil {
+		t.Fatal(err)
+	}
+	if texto, err := reaberta.Decifrar(cifrado); err != nil || string(texto) != "segredo" {
+		t.Errorf("decifrado = %q, %v", texto, err)
+	}
+
+	// Sem verificador a senha é aceita, mas não abre o conteúdo
+	errada := NovaCifra(certa.sal)
+	if err := errada.Desbloquear("senha errada", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := errada.Decifrar(cifrado); !errors.Is(err, ErrSenhaIncorreta) {
+		t.Errorf("decifrar com a senha errada = %v", err)
+	}
+	// Conteúdo alterado também não abre
+	alterado := bytes.Clone(cifrado)
+	alterado[len(alterado)-1] ^= 1
+	if _, err := certa.Decifrar(alterado); !errors.Is(err, ErrSenhaIncorreta) {
+		t.Errorf("decifrar conteúdo alterado = %v", err)
+	}
+}
+
+func TestCifraCabecalho(t *testing.T) {
+	c := cifraTeste(t, "senha")
+	outra := cifraTeste(t, "senha")
+	cifrado, err := c.Cifrar([]byte("conteúdo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	casos := []struct {
+		nome    string
+		dados   []byte
+		cifrado bool
+		propria bool
+	}{
+		{"cifrado por esta cifra", cifrado, true, true},
+		{"só o início basta", cifrado[:TamanhoCabecalho], true, true},
+		{"cabeçalho incompleto", cifrado[:TamanhoCabecalho-1], true, false},
+		{"em claro", []byte(`{"schemaVersion":1,"dados":[]}`), false, false},
+		{"vazio", nil, false, false},
+	}
+	for _, c2 := range casos {
+		if got := Cifrado(c2.dados); got != c2.cifrado {
+			t.Errorf("%s: Cifrado = %v", c2.nome, got)
+		}
+		if got := c.Propria(c2.dados); got != c2.propria {
+			t.Errorf("%s: Propria = %v", c2.nome, got)
+		}
+	}
+	// Com outro sal precisa ser cifrado de novo, mesmo com a mesma senha
+	if outra.Propria(cifrado) {
+		t.Error("Propria com outro sal deveria ser falso")
+	}
+	if _, err := c.Decifrar([]byte(`{"dados":[]}`)); err == nil {
+		t.Error("decifrar conteúdo em claro deveria falhar")
+	}
+}
+
+func TestBackendCifrado(t *testing.T) {
+	c := cifraTeste(t, "senha")
+	interno := NewBackendArquivos(filepath.Join(t.TempDir(), "init"))
+	backend := NewBackendCifrado(interno, c)
+
+	// Documento gravado antes da criptografia é lido como está
+	emClaro := []byte(`{"schemaVersion":1,"dados":[{"id":"antigo"}]}`)
+	if err := interno.Gravar("passos_data.json", emClaro); err != nil {
+		t.Fatal(err)
+	}
+	if lido, err := backend.Ler("passos_data.json"); err != nil || !bytes.Equal(lido, emClaro) {
+		t.Errorf("documento em claro = %s, %v", lido, err)
+	}
+
+	texto := []byte(`{"schemaVersion":1,"dados":[{"id":"1","url":"https://exemplo.com"}]}`)
+	if err := backend.Gravar("links_data.json", texto); err != nil {
+		t.Fatal(err)
+	}
+	bruto, err := interno.Ler("links_data.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(bruto), `{"cifrado":`) || bytes.Contains(bruto, []byte("exemplo.com")) {
+		t.Errorf("gravado no backend interno: %s", bruto)
+	}
+	if lido, err := backend.Ler("links_data.json"); err != nil || !bytes.Equal(lido, texto) {
+		t.Errorf("lido = %s, %v", lido, err)
+	}
+
+	// Ao desligar a criptografia: lê o cifrado, grava em claro
+	decifrando := NewBackendDecifrando(interno, c)
+	lido, err := decifrando.Ler("links_data.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decifrando.Gravar("links_data.json", lido); err != nil {
+		t.Fatal(err)
+	}
+	if bruto, _ := interno.Ler("links_data.json"); !bytes.Equal(bruto, texto) {
+		t.Errorf("regravado em claro: %s", bruto)
+	}
+}
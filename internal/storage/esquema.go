@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Migracao converte os dados brutos de uma versão do esquema para a seguinte
+type Migracao func(dados json.RawMessage) (json.RawMessage, error)
+
+// Esquema descreve a evolução do formato de um arquivo de dados.
+// Migracoes[i] leva os dados da versão i para a versão i+1, então a versão
+// atual é len(Migracoes). A versão 0 é o formato antigo, sem envelope.
+type Esquema struct {
+	Migracoes []Migracao
+}
+
+// Versao retorna a versão atual do esquema
+func (e Esquema) Versao() int {
+	return len(e.Migracoes)
+}
+
+// SemAlteracao é a migração usada quando só o envelope muda
+func SemAlteracao(dados json.RawMessage) (json.RawMessage, error) {
+	return dados, nil
+}
+
+// envelope é o formato gravado em disco
+type envelope struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Dados         json.RawMessage `json:"dados"`
+}
+
+// ErroJSON indica que o conteúdo do arquivo não é JSON válido
+type ErroJSON struct {
+	Err error
+}
+
+func (e *ErroJSON) Error() string {
+	return "JSON inválido: " + e.Err.Error()
+}
+
+func (e *ErroJSON) Unwrap() error {
+	return e.Err
+}
+
+// Migrar abre o envelope (ou aceita o formato antigo) e aplica as migrações
+// pendentes, retornando os dados na versão atual
+func (e Esquema) Migrar(conteudo []byte) (json.RawMessage, error) {
+	if !json.Valid(conteudo) {
+		var v interface{}
+		return nil, &ErroJSON{Err: json.Unmarshal(conteudo, &v)}
+	}
+
+	dados, versao := abrirEnvelope(conteudo)
+	if versao > e.Versao() {
+		return nil, fmt.Errorf("dados na versão %d do esquema, mais nova que a suportada (%d)", versao, e.Versao())
+	}
+
+	for v := versao; v < e.Versao(); v++ {
+		migrado, err := e.Migracoes[v](dados)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao migrar dados da versão %d para %d: %w", v, v+1, err)
+		}
+		dados = migrado
+	}
+	return dados, nil
+}
+
+// Envelopar embrulha os dados com a versão atual do esquema
+func (e Esquema) Envelopar(dados json.RawMessage) ([]byte, error) {
+	return json.MarshalIndent(envelope{
+		SchemaVersion: e.Versao(),
+		Dados:         dados,
+	}, "", "  ")
+}
+
+// Decodificar lê o conteúdo de um arquivo de dados (de qualquer versão) para T
+func Decodificar[T any](conteudo []byte, esquema Esquema) (T, error) {
+	var valor T
+	dados, err := esquema.Migrar(conteudo)
+	if err != nil {
+		return valor, err
+	}
+	if err := json.Unmarshal(dados, &valor); err != nil {
+		return valor, &ErroJSON{Err: err}
+	}
+	return valor, nil
+}
+
+// abrirEnvelope separa versão e dados; arquivos sem envelope são versão 0
+func abrirEnvelope(conteudo []byte) (json.RawMessage, int) {
+	trimmed := bytes.TrimSpace(conteudo)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var campos map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &campos); err == nil {
+			if _, ok := campos["schemaVersion"]; ok {
+				var env envelope
+				if err := json.Unmarshal(trimmed, &env); err == nil {
+					return env.Dados, env.SchemaVersion
+				}
+			}
+		}
+	}
+	return trimmed, 0
+}
@@ -0,0 +1,36 @@
+package storage
+
+import "errors"
+
+// ErrSomenteLeitura indica uma alteração num backend aberto só para leitura
+var ErrSomenteLeitura = errors.New("dados abertos só para leitura")
+
+// BackendSomenteLeitura lê os documentos de outro backend e recusa qualquer
+// alteração, inclusive colocar em quarentena um documento ilegível. A linha
+// de comando o usa para consultar os dados enquanto outro processo (a janela
+// do app) pode estar gravando neles.
+type BackendSomenteLeitura struct {
+	Backend
+}
+
+// NewBackendSomenteLeitura abre backend só para leitura
+func NewBackendSomenteLeitura(backend Backend) *BackendSomenteLeitura {
+	return &BackendSomenteLeitura{Backend: backend}
+}
+
+// Gravar, Remover, Quarentenar e DescartarQuarentena recusam a alteração
+func (b *BackendSomenteLeitura) Gravar(nome string, conteudo []byte) error {
+	return ErrSomenteLeitura
+}
+
+func (b *BackendSomenteLeitura) Remover(nome string) error {
+	return ErrSomenteLeitura
+}
+
+func (b *BackendSomenteLeitura) Quarentenar(nome string) (string, error) {
+	return "", ErrSomenteLeitura
+}
+
+func (b *BackendSomenteLeitura) DescartarQuarentena(nome string) error {
+	return ErrSomenteLeitura
+}
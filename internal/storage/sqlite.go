@@ -0,0 +1,463 @@
+package storage
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // driver SQLite em Go puro (sem cgo)
+)
+
+// BackendSQLite guarda os documentos em um banco SQLite.
+// Documentos que são listas ficam com um item por linha, identificado pelo
+// "id" do item, e gravar só reescreve as linhas que mudaram, em vez do
+// arquivo inteiro. Itens sem "id" (ou com id repetido) são identificados pela
+// posição, então inserir um deles no começo reescreve os seguintes.
+type BackendSQLite struct {
+	db      *sql.DB
+	caminho string
+}
+
+// versaoSQLite é gravada em PRAGMA user_version. Bancos na versão 0 guardam
+// os itens por posição e são convertidos ao abrir.
+const versaoSQLite = 1
+
+const esquemaSQLite = `
+CREATE TABLE IF NOT EXISTS documentos (
+	nome           TEXT PRIMARY KEY,
+	schema_version INTEGER NOT NULL,
+	lista          INTEGER NOT NULL,
+	conteudo       BLOB,
+	ordem          BLOB
+);
+CREATE TABLE IF NOT EXISTS itens (
+	nome     TEXT NOT NULL,
+	chave    TEXT NOT NULL,
+	conteudo BLOB NOT NULL,
+	PRIMARY KEY (nome, chave)
+);
+CREATE TABLE IF NOT EXISTS quarentena (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	nome      TEXT NOT NULL,
+	criado_em TEXT NOT NULL,
+	conteudo  BLOB
+);
+`
+
+// NewBackendSQLite abre (ou cria) o banco no caminho informado
+func NewBackendSQLite(caminho string) (*BackendSQLite, error) {
+	dsn := "file:" + (&url.URL{Path: caminho}).EscapedPath() +
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(FULL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// Uma conexão só: as travas por documento já serializam as escritas
+	db.SetMaxOpenConns(1)
+
+	if err := prepararSQLite(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("erro ao preparar banco %s: %w", caminho, err)
+	}
+	return &BackendSQLite{db: db, caminho: caminho}, nil
+}
+
+// prepararSQLite cria as tabelas e converte bancos da versão 0, que
+// guardavam os itens por posição
+func prepararSQLite(db *sql.DB) error {
+	var versao int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&versao); err != nil {
+		return err
+	}
+	if versao >= versaoSQLite {
+		_, err := db.Exec(esquemaSQLite)
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var antigos int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('itens') WHERE name = 'posicao'`).Scan(&antigos); err != nil {
+		return err
+	}
+	if antigos > 0 {
+		if _, err := tx.Exec(`ALTER TABLE itens RENAME TO itens_v0`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`ALTER TABLE documentos ADD COLUMN ordem BLOB`); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(esquemaSQLite); err != nil {
+		return err
+	}
+	if antigos > 0 {
+		if err := converterItensV0(tx); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, versaoSQLite)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// converterItensV0 regrava as listas da tabela antiga (por posição) na nova
+func converterItensV0(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT nome FROM documentos WHERE lista = 1`)
+	if err != nil {
+		return err
+	}
+	var nomes []string
+	for rows.Next() {
+		var nome string
+		if err := rows.Scan(&nome); err != nil {
+			rows.Close()
+			return err
+		}
+		nomes = append(nomes, nome)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, nome := range nomes {
+		rows, err := tx.Query(`SELECT conteudo FROM itens_v0 WHERE nome = ? ORDER BY posicao`, nome)
+		if err != nil {
+			return err
+		}
+		itens := []json.RawMessage{}
+		for rows.Next() {
+			var item []byte
+			if err := rows.Scan(&item); err != nil {
+				rows.Close()
+				return err
+			}
+			itens = append(itens, item)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if err := gravarItens(tx, nome, itens); err != nil {
+			return fmt.Errorf("erro ao converter %s: %w", nome, err)
+		}
+	}
+	_, err = tx.Exec(`DROP TABLE itens_v0`)
+	return err
+}
+
+// Fechar fecha o banco
+func (b *BackendSQLite) Fechar() error {
+	return b.db.Close()
+}
+
+// Chave usa o caminho do banco e o nome do documento
+func (b *BackendSQLite) Chave(nome string) string {
+	return "sqlite:" + b.caminho + "#" + nome
+}
+
+// Ler monta o documento (em envelope) a partir das tabelas
+func (b *BackendSQLite) Ler(nome string) ([]byte, error) {
+	var versao int
+	var lista bool
+	var conteudo, ordem []byte
+	err := b.db.QueryRow(`SELECT schema_version, lista, conteudo, ordem FROM documentos WHERE nome = ?`, nome).
+		Scan(&versao, &lista, &conteudo, &ordem)
+	if err == sql.ErrNoRows {
+		return nil, os.ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if lista {
+		conteudo, err = b.lerItens(nome, ordem)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(envelope{SchemaVersion: versao, Dados: conteudo})
+}
+
+// lerItens monta a lista na ordem gravada em documentos.ordem
+func (b *BackendSQLite) lerItens(nome string, ordem []byte) ([]byte, error) {
+	var chaves []string
+	if len(ordem) > 0 {
+		if err := json.Unmarshal(ordem, &chaves); err != nil {
+			return nil, fmt.Errorf("ordem inválida em %s: %w", nome, err)
+		}
+	}
+
+	rows, err := b.db.Query(`SELECT chave, conteudo FROM itens WHERE nome = ?`, nome)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	itens := make(map[string][]byte)
+	for rows.Next() {
+		var chave string
+		var item []byte
+		if err := rows.Scan(&chave, &item); err != nil {
+			return nil, err
+		}
+		itens[chave] = item
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, chave := range chaves {
+		item, ok := itens[chave]
+		if !ok {
+			return nil, fmt.Errorf("item %s ausente em %s", chave, nome)
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(item)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// Gravar grava o documento em uma transação. Listas são gravadas item a
+// item, atualizando só os itens cujo conteúdo mudou.
+func (b *BackendSQLite) Gravar(nome string, conteudo []byte) error {
+	if !json.Valid(conteudo) {
+		return fmt.Errorf("conteúdo inválido para %s", nome)
+	}
+	dados, versao := abrirEnvelope(conteudo)
+
+	var itens []json.RawMessage
+	lista := json.Unmarshal(dados, &itens) == nil
+
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if lista {
+		_, err = tx.Exec(`INSERT INTO documentos (nome, schema_version, lista) VALUES (?, ?, 1)
+			ON CONFLICT(nome) DO UPDATE SET schema_version = excluded.schema_version, lista = 1, conteudo = NULL`,
+			nome, versao)
+		if err == nil {
+			err = gravarItens(tx, nome, itens)
+		}
+	} else {
+		var compacto bytes.Buffer
+		if err = json.Compact(&compacto, dados); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO documentos (nome, schema_version, lista, conteudo) VALUES (?, ?, 0, ?)
+			ON CONFLICT(nome) DO UPDATE SET schema_version = excluded.schema_version, lista = 0,
+				conteudo = excluded.conteudo, ordem = NULL`,
+			nome, versao, compacto.Bytes())
+		if err == nil {
+			_, err = tx.Exec(`DELETE FROM itens WHERE nome = ?`, nome)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// gravarItens grava os itens da lista pela chave de cada um: insere os
+// novos, atualiza os alterados, apaga os removidos e regrava a ordem
+func gravarItens(tx *sql.Tx, nome string, itens []json.RawMessage) error {
+	// Conteúdo atual de cada item, para comparar
+	atuais := make(map[string][]byte)
+	rows, err := tx.Query(`SELECT chave, conteudo FROM itens WHERE nome = ?`, nome)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var chave string
+		var item []byte
+		if err := rows.Scan(&chave, &item); err != nil {
+			rows.Close()
+			return err
+		}
+		atuais[chave] = item
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	chaves := chavesItens(itens)
+	for i, item := range itens {
+		var compacto bytes.Buffer
+		if err := json.Compact(&compacto, item); err != nil {
+			return err
+		}
+		atual, existe := atuais[chaves[i]]
+		delete(atuais, chaves[i])
+		if existe && bytes.Equal(atual, compacto.Bytes()) {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO itens (nome, chave, conteudo) VALUES (?, ?, ?)
+			ON CONFLICT(nome, chave) DO UPDATE SET conteudo = excluded.conteudo`,
+			nome, chaves[i], compacto.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	// O que sobrou em atuais saiu da lista
+	for chave := range atuais {
+		if _, err := tx.Exec(`DELETE FROM itens WHERE nome = ? AND chave = ?`, nome, chave); err != nil {
+			return err
+		}
+	}
+
+	ordem, err := json.Marshal(chaves)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE documentos SET ordem = ? WHERE nome = ?`, ordem, nome)
+	return err
+}
+
+// chavesItens identifica cada item pelo campo "id". Itens sem id, ou com um
+// id que já apareceu antes na lista, são identificados pela posição.
+func chavesItens(itens []json.RawMessage) []string {
+	chaves := make([]string, len(itens))
+	usadas := make(map[string]bool, len(itens))
+	for i, item := range itens {
+		var campos struct {
+			ID json.RawMessage `json:"id"`
+		}
+		chave := ""
+		if json.Unmarshal(item, &campos) == nil && len(campos.ID) > 0 && string(campos.ID) != "null" {
+			var compacto bytes.Buffer
+			if json.Compact(&compacto, campos.ID) == nil {
+				chave = "id:" + compacto.String()
+			}
+		}
+		if chave == "" || usadas[chave] {
+			chave = fmt.Sprintf("pos:%d", i)
+		}
+		usadas[chave] = true
+		chaves[i] = chave
+	}
+	return chaves
+}
+
+// Listar retorna os nomes dos documentos *_data.json gravados, como
+// BackendArquivos.Listar
+func (b *BackendSQLite) Listar() ([]string, error) {
+	rows, err := b.db.Query(`SELECT nome FROM documentos WHERE nome LIKE ? ESCAPE '\' ORDER BY nome`,
+		"%"+strings.ReplaceAll(sufixoDados, "_", `\_`))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nomes := []string{}
+	for rows.Next() {
+		var nome string
+		if err := rows.Scan(&nome); err != nil {
+			return nil, err
+		}
+		nomes = append(nomes, nome)
+	}
+	return nomes, rows.Err()
+}
+
+// Remover apaga o documento e seus itens
+func (b *BackendSQLite) Remover(nome string) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM itens WHERE nome = ?`, nome); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM documentos WHERE nome = ?`, nome); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Quarentenar move o documento para a tabela de quarentena
+func (b *BackendSQLite) Quarentenar(nome string) (string, error) {
+	conteudo, err := b.Ler(nome)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := b.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	ts := time.Now().Format("2006-01-02_15-04-05")
+	if _, err := tx.Exec(`INSERT INTO quarentena (nome, criado_em, conteudo) VALUES (?, ?, ?)`, nome, ts, conteudo); err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(`DELETE FROM itens WHERE nome = ?`, nome); err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(`DELETE FROM documentos WHERE nome = ?`, nome); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return "quarentena:" + nome + sufixoQuarentena + ts, nil
+}
+
+// Bloqueado informa se o documento está só na quarentena
+func (b *BackendSQLite) Bloqueado(nome string) (string, bool) {
+	var ts string
+	err := b.db.QueryRow(`SELECT criado_em FROM quarentena
+		WHERE nome = ? AND nome NOT IN (SELECT nome FROM documentos)
+		ORDER BY id DESC LIMIT 1`, nome).Scan(&ts)
+	if err != nil {
+		return "", false
+	}
+	return "quarentena:" + nome + sufixoQuarentena + ts, true
+}
+
+// ListarBloqueados retorna os documentos em quarentena sem versão válida
+func (b *BackendSQLite) ListarBloqueados() ([]string, error) {
+	rows, err := b.db.Query(`SELECT DISTINCT nome FROM quarentena
+		WHERE nome NOT IN (SELECT nome FROM documentos) ORDER BY nome`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nomes := []string{}
+	for rows.Next() {
+		var nome string
+		if err := rows.Scan(&nome); err != nil {
+			return nil, err
+		}
+		nomes = append(nomes, nome)
+	}
+	return nomes, rows.Err()
+}
+
+// DescartarQuarentena apaga as cópias em quarentena do documento
+func (b *BackendSQLite) DescartarQuarentena(nome string) error {
+	_, err := b.db.Exec(`DELETE FROM quarentena WHERE nome = ?`, nome)
+	return err
+}
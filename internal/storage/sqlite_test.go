@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func abrirSQLiteTeste(t *testing.T, caminho string) *BackendSQLite {
+	t.Helper()
+	b, err := NewBackendSQLite(caminho)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { b.Fechar() })
+	return b
+}
+
+// lerLista devolve os dados do envelope gravado em nome
+func lerLista(t *testing.T, b Backend, nome string) []map[string]any {
+	t.Helper()
+	conteudo, err := b.Ler(nome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env struct {
+		Dados []map[string]any `json:"dados"`
+	}
+	if err := json.Unmarshal(conteudo, &env); err != nil {
+		t.Fatal(err)
+	}
+	return env.Dados
+}
+
+func TestSQLiteListaPorID(t *testing.T) {
+	b := abrirSQLiteTeste(t, filepath.Join(t.TempDir(), "dados.db"))
+	gravar := func(dados string) {
+		t.Helper()
+		if err := b.Gravar("links_data.json", []byte(`{"schemaVersion":1,"dados":`+dados+`}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gravar(`[{"id":"a","n":1},{"id":"b","n":2}]`)
+	// Antes de a, sem alterar b: a linha de b continua a mesma
+	var antes int64
+	b.db.QueryRow(`SELECT rowid FROM itens WHERE chave = 'id:"b"'`).Scan(&antes)
+	gravar(`[{"id":"c","n":3},{"id":"a","n":1},{"id":"b","n":2}]`)
+	var depois int64
+	b.db.QueryRow(`SELECT rowid FROM itens WHERE chave = 'id:"b"'`).Scan(&depois)
+	if antes == 0 || antes != depois {
+		t.Errorf("item b regravado ao inserir no começo (rowid %d → %d)", antes, depois)
+	}
+
+	gravar(`[{"id":"c","n":3},{"id":"b","n":20},{"x":1},{"x":2}]`)
+	esperado := []map[string]any{{"id": "c", "n": 3.0}, {"id": "b", "n": 20.0}, {"x": 1.0}, {"x": 2.0}}
+	if lidos := lerLista(t, b, "links_data.json"); !reflect.DeepEqual(lidos, esperado) {
+		t.Errorf("lido %v, esperado %v", lidos, esperado)
+	}
+	var linhas int
+	b.db.QueryRow(`SELECT COUNT(*) FROM itens WHERE nome = 'links_data.json'`).Scan(&linhas)
+	if linhas != 4 {
+		t.Errorf("%d linhas na tabela, esperado 4", linhas)
+	}
+}
+
+func TestSQLiteListarSoDados(t *testing.T) {
+	b := abrirSQLiteTeste(t, filepath.Join(t.TempDir(), "dados.db"))
+	for _, nome := range []string{"links_data.json", "outro.json", "ideiasXdata.json"} {
+		if err := b.Gravar(nome, []byte(`{"schemaVersion":1,"dados":{}}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	nomes, err := b.Listar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nomes, []string{"links_data.json"}) {
+		t.Errorf("Listar = %v", nomes)
+	}
+}
+
+func TestSQLiteConverteVersao0(t *testing.T) {
+	caminho := filepath.Join(t.TempDir(), "dados.db")
+	db, err := sql.Open("sqlite", "file:"+caminho)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`
+CREATE TABLE documentos (nome TEXT PRIMARY KEY, schema_version INTEGER NOT NULL, lista INTEGER NOT NULL, conteudo BLOB);
+CREATE TABLE itens (nome TEXT NOT NULL, posicao INTEGER NOT NULL, conteudo BLOB NOT NULL, PRIMARY KEY (nome, posicao));
+CREATE TABLE quarentena (id INTEGER PRIMARY KEY AUTOINCREMENT, nome TEXT NOT NULL, criado_em TEXT NOT NULL, conteudo BLOB);
+INSERT INTO documentos VALUES ('passos_data.json', 1, 1, NULL);
+INSERT INTO itens VALUES ('passos_data.json', 1, '{"id":"y"}'), ('passos_data.json', 0, '{"id":"x"}');
+`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := abrirSQLiteTeste(t, caminho)
+	esperado := []map[string]any{{"id": "x"}, {"id": "y"}}
+	if lidos := lerLista(t, b, "passos_data.json"); !reflect.DeepEqual(lidos, esperado) {
+		t.Errorf("lido %v, esperado %v", lidos, esperado)
+	}
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Store persiste um valor do tipo T como um documento JSON de um Backend
+type Store[T any] struct {
+	mu         sync.RWMutex // protege backend contra troca durante uma operação
+	backend    Backend
+	nome       string
+	esquema    Esquema
+	vazio      func() T
+	normalizar func(*T)
+}
+
+// NewStore cria um store para o documento informado.
+// esquema define as migrações aplicadas ao carregar, vazio retorna o valor
+// usado quando o documento ainda não existe e normalizar (opcional) é
+// aplicado em todo valor carregado ou salvo.
+func NewStore[T any](backend Backend, nome string, esquema Esquema, vazio func() T, normalizar func(*T)) *Store[T] {
+	return &Store[T]{
+		backend:    backend,
+		nome:       nome,
+		esquema:    esquema,
+		vazio:      vazio,
+		normalizar: normalizar,
+	}
+}
+
+// NewLista cria um store para uma lista, garantindo que nunca seja nil
+func NewLista[E any](backend Backend, nome string, esquema Esquema) *Store[[]E] {
+	return NewStore(backend, nome, esquema,
+		func() []E { return []E{} },
+		func(lista *[]E) {
+			if *lista == nil {
+				*lista = []E{}
+			}
+		},
+	)
+}
+
+// Nome retorna o nome do documento (ex: links_data.json)
+func (s *Store[T]) Nome() string {
+	return s.nome
+}
+
+// Apontar passa a ler e gravar em outro backend (ex: troca de workspace).
+// Espera as operações em andamento terminarem.
+func (s *Store[T]) Apontar(backend Backend) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backend = backend
+}
+
+// Carregar lê o valor salvo
+func (s *Store[T]) Carregar() (T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	defer Travar(s.backend.Chave(s.nome))()
+	return s.carregar()
+}
+
+// Salvar grava o valor
+func (s *Store[T]) Salvar(valor T) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	defer Travar(s.backend.Chave(s.nome))()
+	return s.salvar(valor)
+}
+
+// Atualizar carrega o valor, aplica fn e salva o resultado, tudo sob a
+// trava do documento: chamadas concorrentes são aplicadas uma após a outra.
+// Se fn retornar erro nada é gravado.
+func (s *Store[T]) Atualizar(fn func(*T) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	defer Travar(s.backend.Chave(s.nome))()
+
+	valor, err := s.carregar()
+	if err != nil {
+		return err
+	}
+	if err := fn(&valor); err != nil {
+		return err
+	}
+	return s.salvar(valor)
+}
+
+func (s *Store[T]) carregar() (T, error) {
+	jsonData, err := s.backend.Ler(s.nome)
+	if errors.Is(err, os.ErrNotExist) {
+		// Documento em quarentena - os dados antigos ainda não foram recuperados
+		if q, bloqueado := s.backend.Bloqueado(s.nome); bloqueado {
+			return s.vazio(), &ErroCorrompido{Nome: s.nome, Quarentena: q}
+		}
+		// Documento não existe - retornar valor vazio (app começa do zero)
+		return s.vazio(), nil
+	}
+	if err != nil {
+		return s.vazio(), err
+	}
+
+	valor, err := Decodificar[T](jsonData, s.esquema)
+	if err != nil {
+		var errJSON *ErroJSON
+		if !errors.As(err, &errJSON) {
+			// Falha de migração: o documento é preservado como está
+			return s.vazio(), fmt.Errorf("%s: %w", s.nome, err)
+		}
+		// Nunca devolver dados parciais: o próximo salvamento apagaria o resto
+		q, errQ := s.backend.Quarentenar(s.nome)
+		if errQ != nil {
+			return s.vazio(), fmt.Errorf("arquivo de dados corrompido: %s: %w", s.nome, err)
+		}
+		return s.vazio(), &ErroCorrompido{Nome: s.nome, Quarentena: q, Err: err}
+	}
+	s.aplicarNormalizacao(&valor)
+	return valor, nil
+}
+
+func (s *Store[T]) salvar(valor T) error {
+	// Não sobrescrever dados que não puderam ser lidos
+	if q, bloqueado := s.backend.Bloqueado(s.nome); bloqueado {
+		return &ErroCorrompido{Nome: s.nome, Quarentena: q}
+	}
+
+	s.aplicarNormalizacao(&valor)
+
+	dados, err := json.Marshal(valor)
+	if err != nil {
+		return err
+	}
+	jsonData, err := s.esquema.Envelopar(dados)
+	if err != nil {
+		return err
+	}
+	return s.backend.Gravar(s.nome, jsonData)
+}
+
+func (s *Store[T]) aplicarNormalizacao(valor *T) {
+	if s.normalizar != nil {
+		s.normalizar(valor)
+	}
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// Uma trava por arquivo, compartilhada por todos que acessam o mesmo caminho
+// (stores, backup, restauração). Os métodos do Wails podem ser chamados em
+// paralelo, então todo carregar → alterar → salvar precisa ser serializado.
+//
+// As travas só valem dentro do processo: não protegem contra outro processo
+// (a linha de comando, um cron) gravando nos mesmos arquivos ao mesmo tempo.
+// Entre processos vale a TravaProcesso da pasta de dados (ver travaprocesso.go).
+var (
+	travasMu sync.Mutex
+	travas   = map[string]*sync.Mutex{}
+)
+
+func travaDe(caminho string) *sync.Mutex {
+	chave, err := filepath.Abs(caminho)
+	if err != nil {
+		chave = filepath.Clean(caminho)
+	}
+
+	travasMu.Lock()
+	defer travasMu.Unlock()
+
+	m, ok := travas[chave]
+	if !ok {
+		m = &sync.Mutex{}
+		travas[chave] = m
+	}
+	return m
+}
+
+// Travar bloqueia o arquivo até que a função retornada seja chamada.
+// Use para acessar um arquivo de dados fora de um Store (ex: restauração).
+func Travar(caminho string) func() {
+	m := travaDe(caminho)
+	m.Lock()
+	return m.Unlock
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrTravaOcupada indica que outro processo segura a trava
+var ErrTravaOcupada = errors.New("trava ocupada por outro processo")
+
+// TravaProcesso é uma trava do sistema operacional sobre um arquivo (flock
+// no Linux e no macOS, LockFileEx no Windows). Ao contrário de Travar, vale
+// entre processos: o app e a linha de comando não alteram a mesma pasta de
+// dados ao mesmo tempo. O sistema solta a trava se o processo morrer.
+type TravaProcesso struct {
+	arquivo *os.File
+}
+
+// TravarProcesso pega a trava do arquivo em caminho (criado se preciso),
+// tentando de novo por até espera se outro processo a segurar. Depois disso
+// retorna ErrTravaOcupada.
+func TravarProcesso(caminho string, espera time.Duration) (*TravaProcesso, error) {
+	f, err := os.OpenFile(caminho, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	limite := time.Now().Add(espera)
+	for {
+		err := travarArquivo(f)
+		if err == nil {
+			return &TravaProcesso{arquivo: f}, nil
+		}
+		if !errors.Is(err, ErrTravaOcupada) || time.Now().After(limite) {
+			f.Close()
+			return nil, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// Soltar libera a trava
+func (t *TravaProcesso) Soltar() error {
+	if t == nil || t.arquivo == nil {
+		return nil
+	}
+	soltarArquivo(t.arquivo)
+	err := t.arquivo.Close()
+	t.arquivo = nil
+	return err
+}
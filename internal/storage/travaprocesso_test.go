@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestTravaProcessoExclusiva(t *testing.T) {
+	caminho := filepath.Join(t.TempDir(), ".trava")
+	primeira, err := TravarProcesso(caminho, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Outro descritor do mesmo arquivo faz o papel de outro processo
+	if _, err := TravarProcesso(caminho, 0); !errors.Is(err, ErrTravaOcupada) {
+		t.Fatalf("segunda trava: erro = %v, esperado ErrTravaOcupada", err)
+	}
+
+	if err := primeira.Soltar(); err != nil {
+		t.Fatal(err)
+	}
+	segunda, err := TravarProcesso(caminho, 0)
+	if err != nil {
+		t.Fatalf("trava solta continua ocupada: %v", err)
+	}
+	segunda.Soltar()
+}
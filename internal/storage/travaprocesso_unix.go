@@ -0,0 +1,22 @@
+//go:build unix
+
+package storage
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func travarArquivo(f *os.File) error {
+	err := unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return ErrTravaOcupada
+	}
+	return err
+}
+
+func soltarArquivo(f *os.File) {
+	unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
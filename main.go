@@ -3,56 +3,89 @@ package main
 import (
 	"context"
 	"embed"
+	"errors"
 	"net/http"
 	"os"
 	"path/filepath"
 
+	"github.com/user/tdah-organizer/internal/api"
 	"github.com/user/tdah-organizer/internal/app"
+	"github.com/user/tdah-organizer/internal/cli"
 	"github.com/user/tdah-organizer/internal/handlers"
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
 func main() {
-	// Criar pasta assets se não existir
-	exePath, err := os.Executable()
+	// Com um subcomando (ex: "tarefa listar"), rodar sem abrir a janela
+	if executado, codigo := cli.Executar(os.Args[1:], os.Stdout, os.Stderr); executado {
+		os.Exit(codigo)
+	}
+
+	// Resolver a pasta de dados (--data-dir, TDAH_DATA_DIR, XDG_DATA_HOME
+	// ou a pasta assets ao lado do executável) e criá-la se não existir
+	dataDir, err := app.ResolverDataDir(os.Args[1:])
 	if err != nil {
 		panic(err)
 	}
-	exeDir := filepath.Dir(exePath)
-	assetsDir := filepath.Join(exeDir, "assets")
 
-	if _, err := os.Stat(assetsDir); os.IsNotExist(err) {
-		os.MkdirAll(assetsDir, 0755)
+	// Enquanto a janela estiver aberta, a linha de comando não altera os dados
+	trava, err := app.TravarDados(dataDir)
+	if errors.Is(err, app.ErrDadosEmUso) {
+		avisarErro("Os dados em " + dataDir + " já estão abertos em outra janela do organizador " +
+			"ou em um comando da linha de comando (ex: tdah-organizer api). Feche-o e tente de novo.")
+		return
 	}
-
-	// Criar pasta init dentro de assets se não existir
-	initDir := filepath.Join(assetsDir, "init")
-	if _, err := os.Stat(initDir); os.IsNotExist(err) {
-		os.MkdirAll(initDir, 0755)
+	if err != nil {
+		panic(err)
 	}
+	defer trava.Soltar()
 
-	// Criar pasta img dentro de assets se não existir
-	imgDir := filepath.Join(assetsDir, "img")
-	if _, err := os.Stat(imgDir); os.IsNotExist(err) {
-		os.MkdirAll(imgDir, 0755)
+	// Criar aplicação (abre o workspace ativo, criando init e img se preciso)
+	appInstance, err := app.NewApp(dataDir)
+	if err != nil {
+		panic(err)
 	}
-
-	// Criar aplicação
-	appInstance := app.NewApp(assetsDir)
+	assetsDir := appInstance.AssetsDir()
+	backend := appInstance.Backend()
 
 	// Criar handlers
-	ideiasHandler := handlers.NewIdeiasHandler(assetsDir)
-	linksHandler := handlers.NewLinksHandler(assetsDir)
-	planejamentoHandler := handlers.NewPlanejamentoHandler(assetsDir)
-	passosHandler := handlers.NewPassosHandler(assetsDir)
-	calendarioHandler := handlers.NewCalendarioHandler(assetsDir)
-	objetivosHandler := handlers.NewObjetivosHandler(assetsDir)
-	backupHandler := handlers.NewBackupHandler(assetsDir)
+	ideiasHandler := handlers.NewIdeiasHandler(assetsDir, backend)
+	linksHandler := handlers.NewLinksHandler(assetsDir, backend)
+	planejamentoHandler := handlers.NewPlanejamentoHandler(assetsDir, backend)
+	passosHandler := handlers.NewPassosHandler(assetsDir, backend)
+	calendarioHandler := handlers.NewCalendarioHandler(assetsDir, backend)
+	objetivosHandler := handlers.NewObjetivosHandler(assetsDir, backend)
+	backupHandler := handlers.NewBackupHandler(assetsDir, backend)
+
+	// Handlers acompanham as trocas de workspace
+	err = appInstance.Registrar(
+		ideiasHandler,
+		linksHandler,
+		planejamentoHandler,
+		passosHandler,
+		calendarioHandler,
+		objetivosHandler,
+		backupHandler,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	// API HTTP local, ligada pelo usuário no app (desligada por padrão)
+	apiServidor := api.NovoServidor(dataDir, api.Modulos{
+		Ideias:       ideiasHandler,
+		Links:        linksHandler,
+		Planejamento: planejamentoHandler,
+		Passos:       passosHandler,
+		Calendario:   calendarioHandler,
+		Objetivos:    objetivosHandler,
+	})
 
 	err = wails.Run(&options.App{
 		Title:     "Organizador TDAH Pro",
@@ -68,7 +101,7 @@ func main() {
 					// Verificar se é uma requisição de imagem
 					if len(r.URL.Path) > 8 && r.URL.Path[:8] == "/assets/" {
 						filename := r.URL.Path[8:]
-						filePath := filepath.Join(assetsDir, filename)
+						filePath := filepath.Join(appInstance.AssetsDir(), filename)
 
 						// Verificar se arquivo existe
 						if _, err := os.Stat(filePath); err == nil {
@@ -93,6 +126,15 @@ func main() {
 			calendarioHandler.Startup(ctx)
 			objetivosHandler.Startup(ctx)
 			backupHandler.Startup(ctx)
+			apiServidor.Startup(ctx)
+		},
+		OnShutdown: func(ctx context.Context) {
+			// Parar a API, os backups periódicos, a atualização dos
+			// calendários assinados e os lembretes antes de fechar o backend
+			apiServidor.Shutdown(ctx)
+			calendarioHandler.Shutdown(ctx)
+			backupHandler.Shutdown(ctx)
+			appInstance.Shutdown(ctx)
 		},
 		Bind: []interface{}{
 			appInstance,
@@ -103,6 +145,7 @@ func main() {
 			calendarioHandler,
 			objetivosHandler,
 			backupHandler,
+			apiServidor,
 		},
 	})
 
@@ -110,3 +153,25 @@ func main() {
 		println("Error:", err.Error())
 	}
 }
+
+// avisarErro mostra uma mensagem de erro sem abrir o app e fecha em seguida
+func avisarErro(mensagem string) {
+	err := wails.Run(&options.App{
+		Title:       "Organizador TDAH Pro",
+		Width:       400,
+		Height:      200,
+		StartHidden: true,
+		AssetServer: &assetserver.Options{Assets: assets},
+		OnDomReady: func(ctx context.Context) {
+			runtime.MessageDialog(ctx, runtime.MessageDialogOptions{
+				Type:    runtime.ErrorDialog,
+				Title:   "Organizador TDAH Pro",
+				Message: mensagem,
+			})
+			runtime.Quit(ctx)
+		},
+	})
+	if err != nil {
+		println("Error:", mensagem)
+	}
+}